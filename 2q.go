@@ -2,7 +2,10 @@
 
 package lru
 
-import "sync"
+import (
+	"fmt"
+	"sync"
+)
 
 const (
 	// default2QRecentRatio is the ratio of the 2Q cache dedicated
@@ -20,6 +23,8 @@ func New2Q[K comparable, V any](maxEntries int, opts ...Option[K, V]) *TwoQueueC
 	return New2QParams(maxEntries, default2QRecentRatio, default2QGhostEntries, opts...)
 }
 
+// New2QParams creates a new TwoQueueCache using the provided
+// parameter values. Ratios outside of [0, 1] are replaced by the defaults.
 func New2QParams[K comparable, V any](maxEntries int, recentRatio, ghostRatio float64, opts ...Option[K, V]) *TwoQueueCache[K, V] {
 	if maxEntries <= 0 {
 		maxEntries = defaultSize
@@ -32,16 +37,66 @@ func New2QParams[K comparable, V any](maxEntries int, recentRatio, ghostRatio fl
 	}
 
 	// Determine the sub-sizes
-	recentEntries := int(float64(maxEntries) * recentRatio)
-	evictEntries := int(float64(maxEntries) * ghostRatio)
+	c, _ := New2QConfig(TwoQueueConfig{
+		Size:       maxEntries,
+		RecentSize: int(float64(maxEntries) * recentRatio),
+		GhostSize:  int(float64(maxEntries) * ghostRatio),
+	}, opts...)
+	return c
+}
+
+// TwoQueueConfig pins the sizes of the internal lists of a 2Q cache.
+type TwoQueueConfig struct {
+	// Size is the capacity of the cache, shared by the recent and
+	// the frequent lists. Either list can hold all of it.
+	Size int
+
+	// RecentSize is the target size of the recent list, which holds
+	// the entries accessed once. Beyond it, the recent list is evicted
+	// before the frequent one.
+	RecentSize int
+
+	// GhostSize is the number of keys evicted from the recent list
+	// remembered, so that adding them again goes to the frequent list.
+	// Zero disables the ghost list.
+	GhostSize int
+}
+
+// Validate checks that the sizes are consistent.
+func (cfg TwoQueueConfig) Validate() error {
+	if cfg.Size <= 0 {
+		return fmt.Errorf("lru: 2Q size %d is not positive", cfg.Size)
+	}
+	if cfg.RecentSize < 0 || cfg.RecentSize > cfg.Size {
+		return fmt.Errorf("lru: 2Q recent size %d out of [0, %d]", cfg.RecentSize, cfg.Size)
+	}
+	if cfg.GhostSize < 0 {
+		return fmt.Errorf("lru: 2Q ghost size %d is negative", cfg.GhostSize)
+	}
+	return nil
+}
 
-	return &TwoQueueCache[K, V]{
-		maxEntries:    maxEntries,
-		recentEntries: recentEntries,
-		recent:        NewUnsafeLru[K, V](maxEntries, opts...),
-		frequent:      NewUnsafeLru[K, V](maxEntries, opts...),
-		recentEvict:   NewUnsafeLru[K, V](evictEntries, opts...),
+// New2QConfig creates a new TwoQueueCache with the exact sizes of cfg,
+// or returns the error of its validation.
+func New2QConfig[K comparable, V any](cfg TwoQueueConfig, opts ...Option[K, V]) (*TwoQueueCache[K, V], error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
+
+	recentEvict := newKeySet[K](cfg.GhostSize)
+	// newKeySet defaults a zero size, a zero ghost size disables the ghosts
+	recentEvict.maxEntries = cfg.GhostSize
+
+	c := &TwoQueueCache[K, V]{
+		maxEntries:    cfg.Size,
+		recentEntries: cfg.RecentSize,
+		recent:        NewUnsafeLru[K, V](cfg.Size, opts...),
+		frequent:      NewUnsafeLru[K, V](cfg.Size, opts...),
+		recentEvict:   recentEvict,
+	}
+	c.promoter = newPromoter(c.recent)
+	c.strict = c.recent.(*unsafeCache[K, V]).strict
+	return c, nil
 }
 
 // TwoQueueCache is a thread-safe fixed size 2Q cache.
@@ -59,16 +114,51 @@ type TwoQueueCache[K comparable, V any] struct {
 
 	recent      Lru[K, V]
 	frequent    Lru[K, V]
-	recentEvict Lru[K, V]
+	recentEvict *keySet[K]
+
+	// promoter moves the entries between the lists
+	promoter promoter[K, V]
+
+	// strict is set WithStrictLRU, Contains and Peek take the write lock.
+	strict bool
+
+	stats TwoQueueStats
 
 	sync.RWMutex
 }
 
+// TwoQueueStats breaks the lookups of a TwoQueueCache down by the list
+// which satisfied them, so that the behavior of the policy is observable.
+type TwoQueueStats struct {
+	RecentHits      uint64 // Get hits in the recent list, promoting the entry
+	FrequentHits    uint64 // Get hits in the frequent list
+	Misses          uint64 // Get misses
+	GhostPromotions uint64 // Adds of a key recently evicted, to the frequent list
+}
+
+// Hits returns the number of Get hits.
+func (s TwoQueueStats) Hits() uint64 {
+	return s.RecentHits + s.FrequentHits
+}
+
+// Stats returns the counters of the cache since its creation.
+func (c *TwoQueueCache[K, V]) Stats() TwoQueueStats {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.stats
+}
+
 // Add a value to the cache.
 func (c *TwoQueueCache[K, V]) Add(key K, value V) {
 	c.Lock()
 	defer c.Unlock()
 
+	c.add(key, value)
+}
+
+// add adds a value to the cache, the lock must be held.
+func (c *TwoQueueCache[K, V]) add(key K, value V) {
 	// Check if the value is frequently used already,
 	// and just update the value
 	if c.frequent.Contains(key) {
@@ -79,7 +169,7 @@ func (c *TwoQueueCache[K, V]) Add(key K, value V) {
 	// Check if the value is recently used, and promote
 	// the value into the frequent list
 	if c.recent.Contains(key) {
-		c.recent.Remove(key)
+		c.promoter.move(c.recent, key)
 		c.frequent.Add(key, value)
 		return
 	}
@@ -87,9 +177,10 @@ func (c *TwoQueueCache[K, V]) Add(key K, value V) {
 	// If the value was recently evicted, add it to the
 	// frequently used list
 	if c.recentEvict.Contains(key) {
+		c.stats.GhostPromotions++
 		c.ensureSpace(true)
 		c.recentEvict.Remove(key)
-		c.frequent.Add(key, value)
+		c.promoter.readmit(c.frequent, key, value)
 		return
 	}
 
@@ -103,28 +194,69 @@ func (c *TwoQueueCache[K, V]) Get(key K) (value V, ok bool) {
 	c.Lock()
 	defer c.Unlock()
 
+	return c.get(key)
+}
+
+// GetOrAdd returns the value of the key if it is in the cache, like Get,
+// and otherwise adds value, under a single lock acquisition. loaded tells
+// whether actual was found in the cache.
+func (c *TwoQueueCache[K, V]) GetOrAdd(key K, value V) (actual V, loaded bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	if actual, loaded = c.get(key); loaded {
+		return actual, true
+	}
+	c.add(key, value)
+	return value, false
+}
+
+// ContainsOrAdd checks if a key is in the cache without updating the
+// recent-ness or frequency, and otherwise adds the value, under a single
+// lock acquisition. Returns whether found and whether an eviction occurred.
+func (c *TwoQueueCache[K, V]) ContainsOrAdd(key K, value V) (ok, evicted bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.frequent.Contains(key) || c.recent.Contains(key) {
+		return true, false
+	}
+	n := c.recent.Len() + c.frequent.Len()
+	c.add(key, value)
+	// The new key did not grow the cache if another one left it
+	return false, c.recent.Len()+c.frequent.Len() <= n
+}
+
+// get looks up a key's value from the cache, the lock must be held.
+func (c *TwoQueueCache[K, V]) get(key K) (value V, ok bool) {
 	// Check if this is a frequent value
 	if value, ok = c.frequent.Get(key); ok {
+		c.stats.FrequentHits++
 		return
 	}
 
 	// If the value is contained in recent, then we
 	// promote it to frequent
-	if value, ok = c.recent.Peek(key); ok {
-		c.recent.Remove(key)
-		c.frequent.Add(key, value)
+	if value, ok = c.promoter.promote(c.recent, c.frequent, key); ok {
+		c.stats.RecentHits++
 		return
 	}
 
 	// No hit
+	c.stats.Misses++
 	return value, false
 }
 
 // Contains is used to check if the cache contains a key
 // without updating recency or frequency.
 func (c *TwoQueueCache[K, V]) Contains(key K) (ok bool) {
-	c.RLock()
-	defer c.RUnlock()
+	if c.strict {
+		c.Lock()
+		defer c.Unlock()
+	} else {
+		c.RLock()
+		defer c.RUnlock()
+	}
 
 	return c.frequent.Contains(key) || c.recent.Contains(key)
 }
@@ -132,8 +264,13 @@ func (c *TwoQueueCache[K, V]) Contains(key K) (ok bool) {
 // Peek is used to inspect the cache value of a key
 // without updating recency or frequency.
 func (c *TwoQueueCache[K, V]) Peek(key K) (value V, ok bool) {
-	c.RLock()
-	defer c.RUnlock()
+	if c.strict {
+		c.Lock()
+		defer c.Unlock()
+	} else {
+		c.RLock()
+		defer c.RUnlock()
+	}
 
 	if value, ok = c.frequent.Peek(key); ok {
 		return
@@ -153,12 +290,25 @@ func (c *TwoQueueCache[K, V]) Remove(key K) (ok bool) {
 		return
 	}
 	if c.recentEvict.Remove(key) {
+		c.promoter.forget(key)
 		return
 	}
 
 	return
 }
 
+// Pop atomically retrieves and removes the entry of the key, so that
+// concurrent callers consume each entry exactly once.
+func (c *TwoQueueCache[K, V]) Pop(key K) (value V, ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	if value, ok = c.frequent.RemoveGet(key); ok {
+		return value, true
+	}
+	return c.recent.RemoveGet(key)
+}
+
 // Keys returns a slice of the keys in the cache.
 // The frequently used keys are first in the returned slice.
 func (c *TwoQueueCache[K, V]) Keys() []K {
@@ -186,6 +336,7 @@ func (c *TwoQueueCache[K, V]) Clear() {
 	c.recent.Clear()
 	c.frequent.Clear()
 	c.recentEvict.Clear()
+	c.promoter.clear()
 }
 
 // ensureSpace is used to ensure we have space in the cache
@@ -198,11 +349,9 @@ func (c *TwoQueueCache[K, V]) ensureSpace(recentEvict bool) {
 	}
 
 	// If the recent buffer is larger than
-	// the target, evict from there
-	if recentLen > 0 && (recentLen > c.recentEntries || (recentLen == c.recentEntries && !recentEvict)) {
-		k, _, _ := c.recent.RemoveOldest()
-		var v V
-		c.recentEvict.Add(k, v)
+	// the target, or the only one, evict from there
+	if recentLen > 0 && (recentLen > c.recentEntries || (recentLen == c.recentEntries && !recentEvict) || freqLen == 0) {
+		c.promoter.retire(c.recent, c.recentEvict)
 		return
 	}
 
@@ -279,3 +279,126 @@ func Test2Q_Peek(t *testing.T) {
 		t.Errorf("should not have updated recent-ness of 1")
 	}
 }
+
+func Test2Q_Config(t *testing.T) {
+	for _, cfg := range []TwoQueueConfig{
+		{Size: 0},
+		{Size: 4, RecentSize: -1},
+		{Size: 4, RecentSize: 5},
+		{Size: 4, GhostSize: -1},
+	} {
+		if _, err := New2QConfig[int, int](cfg); err == nil {
+			t.Fatalf("%+v should be invalid", cfg)
+		}
+	}
+
+	l, err := New2QConfig[int, int](TwoQueueConfig{Size: 4, RecentSize: 1, GhostSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.maxEntries != 4 || l.recentEntries != 1 || l.recentEvict.maxEntries != 2 {
+		t.Fatalf("sizes not pinned: %v %v %v", l.maxEntries, l.recentEntries, l.recentEvict.maxEntries)
+	}
+}
+
+func Test2Q_DegenerateRatios(t *testing.T) {
+	for _, ratios := range [][2]float64{{0, 0}, {0, 1}, {1, 0}, {1, 1}} {
+		l := New2QParams[int, int](8, ratios[0], ratios[1])
+		for i := 0; i < 1000; i++ {
+			key := rand.Intn(32)
+			switch rand.Intn(3) {
+			case 0:
+				l.Add(key, key)
+			case 1:
+				l.Get(key)
+			case 2:
+				l.Remove(key)
+			}
+			if l.Len() > 8 {
+				t.Fatalf("ratios %v: bad len %v", ratios, l.Len())
+			}
+			if l.recentEvict.Len() > l.recentEvict.maxEntries {
+				t.Fatalf("ratios %v: bad ghost len %v", ratios, l.recentEvict.Len())
+			}
+		}
+	}
+
+	// Without ghosts, an evicted key is added back to the recent list
+	l := New2QParams[int, int](2, 0.5, 0)
+	l.Add(1, 1)
+	l.Add(2, 2)
+	l.Add(3, 3)
+	l.Add(1, 1)
+	if l.recentEvict.Len() != 0 || l.frequent.Len() != 0 {
+		t.Fatalf("ghost promotion without ghosts: %v", l.frequent.Keys())
+	}
+}
+
+func Test2Q_Stats(t *testing.T) {
+	l := New2Q[int, int](4)
+	l.Add(1, 1)
+	l.Get(1) // Recent hit, promoted to frequent
+	l.Get(1) // Frequent hit
+	l.Get(2) // Miss
+	for i := 2; i < 7; i++ {
+		l.Add(i, i)
+	}
+	l.Add(2, 2) // Recently evicted
+
+	stats := l.Stats()
+	expected := TwoQueueStats{RecentHits: 1, FrequentHits: 1, Misses: 1, GhostPromotions: 1}
+	if stats != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, stats)
+	}
+	if stats.Hits() != 2 {
+		t.Fatalf("Expected %v, got %v", 2, stats.Hits())
+	}
+}
+
+func Test2Q_GetOrAdd(t *testing.T) {
+	l := New2Q[int, int](4)
+	if v, loaded := l.GetOrAdd(1, 1); loaded || v != 1 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, false, v, loaded)
+	}
+	if v, loaded := l.GetOrAdd(1, 10); !loaded || v != 1 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, true, v, loaded)
+	}
+	if l.frequent.Len() != 1 || l.recent.Len() != 0 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, 0, l.frequent.Len(), l.recent.Len())
+	}
+}
+
+func Test2Q_ContainsOrAdd(t *testing.T) {
+	l := New2Q[int, int](4)
+	if ok, evicted := l.ContainsOrAdd(1, 1); ok || evicted {
+		t.Fatalf("Expected %v, %v, got %v, %v", false, false, ok, evicted)
+	}
+	if ok, evicted := l.ContainsOrAdd(1, 10); !ok || evicted {
+		t.Fatalf("Expected %v, %v, got %v, %v", true, false, ok, evicted)
+	}
+	if v, _ := l.Peek(1); v != 1 || l.recent.Len() != 1 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, 1, v, l.recent.Len())
+	}
+	for i := 2; i <= 4; i++ {
+		l.Add(i, i)
+	}
+	if ok, evicted := l.ContainsOrAdd(5, 5); ok || !evicted {
+		t.Fatalf("Expected %v, %v, got %v, %v", false, true, ok, evicted)
+	}
+}
+
+func Test2Q_Pop(t *testing.T) {
+	l := New2Q[int, int](4)
+	l.Add(1, 1)
+	l.Add(2, 2)
+	l.Get(2)
+	if v, ok := l.Pop(1); !ok || v != 1 {
+		t.Fatalf("Expected %v, got %v", 1, v)
+	}
+	if v, ok := l.Pop(2); !ok || v != 2 {
+		t.Fatalf("Expected %v, got %v", 2, v)
+	}
+	if _, ok := l.Pop(2); ok || l.Len() != 0 {
+		t.Fatalf("Expected %v, %v, got %v, %v", false, 0, ok, l.Len())
+	}
+}
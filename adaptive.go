@@ -0,0 +1,257 @@
+package lru
+
+// NewARCPolicy returns a policy evicting like the ARCCache of size entries,
+// balancing the keys accessed once and the keys accessed several times
+// according to the keys recently evicted from either.
+func NewARCPolicy[K comparable](size int) Policy[K] {
+	if size <= 0 {
+		size = defaultSize
+	}
+	return &arcPolicy[K]{
+		size: size,
+		t1:   newKeySet[K](size),
+		b1:   newKeySet[K](size),
+		t2:   newKeySet[K](size),
+		b2:   newKeySet[K](size),
+	}
+}
+
+// New2QPolicy returns a policy evicting like the TwoQueueCache of size
+// entries created by New2Q.
+func New2QPolicy[K comparable](size int) Policy[K] {
+	if size <= 0 {
+		size = defaultSize
+	}
+	return &twoQueuePolicy[K]{
+		recentSize:  int(float64(size) * default2QRecentRatio),
+		recent:      newKeySet[K](size),
+		frequent:    newKeySet[K](size),
+		recentEvict: newKeySet[K](int(float64(size) * default2QGhostEntries)),
+	}
+}
+
+// pendingKey is the key inserted last, kept out of the lists of the ARC
+// and 2Q policies until the cache made room for it, since they choose
+// their victim before adding a key.
+type pendingKey[K comparable] struct {
+	key      K
+	frequent bool // Re-admitted from a ghost list
+	ok       bool
+}
+
+// victimKey is the key last returned by Victim, which goes
+// to a ghost list once removed.
+type victimKey[K comparable] struct {
+	key    K
+	recent bool
+	ok     bool
+}
+
+func (v *victimKey[K]) set(key K, recent bool) (K, bool) {
+	*v = victimKey[K]{key: key, recent: recent, ok: true}
+	return key, true
+}
+
+// next sets the victim to the oldest key not skipped of the recent list
+// if chosen, of the frequent list otherwise, then of the other list, or to
+// the pending key once both are empty.
+func (v *victimKey[K]) next(recentKeys, frequentKeys *keySet[K], recent bool, pending pendingKey[K], skip func(key K) bool) (key K, ok bool) {
+	first, second := frequentKeys, recentKeys
+	if recent {
+		first, second = recentKeys, frequentKeys
+	}
+	if key, ok = first.oldestExcept(skip); ok {
+		return v.set(key, recent)
+	}
+	if key, ok = second.oldestExcept(skip); ok {
+		return v.set(key, !recent)
+	}
+	if pending.ok && (skip == nil || !skip(pending.key)) {
+		return pending.key, true
+	}
+	return key, false
+}
+
+// arcPolicy is the ARC algorithm of ARCCache over the keys only.
+type arcPolicy[K comparable] struct {
+	size int
+	p    int // Target size of t1
+
+	t1, b1 *keySet[K] // Keys accessed once, and their ghosts
+	t2, b2 *keySet[K] // Keys accessed several times, and their ghosts
+
+	pending pendingKey[K]
+	victim  victimKey[K]
+	b2Hit   bool
+}
+
+func (p *arcPolicy[K]) flush() {
+	if !p.pending.ok {
+		return
+	}
+	if p.pending.frequent {
+		p.t2.Add(p.pending.key)
+	} else {
+		p.t1.Add(p.pending.key)
+	}
+	p.pending = pendingKey[K]{}
+}
+
+func (p *arcPolicy[K]) Insert(key K) {
+	p.flush()
+	p.b2Hit = false
+	switch {
+	case p.b1.Remove(key):
+		// t1 is too small, grow its target
+		delta := 1
+		if p.b2.Len() > p.b1.Len()+1 {
+			delta = p.b2.Len() / (p.b1.Len() + 1)
+		}
+		if p.p += delta; p.p > p.size {
+			p.p = p.size
+		}
+		p.pending = pendingKey[K]{key: key, frequent: true, ok: true}
+	case p.b2.Remove(key):
+		// t2 is too small, shrink the target of t1
+		delta := 1
+		if p.b1.Len() > p.b2.Len()+1 {
+			delta = p.b1.Len() / (p.b2.Len() + 1)
+		}
+		if p.p -= delta; p.p < 0 {
+			p.p = 0
+		}
+		p.b2Hit = true
+		p.pending = pendingKey[K]{key: key, frequent: true, ok: true}
+	default:
+		// Keep the size of the ghost lists trim
+		if p.b1.Len() > p.size-p.p {
+			p.b1.RemoveOldest()
+		}
+		if p.b2.Len() > p.p {
+			p.b2.RemoveOldest()
+		}
+		p.pending = pendingKey[K]{key: key, ok: true}
+	}
+}
+
+func (p *arcPolicy[K]) Access(key K) {
+	p.flush()
+	if p.t1.Remove(key) {
+		p.t2.Add(key)
+		return
+	}
+	if p.t2.Contains(key) {
+		p.t2.Add(key)
+	}
+}
+
+func (p *arcPolicy[K]) Remove(key K) {
+	if p.pending.ok && p.pending.key == key {
+		p.pending = pendingKey[K]{}
+		return
+	}
+	evicted := p.victim.ok && p.victim.key == key
+	p.victim = victimKey[K]{}
+	switch {
+	case p.t1.Remove(key):
+		if evicted {
+			p.b1.Add(key)
+		}
+	case p.t2.Remove(key):
+		if evicted {
+			p.b2.Add(key)
+		}
+	}
+}
+
+func (p *arcPolicy[K]) Victim() (key K, ok bool) {
+	return p.NextVictim(nil)
+}
+
+// NextVictim evicts from the list chosen by ARC, then from the other one.
+func (p *arcPolicy[K]) NextVictim(skip func(key K) bool) (key K, ok bool) {
+	t1Len := p.t1.Len()
+	recent := t1Len > 0 && (t1Len > p.p || (t1Len == p.p && p.b2Hit) || p.t2.Len() == 0)
+	return p.victim.next(p.t1, p.t2, recent, p.pending, skip)
+}
+
+func (p *arcPolicy[K]) Clear() {
+	p.p = 0
+	p.t1.Clear()
+	p.b1.Clear()
+	p.t2.Clear()
+	p.b2.Clear()
+	p.pending = pendingKey[K]{}
+	p.victim = victimKey[K]{}
+	p.b2Hit = false
+}
+
+// twoQueuePolicy is the 2Q algorithm of TwoQueueCache over the keys only.
+type twoQueuePolicy[K comparable] struct {
+	recentSize int
+
+	recent      *keySet[K] // Keys accessed once
+	frequent    *keySet[K] // Keys accessed several times
+	recentEvict *keySet[K] // Ghosts of the keys evicted from recent
+
+	pending pendingKey[K]
+	victim  victimKey[K]
+}
+
+func (p *twoQueuePolicy[K]) flush() {
+	if !p.pending.ok {
+		return
+	}
+	if p.pending.frequent {
+		p.frequent.Add(p.pending.key)
+	} else {
+		p.recent.Add(p.pending.key)
+	}
+	p.pending = pendingKey[K]{}
+}
+
+func (p *twoQueuePolicy[K]) Insert(key K) {
+	p.flush()
+	p.pending = pendingKey[K]{key: key, frequent: p.recentEvict.Remove(key), ok: true}
+}
+
+func (p *twoQueuePolicy[K]) Access(key K) {
+	p.flush()
+	if p.recent.Remove(key) || p.frequent.Contains(key) {
+		p.frequent.Add(key)
+	}
+}
+
+func (p *twoQueuePolicy[K]) Remove(key K) {
+	if p.pending.ok && p.pending.key == key {
+		p.pending = pendingKey[K]{}
+		return
+	}
+	evicted := p.victim.ok && p.victim.key == key && p.victim.recent
+	p.victim = victimKey[K]{}
+	if p.recent.Remove(key) && evicted {
+		p.recentEvict.Add(key)
+		return
+	}
+	p.frequent.Remove(key)
+}
+
+func (p *twoQueuePolicy[K]) Victim() (key K, ok bool) {
+	return p.NextVictim(nil)
+}
+
+// NextVictim evicts from the list chosen by 2Q, then from the other one.
+func (p *twoQueuePolicy[K]) NextVictim(skip func(key K) bool) (key K, ok bool) {
+	recentLen := p.recent.Len()
+	readmitted := p.pending.ok && p.pending.frequent
+	recent := recentLen > 0 && (recentLen > p.recentSize || (recentLen == p.recentSize && !readmitted) || p.frequent.Len() == 0)
+	return p.victim.next(p.recent, p.frequent, recent, p.pending, skip)
+}
+
+func (p *twoQueuePolicy[K]) Clear() {
+	p.recent.Clear()
+	p.frequent.Clear()
+	p.recentEvict.Clear()
+	p.pending = pendingKey[K]{}
+	p.victim = victimKey[K]{}
+}
@@ -0,0 +1,59 @@
+package lru
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func sortedKeys(keys []int) []int {
+	sort.Ints(keys)
+	return keys
+}
+
+func TestNewARCPolicy(t *testing.T) {
+	arc := NewARC[int, int](32)
+	l := NewUnsafeLru[int, int](32, WithPolicy[int, int](func() Policy[int] {
+		return NewARCPolicy[int](32)
+	}))
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		k := r.Intn(64)
+		if r.Intn(2) == 0 {
+			arc.Add(k, k)
+			l.Add(k, k)
+		} else {
+			arc.Get(k)
+			l.Get(k)
+		}
+	}
+	expected, got := sortedKeys(arc.Keys()), sortedKeys(l.Keys())
+	if !reflect.DeepEqual(expected, got) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestNew2QPolicy(t *testing.T) {
+	q := New2Q[int, int](32)
+	l := NewUnsafeLru[int, int](32, WithPolicy[int, int](func() Policy[int] {
+		return New2QPolicy[int](32)
+	}))
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		k := r.Intn(64)
+		if r.Intn(2) == 0 {
+			q.Add(k, k)
+			l.Add(k, k)
+		} else {
+			q.Get(k)
+			l.Get(k)
+		}
+	}
+	expected, got := sortedKeys(q.Keys()), sortedKeys(l.Keys())
+	if !reflect.DeepEqual(expected, got) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,180 @@
+package lru
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	// approxSampleSize is the number of entries sampled per eviction.
+	approxSampleSize = 5
+
+	// approxAccessBit is set in the age of an entry when it is used.
+	approxAccessBit = 0x80
+)
+
+// NewApprox creates an ApproxCache.
+func NewApprox[K comparable, V any](maxEntries int) *ApproxCache[K, V] {
+	if maxEntries <= 0 {
+		maxEntries = defaultSize
+	}
+	return &ApproxCache[K, V]{
+		maxEntries: maxEntries,
+		slots:      make([]approxEntry[K, V], 0, maxEntries),
+		index:      make(map[K]int, maxEntries),
+		rand:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+var _ Cacher[int, int] = (*ApproxCache[int, int])(nil)
+
+// ApproxCache is a thread-safe fixed size cache approximating LRU.
+// Instead of a list, recency is tracked with an 8-bit aging counter per
+// entry: using an entry sets its high bit, and every maxEntries operations
+// all the counters are halved. Evictions remove the least recently used of
+// a small random sample of entries. Without list pointers it is leaner than
+// the standard LRU cache, which matters for tens of millions of small entries.
+type ApproxCache[K comparable, V any] struct {
+	maxEntries int
+
+	slots []approxEntry[K, V]
+	index map[K]int
+
+	// ops counts the operations since the counters were last halved.
+	ops  int
+	rand *rand.Rand
+
+	sync.Mutex
+}
+
+// approxEntry is an entry of the ApproxCache.
+type approxEntry[K comparable, V any] struct {
+	key   K
+	value V
+	age   uint8
+}
+
+// Add a value to the cache. Returns true if an eviction occurred.
+func (c *ApproxCache[K, V]) Add(key K, value V) (evicted bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.tick()
+	if i, ok := c.index[key]; ok {
+		c.slots[i].value = value
+		c.slots[i].age |= approxAccessBit
+		return false
+	}
+
+	if len(c.slots) >= c.maxEntries {
+		c.removeSlot(c.victim())
+		evicted = true
+	}
+	c.index[key] = len(c.slots)
+	c.slots = append(c.slots, approxEntry[K, V]{key: key, value: value, age: approxAccessBit})
+	return evicted
+}
+
+// Get looks up a key's value from the cache
+func (c *ApproxCache[K, V]) Get(key K) (value V, ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.tick()
+	i, ok := c.index[key]
+	if !ok {
+		return value, false
+	}
+	c.slots[i].age |= approxAccessBit
+	return c.slots[i].value, true
+}
+
+// Contains checks if a key is in the cache, without updating its age.
+func (c *ApproxCache[K, V]) Contains(key K) (ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	_, ok = c.index[key]
+	return ok
+}
+
+// Peek returns the key value without updating its age.
+func (c *ApproxCache[K, V]) Peek(key K) (value V, ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	i, ok := c.index[key]
+	if !ok {
+		return value, false
+	}
+	return c.slots[i].value, true
+}
+
+// Remove removes the provided key from the cache, returning if the
+// key was contained.
+func (c *ApproxCache[K, V]) Remove(key K) (ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	i, ok := c.index[key]
+	if ok {
+		c.removeSlot(i)
+	}
+	return ok
+}
+
+// Len returns the number of items in the cache.
+func (c *ApproxCache[K, V]) Len() int {
+	c.Lock()
+	defer c.Unlock()
+
+	return len(c.slots)
+}
+
+// Clear is used to completely clear the cache
+func (c *ApproxCache[K, V]) Clear() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.slots = c.slots[:0]
+	c.index = make(map[K]int, c.maxEntries)
+	c.ops = 0
+}
+
+// tick counts an operation, and halves all the ages every maxEntries
+// operations so that old accesses weigh less than recent ones.
+func (c *ApproxCache[K, V]) tick() {
+	if c.ops++; c.ops < c.maxEntries {
+		return
+	}
+	c.ops = 0
+	for i := range c.slots {
+		c.slots[i].age >>= 1
+	}
+}
+
+// victim returns the slot of the least recently used entry of a random
+// sample, the one with the lowest age.
+func (c *ApproxCache[K, V]) victim() int {
+	victim := c.rand.Intn(len(c.slots))
+	for n := 1; n < approxSampleSize; n++ {
+		i := c.rand.Intn(len(c.slots))
+		if c.slots[i].age < c.slots[victim].age {
+			victim = i
+		}
+	}
+	return victim
+}
+
+// removeSlot removes an entry by moving the last one into its slot.
+func (c *ApproxCache[K, V]) removeSlot(i int) {
+	last := len(c.slots) - 1
+	delete(c.index, c.slots[i].key)
+	if i != last {
+		c.slots[i] = c.slots[last]
+		c.index[c.slots[i].key] = i
+	}
+	c.slots[last] = approxEntry[K, V]{}
+	c.slots = c.slots[:last]
+}
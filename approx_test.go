@@ -0,0 +1,85 @@
+package lru
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func BenchmarkApprox_Rand(b *testing.B) {
+	l := NewApprox[int64, int64](8192)
+
+	trace := make([]int64, b.N*2)
+	for i := 0; i < b.N*2; i++ {
+		trace[i] = rand.Int63() % 32768
+	}
+
+	b.ResetTimer()
+
+	var hit, miss int
+	for i := 0; i < 2*b.N; i++ {
+		if i%2 == 0 {
+			l.Add(trace[i], trace[i])
+		} else {
+			_, ok := l.Get(trace[i])
+			if ok {
+				hit++
+			} else {
+				miss++
+			}
+		}
+	}
+	b.Logf("hit: %d miss: %d ratio: %f", hit, miss, float64(hit)/float64(miss))
+}
+
+func TestApprox_RandomOps(t *testing.T) {
+	size := 128
+	l := NewApprox[int64, int64](size)
+
+	n := 200000
+	for i := 0; i < n; i++ {
+		key := rand.Int63() % 512
+		switch rand.Int63() % 3 {
+		case 0:
+			l.Add(key, key)
+		case 1:
+			if v, ok := l.Get(key); ok && v != key {
+				t.Fatalf("Expected %v, got %v", key, v)
+			}
+		case 2:
+			l.Remove(key)
+		}
+
+		if l.Len() > size {
+			t.Fatalf("bad: len %d", l.Len())
+		}
+		if len(l.index) != len(l.slots) {
+			t.Fatalf("bad: index %d slots %d", len(l.index), len(l.slots))
+		}
+	}
+}
+
+func TestApprox_KeepsHotEntries(t *testing.T) {
+	size := 100
+	l := NewApprox[int, int](size)
+	l.rand = rand.New(rand.NewSource(1))
+
+	// Keys below 10 are used constantly, the others once
+	for i := 0; i < 10*size; i++ {
+		l.Add(size+i, i)
+		l.Get(i % 10)
+		if i < 10 {
+			l.Add(i, i)
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		if !l.Contains(i) {
+			t.Fatalf("hot key %d evicted", i)
+		}
+	}
+
+	l.Clear()
+	if l.Len() != 0 {
+		t.Fatalf("Expected %v, got %v", 0, l.Len())
+	}
+}
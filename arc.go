@@ -8,14 +8,17 @@ func NewARC[K comparable, V any](maxEntries int, opts ...Option[K, V]) *ARCCache
 	if maxEntries <= 0 {
 		maxEntries = defaultSize
 	}
-	return &ARCCache[K, V]{
+	c := &ARCCache[K, V]{
 		maxEntries: maxEntries,
 		p:          0,
 		t1:         NewUnsafeLru[K, V](maxEntries, opts...),
-		b1:         NewUnsafeLru[K, V](maxEntries, opts...),
+		b1:         newKeySet[K](maxEntries),
 		t2:         NewUnsafeLru[K, V](maxEntries, opts...),
-		b2:         NewUnsafeLru[K, V](maxEntries, opts...),
+		b2:         newKeySet[K](maxEntries),
 	}
+	c.promoter = newPromoter(c.t1)
+	c.strict = c.t1.(*unsafeCache[K, V]).strict
+	return c
 }
 
 // ARCCache is a thread-safe fixed size Adaptive Replacement Cache (ARC).
@@ -30,24 +33,63 @@ type ARCCache[K comparable, V any] struct {
 	maxEntries int // MaxEntries is the total capacity of the cache
 	p          int // P is the dynamic preference towards T1 or T2
 
-	t1 Lru[K, V] // T1 is the LRU for recently accessed items
-	b1 Lru[K, V] // B1 is the LRU for evictions from t1
+	t1 Lru[K, V]  // T1 is the LRU for recently accessed items
+	b1 *keySet[K] // B1 is the LRU for evictions from t1
 
-	t2 Lru[K, V] // T2 is the LRU for frequently accessed items
-	b2 Lru[K, V] // B2 is the LRU for evictions from t2
+	t2 Lru[K, V]  // T2 is the LRU for frequently accessed items
+	b2 *keySet[K] // B2 is the LRU for evictions from t2
+
+	// promoter moves the entries between the lists
+	promoter promoter[K, V]
+
+	// strict is set WithStrictLRU, Contains and Peek take the write lock.
+	strict bool
+
+	stats ARCStats
 
 	sync.RWMutex
 }
 
+// ARCStats breaks the lookups of an ARCCache down by the list which
+// satisfied them, so that the adaptation of the cache is observable.
+type ARCStats struct {
+	T1Hits uint64 // Get hits in T1, promoting the entry to T2
+	T2Hits uint64 // Get hits in T2
+	Misses uint64 // Get misses
+	B1Hits uint64 // Adds of a key recently evicted from T1, growing P
+	B2Hits uint64 // Adds of a key recently evicted from T2, shrinking P
+	P      int    // Current target size of T1
+}
+
+// Hits returns the number of Get hits.
+func (s ARCStats) Hits() uint64 {
+	return s.T1Hits + s.T2Hits
+}
+
+// Stats returns the counters of the cache since its creation.
+func (c *ARCCache[K, V]) Stats() ARCStats {
+	c.RLock()
+	defer c.RUnlock()
+
+	stats := c.stats
+	stats.P = c.p
+	return stats
+}
+
 // Add a value to the cache
 func (c *ARCCache[K, V]) Add(key K, value V) {
 	c.Lock()
 	defer c.Unlock()
 
+	c.add(key, value)
+}
+
+// add adds a value to the cache, the lock must be held.
+func (c *ARCCache[K, V]) add(key K, value V) {
 	// Check if the value is contained in T1 (recent), and potentially
 	// promote it to frequent T2
 	if c.t1.Contains(key) {
-		c.t1.Remove(key)
+		c.promoter.move(c.t1, key)
 		c.t2.Add(key, value)
 		return
 	}
@@ -61,6 +103,7 @@ func (c *ARCCache[K, V]) Add(key K, value V) {
 	// Check if this value was recently evicted as part of the
 	// recently used list
 	if c.b1.Contains(key) {
+		c.stats.B1Hits++
 		// T1 set is too small, increase P appropriately
 		delta := 1
 		b1Len := c.b1.Len()
@@ -83,13 +126,14 @@ func (c *ARCCache[K, V]) Add(key K, value V) {
 		c.b1.Remove(key)
 
 		// Add the key to the frequently used list
-		c.t2.Add(key, value)
+		c.promoter.readmit(c.t2, key, value)
 		return
 	}
 
 	// Check if this value was recently evicted as part of the
 	// frequently used list
 	if c.b2.Contains(key) {
+		c.stats.B2Hits++
 		// T2 set is too small, decrease P appropriately
 		delta := 1
 		b1Len := c.b1.Len()
@@ -112,7 +156,7 @@ func (c *ARCCache[K, V]) Add(key K, value V) {
 		c.b2.Remove(key)
 
 		// Add the key to the frequently used list
-		c.t2.Add(key, value)
+		c.promoter.readmit(c.t2, key, value)
 		return
 	}
 
@@ -123,10 +167,14 @@ func (c *ARCCache[K, V]) Add(key K, value V) {
 
 	// Keep the size of the ghost buffers trim
 	if c.b1.Len() > c.maxEntries-c.p {
-		c.b1.RemoveOldest()
+		if k, ok := c.b1.RemoveOldest(); ok {
+			c.promoter.forget(k)
+		}
 	}
 	if c.b2.Len() > c.p {
-		c.b2.RemoveOldest()
+		if k, ok := c.b2.RemoveOldest(); ok {
+			c.promoter.forget(k)
+		}
 	}
 
 	// Add to the recently seen list
@@ -138,28 +186,69 @@ func (c *ARCCache[K, V]) Get(key K) (value V, ok bool) {
 	c.Lock()
 	defer c.Unlock()
 
+	return c.get(key)
+}
+
+// GetOrAdd returns the value of the key if it is in the cache, like Get,
+// and otherwise adds value, under a single lock acquisition. loaded tells
+// whether actual was found in the cache.
+func (c *ARCCache[K, V]) GetOrAdd(key K, value V) (actual V, loaded bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	if actual, loaded = c.get(key); loaded {
+		return actual, true
+	}
+	c.add(key, value)
+	return value, false
+}
+
+// ContainsOrAdd checks if a key is in the cache without updating the
+// recent-ness or frequency, and otherwise adds the value, under a single
+// lock acquisition. Returns whether found and whether an eviction occurred.
+func (c *ARCCache[K, V]) ContainsOrAdd(key K, value V) (ok, evicted bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.t1.Contains(key) || c.t2.Contains(key) {
+		return true, false
+	}
+	n := c.t1.Len() + c.t2.Len()
+	c.add(key, value)
+	// The new key did not grow the cache if another one left it
+	return false, c.t1.Len()+c.t2.Len() <= n
+}
+
+// get looks up a key's value from the cache, the lock must be held.
+func (c *ARCCache[K, V]) get(key K) (value V, ok bool) {
 	// If the value is contained in T1 (recent), then
 	// promote it to T2 (frequent)
-	if value, ok = c.t1.Peek(key); ok {
-		c.t1.Remove(key)
-		c.t2.Add(key, value)
+	if value, ok = c.promoter.promote(c.t1, c.t2, key); ok {
+		c.stats.T1Hits++
 		return
 	}
 
 	// Check if the value is contained in T2 (frequent)
 	if value, ok = c.t2.Get(key); ok {
+		c.stats.T2Hits++
 		return
 	}
 
 	// No hit
+	c.stats.Misses++
 	return value, false
 }
 
 // Contains is used to check if the cache contains a key
 // without updating recency or frequency.
 func (c *ARCCache[K, V]) Contains(key K) (ok bool) {
-	c.RLock()
-	defer c.RUnlock()
+	if c.strict {
+		c.Lock()
+		defer c.Unlock()
+	} else {
+		c.RLock()
+		defer c.RUnlock()
+	}
 
 	return c.t1.Contains(key) || c.t2.Contains(key)
 }
@@ -167,8 +256,13 @@ func (c *ARCCache[K, V]) Contains(key K) (ok bool) {
 // Peek is used to inspect the cache value of a key
 // without updating recency or frequency.
 func (c *ARCCache[K, V]) Peek(key K) (value V, ok bool) {
-	c.RLock()
-	defer c.RUnlock()
+	if c.strict {
+		c.Lock()
+		defer c.Unlock()
+	} else {
+		c.RLock()
+		defer c.RUnlock()
+	}
 
 	if value, ok = c.t1.Peek(key); ok {
 		return
@@ -188,15 +282,29 @@ func (c *ARCCache[K, V]) Remove(key K) (ok bool) {
 		return
 	}
 	if c.b1.Remove(key) {
+		c.promoter.forget(key)
 		return
 	}
 	if c.b2.Remove(key) {
+		c.promoter.forget(key)
 		return
 	}
 
 	return
 }
 
+// Pop atomically retrieves and removes the entry of the key, so that
+// concurrent callers consume each entry exactly once.
+func (c *ARCCache[K, V]) Pop(key K) (value V, ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	if value, ok = c.t1.RemoveGet(key); ok {
+		return value, true
+	}
+	return c.t2.RemoveGet(key)
+}
+
 // Keys returns all the cached keys
 func (c *ARCCache[K, V]) Keys() []K {
 	c.RLock()
@@ -224,6 +332,7 @@ func (c *ARCCache[K, V]) Clear() {
 	c.t2.Clear()
 	c.b1.Clear()
 	c.b2.Clear()
+	c.promoter.clear()
 }
 
 // replace is used to adaptively evict from either T1 or T2
@@ -231,16 +340,8 @@ func (c *ARCCache[K, V]) Clear() {
 func (c *ARCCache[K, V]) replace(b2ContainsKey bool) {
 	t1Len := c.t1.Len()
 	if t1Len > 0 && (t1Len > c.p || (t1Len == c.p && b2ContainsKey)) {
-		k, _, ok := c.t1.RemoveOldest()
-		if ok {
-			var v V
-			c.b1.Add(k, v)
-		}
+		c.promoter.retire(c.t1, c.b1, c.b2)
 	} else {
-		k, _, ok := c.t2.RemoveOldest()
-		if ok {
-			var v V
-			c.b2.Add(k, v)
-		}
+		c.promoter.retire(c.t2, c.b2, c.b1)
 	}
 }
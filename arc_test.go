@@ -350,3 +350,74 @@ func TestARC_Peek(t *testing.T) {
 		t.Errorf("should not have updated recent-ness of 1")
 	}
 }
+
+func TestARC_Stats(t *testing.T) {
+	l := NewARC[int, int](2)
+	l.Add(1, 1)
+	l.Get(1) // T1 hit, promoted to T2
+	l.Get(1) // T2 hit
+	l.Get(2) // Miss
+	l.Add(2, 2)
+	l.Add(3, 3) // Evicts 2 from T1 to B1
+	l.Add(2, 2) // B1 hit
+
+	stats := l.Stats()
+	expected := ARCStats{T1Hits: 1, T2Hits: 1, Misses: 1, B1Hits: 1, P: 1}
+	if stats != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, stats)
+	}
+	if stats.Hits() != 2 {
+		t.Fatalf("Expected %v, got %v", 2, stats.Hits())
+	}
+}
+
+func TestARC_GetOrAdd(t *testing.T) {
+	l := NewARC[int, int](2)
+	if v, loaded := l.GetOrAdd(1, 1); loaded || v != 1 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, false, v, loaded)
+	}
+	if v, loaded := l.GetOrAdd(1, 10); !loaded || v != 1 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, true, v, loaded)
+	}
+	// The hit promotes the entry like Get
+	if l.t2.Len() != 1 {
+		t.Fatalf("Expected %v, got %v", 1, l.t2.Len())
+	}
+	if s := l.Stats(); s.T1Hits != 1 || s.Misses != 1 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, 1, s.T1Hits, s.Misses)
+	}
+}
+
+func TestARC_ContainsOrAdd(t *testing.T) {
+	l := NewARC[int, int](2)
+	if ok, evicted := l.ContainsOrAdd(1, 1); ok || evicted {
+		t.Fatalf("Expected %v, %v, got %v, %v", false, false, ok, evicted)
+	}
+	if ok, evicted := l.ContainsOrAdd(1, 10); !ok || evicted {
+		t.Fatalf("Expected %v, %v, got %v, %v", true, false, ok, evicted)
+	}
+	// The entry is not promoted nor updated
+	if v, _ := l.Peek(1); v != 1 || l.t1.Len() != 1 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, 1, v, l.t1.Len())
+	}
+	l.Add(2, 2)
+	if ok, evicted := l.ContainsOrAdd(3, 3); ok || !evicted {
+		t.Fatalf("Expected %v, %v, got %v, %v", false, true, ok, evicted)
+	}
+}
+
+func TestARC_Pop(t *testing.T) {
+	l := NewARC[int, int](4)
+	l.Add(1, 1)
+	l.Add(2, 2)
+	l.Get(2)
+	if v, ok := l.Pop(1); !ok || v != 1 {
+		t.Fatalf("Expected %v, got %v", 1, v)
+	}
+	if v, ok := l.Pop(2); !ok || v != 2 {
+		t.Fatalf("Expected %v, got %v", 2, v)
+	}
+	if _, ok := l.Pop(2); ok || l.Len() != 0 {
+		t.Fatalf("Expected %v, %v, got %v, %v", false, 0, ok, l.Len())
+	}
+}
@@ -0,0 +1,36 @@
+package lru
+
+// WithOnBatchRemoved replaces the eviction callbacks of RemoveAll by a single
+// invocation of onRemoved with all the removed entries, to avoid a callback
+// per entry during mass invalidations. It is executed synchronously, after
+// the removals, and not at all if no key was contained.
+func WithOnBatchRemoved[K comparable, V any](onRemoved func(entries []Entry[K, V])) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		c.onBatchRemoved = onRemoved
+	}
+}
+
+func (c *unsafeCache[K, V]) RemoveAll(keys []K) (removed int) {
+	var batch []Entry[K, V]
+	if c.onBatchRemoved != nil {
+		c.batch = &batch
+	}
+	for _, key := range keys {
+		if elem, ok := c.bucket.get(c.normalize(key)); ok {
+			c.removeElement(elem, EventRemove)
+			removed++
+		}
+	}
+	c.batch = nil
+	if len(batch) > 0 {
+		c.onBatchRemoved(batch)
+	}
+	return removed
+}
+
+func (c *unsafeCache[K, V]) AddReturningEvicted(key K, value V) (evicted []Entry[K, V]) {
+	c.batch = &evicted
+	c.add(key, value, c.ttl)
+	c.batch = nil
+	return evicted
+}
@@ -0,0 +1,84 @@
+package lru
+
+import "testing"
+
+func TestLru_RemoveAll(t *testing.T) {
+	var evicted []int
+	l := New[int, int](128, WithOnEvicted(func(key, value int) {
+		evicted = append(evicted, key)
+	}))
+	for i := 0; i < 5; i++ {
+		l.Add(i, i)
+	}
+	if removed := l.RemoveAll([]int{1, 3, 7}); removed != 2 {
+		t.Fatalf("Expected %v, got %v", 2, removed)
+	}
+	if l.Len() != 3 || l.Contains(1) || l.Contains(3) {
+		t.Fatalf("bad keys: %v", l.Keys())
+	}
+	if len(evicted) != 2 {
+		t.Fatalf("Expected %v, got %v", 2, len(evicted))
+	}
+}
+
+func TestLru_WithOnBatchRemoved(t *testing.T) {
+	var evicted, batches int
+	var batch []Entry[int, int]
+	l := New[int, int](3,
+		WithOnEvicted(func(key, value int) { evicted++ }),
+		WithOnBatchRemoved(func(entries []Entry[int, int]) {
+			batches++
+			batch = entries
+		}),
+	)
+	for i := 0; i < 4; i++ {
+		l.Add(i, i*10)
+	}
+	if evicted != 1 {
+		t.Fatalf("capacity evictions should execute the callback: %v", evicted)
+	}
+
+	l.RemoveAll([]int{1, 2, 5})
+	if evicted != 1 || batches != 1 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, 1, evicted, batches)
+	}
+	if len(batch) != 2 || batch[0].Key != 1 || batch[1].Value != 20 {
+		t.Fatalf("bad batch: %+v", batch)
+	}
+
+	l.RemoveAll([]int{5})
+	if batches != 1 {
+		t.Fatal("empty batch should not be delivered")
+	}
+	l.Remove(3)
+	if evicted != 2 {
+		t.Fatalf("Remove should execute the callback: %v", evicted)
+	}
+}
+
+func TestLru_AddReturningEvicted(t *testing.T) {
+	evicted := 0
+	l := New[int, int](10,
+		WithWeigher(func(k, v int) int64 { return int64(v) }, 10),
+		WithOnEvicted(func(k, v int) { evicted++ }),
+	)
+	for i := 1; i <= 4; i++ {
+		if e := l.AddReturningEvicted(i, 2); len(e) != 0 {
+			t.Fatalf("Expected no evictions, got %v", e)
+		}
+	}
+
+	e := l.AddReturningEvicted(5, 6)
+	if len(e) != 2 || e[0].Key != 1 || e[1].Key != 2 || e[0].Value != 2 {
+		t.Fatalf("bad evictions: %+v", e)
+	}
+	if evicted != 0 || l.Len() != 3 {
+		t.Fatalf("Expected %v, got %v", 0, evicted)
+	}
+
+	// The callbacks are back for the other Adds
+	l.Add(6, 10)
+	if evicted != 3 {
+		t.Fatalf("Expected %v, got %v", 3, evicted)
+	}
+}
@@ -0,0 +1,124 @@
+// Package bloom provides a resettable Bloom filter, generic over the key
+// type. Used as a doorkeeper, it keeps keys seen only once out of a cache:
+//
+//	if door.Add(key) {
+//		cache.Add(key, value) // seen before
+//	}
+package bloom
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sync"
+)
+
+// Hash maps a key to uint64
+type Hash[K any] func(key K) uint64
+
+// Filter is a Bloom filter. It is safe for concurrent access.
+type Filter[K any] struct {
+	hash   Hash[K]
+	hashes uint64 // Number of bits set per key
+	bits   []uint64
+	added  int
+
+	mu sync.Mutex
+}
+
+// New creates a filter sized for the given number of keys with the given
+// false positive rate. fn defaults to FNV-1a of fmt.Sprint of the key.
+func New[K any](keys int, falsePositiveRate float64, fn Hash[K]) *Filter[K] {
+	if keys <= 0 {
+		keys = 1
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		falsePositiveRate = 0.01
+	}
+	m := math.Ceil(-float64(keys) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	k := math.Round(m / float64(keys) * math.Ln2)
+	if k < 1 {
+		k = 1
+	}
+
+	f := &Filter[K]{
+		hash:   fn,
+		hashes: uint64(k),
+		bits:   make([]uint64, (uint64(m)+63)/64),
+	}
+	if f.hash == nil {
+		f.hash = func(key K) uint64 {
+			h := fnv.New64a()
+			_, _ = fmt.Fprint(h, key)
+			return h.Sum64()
+		}
+	}
+	return f
+}
+
+// Add adds the key to the filter. Returns true if the key may have been
+// added before, false if it definitely was not.
+func (f *Filter[K]) Add(key K) (present bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	present = true
+	f.locate(key, func(i uint64) {
+		word, mask := i/64, uint64(1)<<(i%64)
+		if f.bits[word]&mask == 0 {
+			present = false
+			f.bits[word] |= mask
+		}
+	})
+	if !present {
+		f.added++
+	}
+	return present
+}
+
+// Contains returns true if the key may have been added, false if it
+// definitely was not.
+func (f *Filter[K]) Contains(key K) (present bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	present = true
+	f.locate(key, func(i uint64) {
+		if f.bits[i/64]&(1<<(i%64)) == 0 {
+			present = false
+		}
+	})
+	return present
+}
+
+// Len returns the number of keys added since the last reset, not counting
+// the keys which were reported as present.
+func (f *Filter[K]) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.added
+}
+
+// Reset removes all the keys, e.g. periodically so the filter only
+// remembers recent keys.
+func (f *Filter[K]) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i := range f.bits {
+		f.bits[i] = 0
+	}
+	f.added = 0
+}
+
+// locate calls fn with each bit index of the key, derived from the two
+// halves of its hash by double hashing.
+func (f *Filter[K]) locate(key K, fn func(i uint64)) {
+	hash := f.hash(key)
+	h1, h2 := hash&math.MaxUint32, hash>>32|1
+	m := uint64(len(f.bits)) * 64
+	for i := uint64(0); i < f.hashes; i++ {
+		fn((h1 + i*h2) % m)
+	}
+}
@@ -0,0 +1,61 @@
+package bloom
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	f := New[string](1000, 0.01, nil)
+	if f.Contains("a") {
+		t.Fatal("should not contain a")
+	}
+	if f.Add("a") {
+		t.Fatal("a should not be present")
+	}
+	if !f.Add("a") || !f.Contains("a") {
+		t.Fatal("a should be present")
+	}
+	if f.Len() != 1 {
+		t.Fatalf("Expected %v, got %v", 1, f.Len())
+	}
+
+	f.Reset()
+	if f.Contains("a") || f.Len() != 0 {
+		t.Fatal("should be empty")
+	}
+}
+
+func TestFilter_FalsePositiveRate(t *testing.T) {
+	f := New[int](1000, 0.01, nil)
+	for i := 0; i < 1000; i++ {
+		f.Add(i)
+	}
+	for i := 0; i < 1000; i++ {
+		if !f.Contains(i) {
+			t.Fatalf("should contain %v", i)
+		}
+	}
+
+	falsePositives := 0
+	for i := 1000; i < 11000; i++ {
+		if f.Contains(i) {
+			falsePositives++
+		}
+	}
+	// Expect around 100
+	if falsePositives > 300 {
+		t.Fatalf("too many false positives: %v", falsePositives)
+	}
+}
+
+func TestFilter_Hash(t *testing.T) {
+	f := New[string](100, 0.01, func(key string) uint64 {
+		i, _ := strconv.ParseUint(key, 10, 64)
+		return i
+	})
+	f.Add("1")
+	if !f.Contains("1") {
+		t.Fatal("should contain 1")
+	}
+}
@@ -0,0 +1,56 @@
+package lru
+
+import "time"
+
+const (
+	// minJanitorInterval and maxJanitorInterval bound the period of the
+	// sweeps of NewBounded.
+	minJanitorInterval = time.Second
+	maxJanitorInterval = time.Minute
+)
+
+// WithJanitor removes the expired entries every interval. The Cache
+// returned by New sweeps on a background goroutine until Close, while the
+// caller of NewUnsafeLru has to call RemoveExpired itself. Without it,
+// expired entries are only removed when they are looked up or evicted.
+func WithJanitor[K comparable, V any](interval time.Duration) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		c.janitorInterval = interval
+	}
+}
+
+// NewBounded creates a Cache holding at most maxEntries entries, each
+// for at most maxAge, with a janitor sweeping the expired entries four
+// times per maxAge, between once a second and once a minute. The options
+// are applied afterwards, and can override these defaults. A maxAge of
+// zero or less disables the expiration.
+func NewBounded[K comparable, V any](maxEntries int, maxAge time.Duration, opts ...Option[K, V]) *Cache[K, V] {
+	if maxAge <= 0 {
+		return New[K, V](maxEntries, opts...)
+	}
+	interval := maxAge / 4
+	if interval < minJanitorInterval {
+		interval = minJanitorInterval
+	}
+	if interval > maxJanitorInterval {
+		interval = maxJanitorInterval
+	}
+	defaults := []Option[K, V]{WithTTL[K, V](maxAge), WithJanitor[K, V](interval)}
+	return New[K, V](maxEntries, append(defaults, opts...)...)
+}
+
+// janitor removes the expired entries every interval, until Close.
+func (c *Cache[K, V]) janitor(u *unsafeCache[K, V], interval time.Duration) {
+	defer u.untrack()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-u.ctx.Done():
+			return
+		case <-ticker.C:
+			c.RemoveExpired()
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewBounded(t *testing.T) {
+	c := NewBounded[string, int](2, time.Minute)
+	defer c.Close()
+
+	u := c.lru.(*unsafeCache[string, int])
+	if u.ttl != time.Minute {
+		t.Fatalf("Expected %v, got %v", time.Minute, u.ttl)
+	}
+	if u.janitorInterval != 15*time.Second {
+		t.Fatalf("Expected %v, got %v", 15*time.Second, u.janitorInterval)
+	}
+
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Add("c", 3)
+	if c.Len() != 2 || c.Contains("a") {
+		t.Fatalf("Expected %v, got %v", 2, c.Len())
+	}
+}
+
+func TestNewBounded_Defaults(t *testing.T) {
+	for _, tt := range []struct {
+		maxAge, want time.Duration
+	}{
+		{time.Second, minJanitorInterval},
+		{time.Hour, maxJanitorInterval},
+		{0, 0},
+	} {
+		c := NewBounded[string, int](2, tt.maxAge)
+		if got := c.lru.(*unsafeCache[string, int]).janitorInterval; got != tt.want {
+			t.Fatalf("Expected %v, got %v", tt.want, got)
+		}
+		c.Close()
+	}
+
+	c := NewBounded[string, int](2, time.Hour, WithJanitor[string, int](time.Second))
+	defer c.Close()
+	if got := c.lru.(*unsafeCache[string, int]).janitorInterval; got != time.Second {
+		t.Fatalf("Expected %v, got %v", time.Second, got)
+	}
+}
+
+func TestCache_WithJanitor(t *testing.T) {
+	expired := make(chan string, 1)
+	c := New[string, int](10,
+		WithTTL[string, int](time.Millisecond),
+		WithJanitor[string, int](5*time.Millisecond),
+		WithOnExpired(func(k string, v int) {
+			expired <- k
+		}),
+	)
+	defer c.Close()
+
+	c.Add("a", 1)
+	select {
+	case k := <-expired:
+		if k != "a" {
+			t.Fatalf("Expected %v, got %v", "a", k)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the janitor should remove the expired entry")
+	}
+	if c.Len() != 0 {
+		t.Fatalf("Expected %v, got %v", 0, c.Len())
+	}
+}
@@ -0,0 +1,27 @@
+package lru
+
+import "sync/atomic"
+
+// Disable turns the cache into a transparent pass-through, e.g. to mitigate
+// an incident caused by poisoned cache data: every lookup misses, the
+// listings such as Keys, Items, All, Range, ByExpiry and KeysApprox are
+// empty, and every Add is dropped, until Enable. The entries are kept, and
+// Remove, RemoveGet, Pop, RemoveOldest and Clear still remove them, without
+// returning their values, so that they can be fixed before re-enabling.
+func (c *Cache[K, V]) Disable() {
+	atomic.StoreInt32(&c.disabled, 1)
+}
+
+// Enable turns a disabled cache back on.
+func (c *Cache[K, V]) Enable() {
+	atomic.StoreInt32(&c.disabled, 0)
+}
+
+// Disabled returns true if the cache is a pass-through.
+func (c *Cache[K, V]) Disabled() bool {
+	return c.bypassed()
+}
+
+func (c *Cache[K, V]) bypassed() bool {
+	return atomic.LoadInt32(&c.disabled) == 1
+}
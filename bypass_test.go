@@ -0,0 +1,71 @@
+package lru
+
+import "testing"
+
+func TestCache_Disable(t *testing.T) {
+	l := New[int, int](128)
+	l.Add(1, 1)
+
+	l.Disable()
+	if !l.Disabled() {
+		t.Fatal("should be disabled")
+	}
+	if _, ok := l.Get(1); ok {
+		t.Fatal("should miss")
+	}
+	if l.Contains(1) {
+		t.Fatal("should miss")
+	}
+	if _, ok := l.PeekEntry(1); ok {
+		t.Fatal("should miss")
+	}
+	if l.Add(2, 2); l.Len() != 1 {
+		t.Fatalf("Expected %v, got %v", 1, l.Len())
+	}
+	if result := l.Put(2, 2); result != AddResultRejected {
+		t.Fatalf("Expected %v, got %v", AddResultRejected, result)
+	}
+	v, ok := l.Compute(1, func(old int, exists bool) (int, bool) {
+		if exists {
+			t.Fatal("should miss")
+		}
+		return old + 10, true
+	})
+	if v != 10 || ok {
+		t.Fatalf("Expected %v, %v, got %v, %v", 10, false, v, ok)
+	}
+	if _, _, ok = l.GetOldest(); ok {
+		t.Fatal("oldest should miss")
+	}
+	if _, _, ok = l.PopOldestIf(nil); ok {
+		t.Fatal("oldest should miss")
+	}
+	if keys, items := l.Keys(), l.Items(); len(keys) != 0 || len(items) != 0 {
+		t.Fatalf("Expected %v, got %v, %v", 0, keys, items)
+	}
+	l.Range(func(key, value int) bool {
+		t.Fatal("should not visit")
+		return false
+	})
+	if keys := l.KeysApprox(); len(keys) != 0 {
+		t.Fatalf("Expected %v, got %v", 0, keys)
+	}
+
+	l.Enable()
+	l.Add(3, 3)
+	l.Disable()
+	if v, ok = l.Pop(3); v != 0 || ok {
+		t.Fatalf("Expected %v, %v, got %v, %v", 0, false, v, ok)
+	}
+
+	l.Enable()
+	if v, ok = l.Get(1); !ok || v != 1 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, true, v, ok)
+	}
+	if l.Contains(2) {
+		t.Fatal("2 should have been dropped")
+	}
+	if l.Contains(3) {
+		t.Fatal("3 should have been removed")
+	}
+}
@@ -0,0 +1,88 @@
+package lru
+
+// NewChain composes caches into levels, e.g. a small L1 in front of a larger
+// L2. Lookups query the levels in order and promote hits into the earlier
+// levels, Add writes to every level.
+func NewChain[K comparable, V any](caches ...Cacher[K, V]) *ChainCache[K, V] {
+	return &ChainCache[K, V]{levels: caches, writeAll: true}
+}
+
+// NewChainWriteFirst is like NewChain, but Add only writes to the first
+// level, the later levels are filled by other means.
+func NewChainWriteFirst[K comparable, V any](caches ...Cacher[K, V]) *ChainCache[K, V] {
+	return &ChainCache[K, V]{levels: caches}
+}
+
+// ChainCache is a multi-level cache. It does no locking of its own,
+// so it is safe for concurrent access only if every level is.
+type ChainCache[K comparable, V any] struct {
+	levels   []Cacher[K, V]
+	writeAll bool
+}
+
+// Add a value to the first level, or to every level.
+// Returns true if an eviction occurred in any of them.
+func (c *ChainCache[K, V]) Add(key K, value V) (evicted bool) {
+	for i, l := range c.levels {
+		if i > 0 && !c.writeAll {
+			break
+		}
+		if l.Add(key, value) {
+			evicted = true
+		}
+	}
+	return evicted
+}
+
+// Get looks up a key's value level by level, and copies a hit
+// into the levels before the one it was found in.
+func (c *ChainCache[K, V]) Get(key K) (value V, ok bool) {
+	for i, l := range c.levels {
+		if value, ok = l.Get(key); !ok {
+			continue
+		}
+		for j := 0; j < i; j++ {
+			c.levels[j].Add(key, value)
+		}
+		return value, true
+	}
+	return value, false
+}
+
+// Peek looks up a key's value level by level, without promoting it
+// or updating the "recently used"-ness of the key.
+func (c *ChainCache[K, V]) Peek(key K) (value V, ok bool) {
+	for _, l := range c.levels {
+		if value, ok = l.Peek(key); ok {
+			return value, true
+		}
+	}
+	return value, false
+}
+
+// Contains checks if any level contains the key.
+func (c *ChainCache[K, V]) Contains(key K) (ok bool) {
+	for _, l := range c.levels {
+		if l.Contains(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Remove removes the key from every level, returning if any contained it.
+func (c *ChainCache[K, V]) Remove(key K) (ok bool) {
+	for _, l := range c.levels {
+		if l.Remove(key) {
+			ok = true
+		}
+	}
+	return ok
+}
+
+// Clear clears every level.
+func (c *ChainCache[K, V]) Clear() {
+	for _, l := range c.levels {
+		l.Clear()
+	}
+}
@@ -0,0 +1,55 @@
+package lru
+
+import "testing"
+
+func TestChainCache(t *testing.T) {
+	l1 := NewUnsafeLru[int, int](2)
+	l2 := New[int, int](10)
+	c := NewChain[int, int](l1, l2)
+
+	for i := 0; i < 4; i++ {
+		c.Add(i, i)
+	}
+	if l1.Len() != 2 || l2.Len() != 4 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 2, 4, l1.Len(), l2.Len())
+	}
+
+	// Hit in L2 is promoted into L1
+	if v, ok := c.Get(0); !ok || v != 0 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 0, true, v, ok)
+	}
+	if !l1.Contains(0) {
+		t.Fatal("should be promoted")
+	}
+
+	// Peek does not promote
+	if v, ok := c.Peek(1); !ok || v != 1 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, true, v, ok)
+	}
+	if l1.Contains(1) {
+		t.Fatal("should not be promoted")
+	}
+
+	if !c.Remove(0) || c.Contains(0) {
+		t.Fatal("should be removed from every level")
+	}
+	if _, ok := c.Get(100); ok {
+		t.Fatal("should not exist")
+	}
+
+	c.Clear()
+	if l1.Len() != 0 || l2.Len() != 0 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 0, 0, l1.Len(), l2.Len())
+	}
+}
+
+func TestChainCache_WriteFirst(t *testing.T) {
+	l1 := NewUnsafeLru[int, int](2)
+	l2 := NewUnsafeLru[int, int](10)
+	c := NewChainWriteFirst[int, int](l1, l2)
+
+	c.Add(1, 1)
+	if !l1.Contains(1) || l2.Contains(1) {
+		t.Fatal("should only be written to the first level")
+	}
+}
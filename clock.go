@@ -0,0 +1,82 @@
+package lru
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WithClock sets the clock of the expirations, time.Now by default,
+// e.g. to control the time in tests.
+func WithClock[K comparable, V any](now func() time.Time) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		if now != nil {
+			c.now = now
+		}
+	}
+}
+
+// WithCoarseClock timestamps the entries with a clock updated every
+// resolution by a background goroutine, instead of calling time.Now on every
+// operation, which cuts the overhead of WithTTL and WithTTI on very hot
+// caches. Expirations are then up to about resolution late. The caches with
+// the same resolution share the clock, whose goroutine starts on first use
+// and stops once the clock is not read for a whole resolution, so it needs no
+// Close, e.g. for the internal lists of the ARC and 2Q caches.
+func WithCoarseClock[K comparable, V any](resolution time.Duration) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		if resolution <= 0 {
+			return
+		}
+		c.now = sharedCoarseClock(resolution).now
+	}
+}
+
+// coarseClocks holds the process-wide coarse clocks by resolution.
+var coarseClocks sync.Map
+
+func sharedCoarseClock(resolution time.Duration) *coarseClock {
+	if clock, ok := coarseClocks.Load(resolution); ok {
+		return clock.(*coarseClock)
+	}
+	clock, _ := coarseClocks.LoadOrStore(resolution, &coarseClock{resolution: resolution})
+	return clock.(*coarseClock)
+}
+
+// coarseClock is a time read atomically, updated by a ticker
+// running while the clock is read.
+type coarseClock struct {
+	resolution time.Duration
+	unixNano   atomic.Int64
+	// read is set by now and cleared on every tick
+	read    atomic.Bool
+	running atomic.Bool
+}
+
+func (c *coarseClock) now() time.Time {
+	if !c.running.Load() && c.running.CompareAndSwap(false, true) {
+		c.set(time.Now())
+		go c.tick()
+	}
+	if !c.read.Load() {
+		c.read.Store(true)
+	}
+	return time.Unix(0, c.unixNano.Load())
+}
+
+func (c *coarseClock) set(t time.Time) {
+	c.unixNano.Store(t.UnixNano())
+}
+
+// tick updates the clock until it is idle for a whole resolution.
+func (c *coarseClock) tick() {
+	ticker := time.NewTicker(c.resolution)
+	defer ticker.Stop()
+	for t := range ticker.C {
+		if !c.read.Swap(false) {
+			c.running.Store(false)
+			return
+		}
+		c.set(t)
+	}
+}
@@ -0,0 +1,52 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLru_WithCoarseClock(t *testing.T) {
+	l := NewUnsafeLru[string, int](10, WithTTL[string, int](10*time.Millisecond), WithCoarseClock[string, int](time.Millisecond))
+	defer l.Close()
+
+	c := l.(*unsafeCache[string, int])
+	t0 := c.now()
+
+	l.Add("a", 1)
+	deadline := time.Now().Add(time.Second)
+	for l.Contains("a") && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if l.Contains("a") {
+		t.Fatal("the entry should expire as the clock advances")
+	}
+	if !c.now().After(t0) {
+		t.Fatalf("Expected after %v, got %v", t0, c.now())
+	}
+}
+
+func TestARCCache_WithCoarseClockIdle(t *testing.T) {
+	const resolution = 3 * time.Millisecond
+	c := NewARC[string, int](10, WithCoarseClock[string, int](resolution))
+	c.Add("a", 1)
+	c.Get("a")
+
+	// The internal lists are never closed, the shared clock stops by itself
+	clock := sharedCoarseClock(resolution)
+	deadline := time.Now().Add(time.Second)
+	for clock.running.Load() && time.Now().Before(deadline) {
+		time.Sleep(resolution)
+	}
+	if clock.running.Load() {
+		t.Fatal("the idle clock should stop")
+	}
+
+	// and restarts on the next read
+	t0 := clock.now()
+	if !clock.running.Load() {
+		t.Fatal("the clock should restart when read")
+	}
+	if time.Since(t0) > time.Second {
+		t.Fatalf("Expected a fresh time, got %v", t0)
+	}
+}
@@ -0,0 +1,22 @@
+// Package cbor provides an lru.Codec using CBOR.
+package cbor
+
+import (
+	"io"
+
+	"github.com/electricbubble/lru"
+	"github.com/fxamacker/cbor/v2"
+)
+
+// Codec is an lru.Codec using github.com/fxamacker/cbor/v2.
+type Codec struct{}
+
+var _ lru.Codec = Codec{}
+
+func (Codec) NewEncoder(w io.Writer) lru.Encoder {
+	return cbor.NewEncoder(w)
+}
+
+func (Codec) NewDecoder(r io.Reader) lru.Decoder {
+	return cbor.NewDecoder(r)
+}
@@ -0,0 +1,16 @@
+module github.com/electricbubble/lru/codec
+
+go 1.19
+
+require (
+	github.com/electricbubble/lru v0.0.0
+	github.com/fxamacker/cbor/v2 v2.5.0
+	github.com/vmihailenco/msgpack/v5 v5.3.5
+)
+
+require (
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
+)
+
+replace github.com/electricbubble/lru => ../
@@ -0,0 +1,22 @@
+// Package msgpack provides an lru.Codec using MessagePack.
+package msgpack
+
+import (
+	"io"
+
+	"github.com/electricbubble/lru"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Codec is an lru.Codec using github.com/vmihailenco/msgpack/v5.
+type Codec struct{}
+
+var _ lru.Codec = Codec{}
+
+func (Codec) NewEncoder(w io.Writer) lru.Encoder {
+	return msgpack.NewEncoder(w)
+}
+
+func (Codec) NewDecoder(r io.Reader) lru.Decoder {
+	return msgpack.NewDecoder(r)
+}
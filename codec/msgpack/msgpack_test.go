@@ -0,0 +1,36 @@
+package msgpack
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/electricbubble/lru"
+)
+
+func TestCodec(t *testing.T) {
+	l := lru.New[string, []int](128)
+	l.Add("a", []int{1})
+	l.AddWithTTL("b", []int{2, 3}, time.Hour)
+
+	var buf bytes.Buffer
+	if err := lru.Export[string, []int](&buf, l, Codec{}); err != nil {
+		t.Fatal(err)
+	}
+
+	imported := lru.New[string, []int](128)
+	n, err := lru.Import[string, []int](&buf, imported, Codec{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || !reflect.DeepEqual(imported.Keys(), []string{"a", "b"}) {
+		t.Fatalf("Expected %v, got %v", []string{"a", "b"}, imported.Keys())
+	}
+	if v, _ := imported.Peek("b"); !reflect.DeepEqual(v, []int{2, 3}) {
+		t.Fatalf("Expected %v, got %v", []int{2, 3}, v)
+	}
+	if e, _ := imported.PeekEntry("b"); e.ExpiresAt.IsZero() {
+		t.Fatal("expiry not imported")
+	}
+}
@@ -0,0 +1,45 @@
+package lru
+
+// Comparable is a Cache of comparable values, with the compare-and-swap
+// operations of sync.Map, so that concurrent writers can coordinate
+// without a mutex of their own.
+type Comparable[K, V comparable] struct {
+	*Cache[K, V]
+}
+
+// NewComparable creates a Comparable cache, taking the arguments of New.
+func NewComparable[K, V comparable](maxEntries int, opts ...Option[K, V]) Comparable[K, V] {
+	return Comparable[K, V]{New[K, V](maxEntries, opts...)}
+}
+
+// CompareAndSwap replaces the value of the key with new if it is in the
+// cache with the value old, returning whether it was swapped.
+func (c Comparable[K, V]) CompareAndSwap(key K, old, new V) (swapped bool) {
+	if c.bypassed() {
+		return false
+	}
+	c.lock()
+	defer c.Unlock()
+
+	if v, ok := c.lru.Peek(key); !ok || v != old {
+		return false
+	}
+	c.lru.Add(key, new)
+	return true
+}
+
+// CompareAndDelete removes the key if it is in the cache with the value
+// old, returning whether it was removed. Like CompareAndSwap, it misses
+// while the cache is disabled.
+func (c Comparable[K, V]) CompareAndDelete(key K, old V) (deleted bool) {
+	if c.bypassed() {
+		return false
+	}
+	c.lock()
+	defer c.Unlock()
+
+	if v, ok := c.lru.Peek(key); !ok || v != old {
+		return false
+	}
+	return c.lru.Remove(key)
+}
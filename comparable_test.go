@@ -0,0 +1,59 @@
+package lru
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestComparable(t *testing.T) {
+	c := NewComparable[string, int](10)
+	if c.CompareAndSwap("a", 0, 1) {
+		t.Fatalf("Expected %v, got %v", false, true)
+	}
+	c.Add("a", 1)
+	if c.CompareAndSwap("a", 2, 3) {
+		t.Fatalf("Expected %v, got %v", false, true)
+	}
+	if !c.CompareAndSwap("a", 1, 2) {
+		t.Fatalf("Expected %v, got %v", true, false)
+	}
+	if c.CompareAndDelete("a", 1) || !c.Contains("a") {
+		t.Fatalf("Expected %v, got %v", false, true)
+	}
+	if !c.CompareAndDelete("a", 2) || c.Contains("a") {
+		t.Fatalf("Expected %v, got %v", true, false)
+	}
+
+	c.Add("b", 1)
+	c.Disable()
+	if c.CompareAndSwap("b", 1, 2) || c.CompareAndDelete("b", 1) {
+		t.Fatalf("Expected %v, got %v", false, true)
+	}
+	c.Enable()
+	if v, ok := c.Get("b"); !ok || v != 1 {
+		t.Fatalf("Expected %v, got %v", 1, v)
+	}
+}
+
+func TestComparable_Concurrent(t *testing.T) {
+	c := NewComparable[string, int](10)
+	c.Add("n", 0)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; {
+				v, _ := c.Peek("n")
+				if c.CompareAndSwap("n", v, v+1) {
+					i++
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if v, _ := c.Get("n"); v != 400 {
+		t.Fatalf("Expected %v, got %v", 400, v)
+	}
+}
@@ -0,0 +1,105 @@
+// edited by https://github.com/golang/groupcache/blob/master/consistenthash/consistenthash.go
+
+// Package consistenthash provides a hash ring with virtual nodes,
+// generic over the node type.
+package consistenthash
+
+import (
+	"fmt"
+	"hash/crc32"
+	"sort"
+	"strconv"
+)
+
+// Hash maps bytes to uint32
+type Hash func(data []byte) uint32
+
+// Map is a consistent hash ring. It is not safe for concurrent access.
+type Map[N comparable] struct {
+	hash     Hash
+	name     func(node N) string
+	replicas int
+	keys     []uint32 // Sorted
+	hashMap  map[uint32]N
+}
+
+// New creates a ring with the given number of virtual nodes per node.
+// fn defaults to crc32.ChecksumIEEE, and name, which identifies a node
+// on the ring, defaults to fmt.Sprint.
+func New[N comparable](replicas int, fn Hash, name func(node N) string) *Map[N] {
+	if replicas <= 0 {
+		replicas = 1
+	}
+	m := &Map[N]{
+		replicas: replicas,
+		hash:     fn,
+		name:     name,
+		hashMap:  make(map[uint32]N),
+	}
+	if m.hash == nil {
+		m.hash = crc32.ChecksumIEEE
+	}
+	if m.name == nil {
+		m.name = func(node N) string { return fmt.Sprint(node) }
+	}
+	return m
+}
+
+// IsEmpty returns true if there are no nodes on the ring.
+func (m *Map[N]) IsEmpty() bool {
+	return len(m.keys) == 0
+}
+
+// Add adds some nodes to the ring.
+func (m *Map[N]) Add(nodes ...N) {
+	for _, node := range nodes {
+		name := m.name(node)
+		for i := 0; i < m.replicas; i++ {
+			hash := m.hash([]byte(strconv.Itoa(i) + name))
+			m.keys = append(m.keys, hash)
+			m.hashMap[hash] = node
+		}
+	}
+	sort.Slice(m.keys, func(i, j int) bool { return m.keys[i] < m.keys[j] })
+}
+
+// Remove removes some nodes from the ring.
+func (m *Map[N]) Remove(nodes ...N) {
+	removed := make(map[uint32]struct{})
+	for _, node := range nodes {
+		name := m.name(node)
+		for i := 0; i < m.replicas; i++ {
+			hash := m.hash([]byte(strconv.Itoa(i) + name))
+			if n, ok := m.hashMap[hash]; ok && n == node {
+				delete(m.hashMap, hash)
+				removed[hash] = struct{}{}
+			}
+		}
+	}
+	keys := m.keys[:0]
+	for _, hash := range m.keys {
+		if _, ok := removed[hash]; !ok {
+			keys = append(keys, hash)
+		}
+	}
+	m.keys = keys
+}
+
+// Get returns the node closest to the key on the ring.
+func (m *Map[N]) Get(key string) (node N, ok bool) {
+	if m.IsEmpty() {
+		return node, false
+	}
+
+	hash := m.hash([]byte(key))
+
+	// Binary search for appropriate replica.
+	idx := sort.Search(len(m.keys), func(i int) bool { return m.keys[i] >= hash })
+
+	// Means we have cycled back to the first replica.
+	if idx == len(m.keys) {
+		idx = 0
+	}
+
+	return m.hashMap[m.keys[idx]], true
+}
@@ -0,0 +1,118 @@
+// edited by https://github.com/golang/groupcache/blob/master/consistenthash/consistenthash_test.go
+
+package consistenthash
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+func TestHashing(t *testing.T) {
+	// Override the hash function to return easier to reason about values. Assumes
+	// the keys can be converted to an integer.
+	hash := New[int](3, func(key []byte) uint32 {
+		i, err := strconv.Atoi(string(key))
+		if err != nil {
+			panic(err)
+		}
+		return uint32(i)
+	}, nil)
+
+	// Given the above hash function, this will give replicas with "hashes":
+	// 2, 4, 6, 12, 14, 16, 22, 24, 26
+	hash.Add(6, 4, 2)
+
+	testCases := map[string]int{
+		"2":  2,
+		"11": 2,
+		"23": 4,
+		"27": 2,
+	}
+
+	for k, v := range testCases {
+		if node, _ := hash.Get(k); node != v {
+			t.Errorf("Asking for %s, should have yielded %d", k, v)
+		}
+	}
+
+	// Adds 8, 18, 28
+	hash.Add(8)
+
+	// 27 should now map to 8.
+	testCases["27"] = 8
+
+	for k, v := range testCases {
+		if node, _ := hash.Get(k); node != v {
+			t.Errorf("Asking for %s, should have yielded %d", k, v)
+		}
+	}
+
+	// Removes 8, 18, 28
+	hash.Remove(8)
+
+	// 27 should map to 2 again.
+	testCases["27"] = 2
+
+	for k, v := range testCases {
+		if node, _ := hash.Get(k); node != v {
+			t.Errorf("Asking for %s, should have yielded %d", k, v)
+		}
+	}
+}
+
+func TestConsistency(t *testing.T) {
+	hash1 := New[string](1, nil, nil)
+	hash2 := New[string](1, nil, nil)
+
+	hash1.Add("Bill", "Bob", "Bonny")
+	hash2.Add("Bob", "Bonny", "Bill")
+
+	b1, _ := hash1.Get("Ben")
+	b2, _ := hash2.Get("Ben")
+	if b1 != b2 {
+		t.Errorf("Fetching 'Ben' from both hashes should be the same")
+	}
+
+	hash2.Add("Becky", "Ben", "Bobby")
+
+	for _, key := range []string{"Ben", "Bob", "Bonny"} {
+		n1, _ := hash1.Get(key)
+		n2, _ := hash2.Get(key)
+		if n1 != n2 {
+			t.Errorf("Direct matches should always return the same entry")
+		}
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	hash := New[string](50, nil, nil)
+	if _, ok := hash.Get("k"); ok {
+		t.Fatal("should not exist")
+	}
+}
+
+type server struct {
+	host string
+	port int
+}
+
+func TestNodeType(t *testing.T) {
+	hash := New[server](50, nil, func(s server) string {
+		return fmt.Sprintf("%s:%d", s.host, s.port)
+	})
+	servers := []server{{"10.0.0.1", 11211}, {"10.0.0.2", 11211}}
+	hash.Add(servers...)
+
+	seen := make(map[server]bool)
+	for i := 0; i < 100; i++ {
+		node, ok := hash.Get(strconv.Itoa(i))
+		if !ok {
+			t.Fatal("should exist")
+		}
+		seen[node] = true
+	}
+	if len(seen) != len(servers) {
+		t.Fatalf("Expected %v, got %v", len(servers), len(seen))
+	}
+}
@@ -0,0 +1,104 @@
+package lru
+
+import (
+	"context"
+	"sync"
+)
+
+// contextKey is the key of the caches of type K, V in a context.
+type contextKey[K comparable, V any] struct{}
+
+// NewContext returns a child context carrying the cache, e.g. to attach
+// a per-request memoization cache in a middleware. A context carries one
+// cache per key and value types.
+func NewContext[K comparable, V any](ctx context.Context, cache Cacher[K, V]) context.Context {
+	return context.WithValue(ctx, contextKey[K, V]{}, cache)
+}
+
+// FromContext returns the cache of type K, V carried by the context.
+func FromContext[K comparable, V any](ctx context.Context) (cache Cacher[K, V], ok bool) {
+	cache, ok = ctx.Value(contextKey[K, V]{}).(Cacher[K, V])
+	return cache, ok
+}
+
+// Memoize returns the value of the key from the cache carried by the
+// context, or calls fn and caches its successful result. Without a cache,
+// it just calls fn.
+func Memoize[K comparable, V any](ctx context.Context, key K, fn func() (V, error)) (value V, err error) {
+	cache, ok := FromContext[K, V](ctx)
+	if !ok {
+		return fn()
+	}
+	if value, ok = cache.Get(key); ok {
+		return value, nil
+	}
+	if value, err = fn(); err != nil {
+		return value, err
+	}
+	cache.Add(key, value)
+	return value, nil
+}
+
+// NewRequestCache creates a RequestCache.
+func NewRequestCache[K comparable, V any]() *RequestCache[K, V] {
+	return &RequestCache[K, V]{values: make(map[K]V)}
+}
+
+// RequestCache is a cache without eviction for the lifetime of a request,
+// lighter to create than an LRU. It is safe for concurrent access.
+type RequestCache[K comparable, V any] struct {
+	values map[K]V
+
+	sync.RWMutex
+}
+
+var _ Cacher[int, int] = (*RequestCache[int, int])(nil)
+
+// Add a value to the cache. It never evicts.
+func (c *RequestCache[K, V]) Add(key K, value V) (evicted bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.values[key] = value
+	return false
+}
+
+// Get looks up a key's value from the cache
+func (c *RequestCache[K, V]) Get(key K) (value V, ok bool) {
+	return c.Peek(key)
+}
+
+// Contains checks if a key is in the cache.
+func (c *RequestCache[K, V]) Contains(key K) (ok bool) {
+	_, ok = c.Peek(key)
+	return ok
+}
+
+// Peek is the same as Get, the cache does not track recency.
+func (c *RequestCache[K, V]) Peek(key K) (value V, ok bool) {
+	c.RLock()
+	defer c.RUnlock()
+
+	value, ok = c.values[key]
+	return value, ok
+}
+
+// Remove removes the provided key from the cache, returning if the
+// key was contained.
+func (c *RequestCache[K, V]) Remove(key K) (ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, ok = c.values[key]; ok {
+		delete(c.values, key)
+	}
+	return ok
+}
+
+// Clear is used to completely clear the cache
+func (c *RequestCache[K, V]) Clear() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.values = make(map[K]V)
+}
@@ -0,0 +1,68 @@
+package lru
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := FromContext[string, int](ctx); ok {
+		t.Fatal("should not carry a cache")
+	}
+
+	cache := NewRequestCache[string, int]()
+	ctx = NewContext[string, int](ctx, cache)
+	if c, ok := FromContext[string, int](ctx); !ok || c != Cacher[string, int](cache) {
+		t.Fatal("should carry the cache")
+	}
+	if _, ok := FromContext[string, string](ctx); ok {
+		t.Fatal("should not carry a cache of other types")
+	}
+
+	calls := 0
+	fn := func() (int, error) {
+		calls++
+		return 42, nil
+	}
+	for i := 0; i < 3; i++ {
+		if v, err := Memoize(ctx, "answer", fn); err != nil || v != 42 {
+			t.Fatalf("Expected %v, got %v, %v", 42, v, err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("Expected %v, got %v", 1, calls)
+	}
+
+	// Errors are not cached
+	errFn := errors.New("failed")
+	if _, err := Memoize(ctx, "error", func() (int, error) { return 0, errFn }); err != errFn {
+		t.Fatalf("Expected %v, got %v", errFn, err)
+	}
+	if cache.Contains("error") {
+		t.Fatal("error should not be cached")
+	}
+
+	// Without a cache, fn is always called
+	Memoize(context.Background(), "answer", fn)
+	if calls != 2 {
+		t.Fatalf("Expected %v, got %v", 2, calls)
+	}
+}
+
+func TestRequestCache(t *testing.T) {
+	c := NewRequestCache[int, int]()
+	c.Add(1, 1)
+	if v, ok := c.Get(1); !ok || v != 1 {
+		t.Fatalf("Expected %v, got %v", 1, v)
+	}
+	if !c.Remove(1) || c.Remove(1) || c.Contains(1) {
+		t.Fatal("should be removed once")
+	}
+	c.Add(2, 2)
+	c.Clear()
+	if c.Contains(2) {
+		t.Fatal("should be cleared")
+	}
+}
@@ -0,0 +1,158 @@
+package lru
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// coordinatorMinShare is the fraction of an equal share of the budget
+// every cache keeps, 1/coordinatorMinShare, so that a cache without hits
+// can still prove useful.
+const coordinatorMinShare = 4
+
+// Budgeted is a cache whose capacity is managed by a Coordinator, like Cache.
+type Budgeted interface {
+	// Resize changes the cache size.
+	Resize(size int) (evicted int)
+
+	// Stats returns the counters of the cache since its creation.
+	Stats() Stats
+}
+
+// NewCoordinator creates a Coordinator sharing a budget of bytes.
+func NewCoordinator(budget int64) *Coordinator {
+	return &Coordinator{budget: budget}
+}
+
+// Coordinator shares a global byte budget among caches hosted by the same
+// process. Rebalance gives each cache a minimum share, and distributes the
+// rest in proportion to the hits each cache served since the previous
+// rebalance, i.e. its recent hit ratio weighted by its traffic.
+// It is safe for concurrent access.
+type Coordinator struct {
+	budget  int64
+	members []*budgetMember
+
+	sync.Mutex
+}
+
+type budgetMember struct {
+	cache      Budgeted
+	entryBytes int64 // Estimated size of an entry
+	bytes      int64 // Allocated share of the budget
+	last       Stats // Counters at the previous rebalance
+}
+
+// Register adds a cache whose entries take about entryBytes each, and
+// splits the budget equally among the registered caches, resizing them.
+func (c *Coordinator) Register(cache Budgeted, entryBytes int64) {
+	c.Lock()
+	defer c.Unlock()
+
+	if entryBytes <= 0 {
+		entryBytes = 1
+	}
+	c.members = append(c.members, &budgetMember{
+		cache:      cache,
+		entryBytes: entryBytes,
+		last:       cache.Stats(),
+	})
+	share := c.budget / int64(len(c.members))
+	for _, m := range c.members {
+		c.allocate(m, share)
+	}
+}
+
+// Unregister removes a cache, returning if it was registered. Its share is
+// redistributed by the next Rebalance.
+func (c *Coordinator) Unregister(cache Budgeted) (ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	for i, m := range c.members {
+		if m.cache == cache {
+			c.members = append(c.members[:i], c.members[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Allocation returns the share of the budget of the cache, in bytes.
+func (c *Coordinator) Allocation(cache Budgeted) (bytes int64, ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	for _, m := range c.members {
+		if m.cache == cache {
+			return m.bytes, true
+		}
+	}
+	return 0, false
+}
+
+// Rebalance redistributes the budget among the caches according to their
+// hits since the previous rebalance, and resizes them. Shares move halfway
+// to their target per rebalance, to dampen oscillations. Nothing changes
+// while no cache has hits.
+func (c *Coordinator) Rebalance() {
+	c.Lock()
+	defer c.Unlock()
+
+	if len(c.members) == 0 {
+		return
+	}
+	hits := make([]uint64, len(c.members))
+	var total uint64
+	for i, m := range c.members {
+		stats := m.cache.Stats()
+		hits[i] = stats.Sub(m.last).Hits
+		total += hits[i]
+		m.last = stats
+	}
+
+	var allocated int64
+	for _, m := range c.members {
+		allocated += m.bytes
+	}
+	if total == 0 && allocated == c.budget {
+		return
+	}
+
+	minShare := c.budget / int64(len(c.members)) / coordinatorMinShare
+	rest := c.budget - minShare*int64(len(c.members))
+	for i, m := range c.members {
+		target := minShare
+		if total > 0 {
+			target += int64(float64(rest) * float64(hits[i]) / float64(total))
+		} else {
+			target += rest / int64(len(c.members))
+		}
+		c.allocate(m, (m.bytes+target)/2)
+	}
+}
+
+// Run rebalances every interval until the context is done.
+func (c *Coordinator) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Rebalance()
+		}
+	}
+}
+
+func (c *Coordinator) allocate(m *budgetMember, bytes int64) {
+	m.bytes = bytes
+	size := int(bytes / m.entryBytes)
+	if size < 1 {
+		size = 1
+	}
+	m.cache.Resize(size)
+}
@@ -0,0 +1,78 @@
+package lru
+
+import "testing"
+
+func TestCoordinator(t *testing.T) {
+	hot := New[int, int](1)
+	cold := New[int, int](1)
+
+	c := NewCoordinator(1000)
+	c.Register(hot, 10)
+	c.Register(cold, 10)
+	if bytes, _ := c.Allocation(hot); bytes != 500 {
+		t.Fatalf("Expected %v, got %v", 500, bytes)
+	}
+
+	// Nothing learned yet
+	c.Rebalance()
+	if bytes, _ := c.Allocation(cold); bytes != 500 {
+		t.Fatalf("Expected %v, got %v", 500, bytes)
+	}
+
+	for i := 0; i < 50; i++ {
+		hot.Add(i, i)
+		hot.Get(i)
+		cold.Get(i)
+	}
+	for i := 0; i < 10; i++ {
+		hot.Get(i)
+		c.Rebalance()
+	}
+
+	hotBytes, _ := c.Allocation(hot)
+	coldBytes, _ := c.Allocation(cold)
+	if hotBytes <= coldBytes || coldBytes < 1000/2/coordinatorMinShare {
+		t.Fatalf("bad allocations: hot %v, cold %v", hotBytes, coldBytes)
+	}
+	if hotBytes+coldBytes > 1000 {
+		t.Fatalf("over budget: %v", hotBytes+coldBytes)
+	}
+	if hot.lru.(*unsafeCache[int, int]).maxEntries != int(hotBytes/10) {
+		t.Fatal("hot cache not resized")
+	}
+
+	if !c.Unregister(cold) || c.Unregister(cold) {
+		t.Fatal("should be unregistered once")
+	}
+	c.Rebalance()
+	if hotBytes, _ = c.Allocation(hot); hotBytes <= 500 {
+		t.Fatalf("freed share not redistributed: %v", hotBytes)
+	}
+}
+
+func TestCoordinator_StatsDelta(t *testing.T) {
+	hot := New[int, int](10)
+	cold := New[int, int](10)
+	hot.Add(1, 1)
+	cold.Add(1, 1)
+
+	c := NewCoordinator(1000)
+	c.Register(hot, 10)
+	c.Register(cold, 10)
+
+	cold.Get(1)
+	c.Rebalance()
+	// A scraper reading the deltas does not disturb the coordinator
+	cold.StatsDelta()
+	hot.Get(1)
+	c.Rebalance()
+
+	hotBytes, _ := c.Allocation(hot)
+	coldBytes, _ := c.Allocation(cold)
+	if hotBytes <= coldBytes {
+		t.Fatalf("bad allocations: hot %v, cold %v", hotBytes, coldBytes)
+	}
+	if stats := cold.Stats(); stats.Hits != 1 {
+		t.Fatalf("Expected %v, got %v", 1, stats.Hits)
+	}
+}
@@ -0,0 +1,58 @@
+package lru
+
+import (
+	"math"
+	"time"
+
+	"github.com/electricbubble/lru/list"
+)
+
+// WithDecayedEviction tracks an exponentially decayed access rate per entry,
+// halving every halfLife, and evicts the entry with the lowest rate among
+// the candidates oldest entries instead of the oldest one. Frequently used
+// entries then survive a burst of one-time accesses, which improves the
+// hit ratio of mixed-frequency workloads. A policy set WithPolicy prevails.
+func WithDecayedEviction[K comparable, V any](halfLife time.Duration, candidates int) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		if halfLife <= 0 || candidates <= 0 {
+			return
+		}
+		c.decayHalfLife = halfLife
+		c.decayCandidates = candidates
+	}
+}
+
+// decayedRate returns the rate of the entry decayed until now.
+func (c *unsafeCache[K, V]) decayedRate(ent *entry[K, V], now time.Time) float64 {
+	elapsed := now.Sub(ent.rateAt)
+	if elapsed <= 0 {
+		return ent.rate
+	}
+	return ent.rate * math.Exp2(-float64(elapsed)/float64(c.decayHalfLife))
+}
+
+// recordAccess adds an access to the rate of the entry.
+func (c *unsafeCache[K, V]) recordAccess(ent *entry[K, V]) {
+	now := c.now()
+	ent.rate = c.decayedRate(ent, now) + 1
+	ent.rateAt = now
+}
+
+// leastDecayed returns the entry with the lowest decayed rate among the
+// oldest candidates, the oldest one on ties.
+func (c *unsafeCache[K, V]) leastDecayed() *list.Element[*entry[K, V]] {
+	now := c.now()
+	victim := c.entries.Back()
+	if victim == nil {
+		return nil
+	}
+	lowest := c.decayedRate(victim.Value, now)
+	elem := victim.Prev()
+	for i := 1; elem != nil && i < c.decayCandidates; i++ {
+		if rate := c.decayedRate(elem.Value, now); rate < lowest {
+			victim, lowest = elem, rate
+		}
+		elem = elem.Prev()
+	}
+	return victim
+}
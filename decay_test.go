@@ -0,0 +1,54 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLru_WithDecayedEviction(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	l := NewUnsafeLru[int, int](3, WithDecayedEviction[int, int](time.Minute, 3)).(*unsafeCache[int, int])
+	l.now = clock.now
+
+	l.Add(1, 1)
+	for i := 0; i < 5; i++ {
+		l.Get(1)
+	}
+	l.Add(2, 2)
+	l.Add(3, 3)
+	// 1 is the oldest, but the most used
+	l.Add(4, 4)
+	if !reflect.DeepEqual(l.Keys(), []int{1, 3, 4}) {
+		t.Fatalf("Expected %v, got %v", []int{1, 3, 4}, l.Keys())
+	}
+
+	// Its rate decays while the others are used
+	clock.advance(10 * time.Minute)
+	l.Get(3)
+	l.Get(4)
+	l.Add(5, 5)
+	if l.Contains(1) {
+		t.Fatalf("1 should be evicted: %v", l.Keys())
+	}
+
+	// The rate halves every half-life
+	elem, _ := l.bucket.get(3)
+	ent := elem.Value
+	if rate := l.decayedRate(ent, clock.now().Add(time.Minute)); rate != ent.rate/2 {
+		t.Fatalf("Expected %v, got %v", ent.rate/2, rate)
+	}
+}
+
+func TestLru_WithDecayedEviction_Candidates(t *testing.T) {
+	l := NewUnsafeLru[int, int](3, WithDecayedEviction[int, int](time.Minute, 1))
+	l.Add(1, 1)
+	l.Get(1)
+	l.Add(2, 2)
+	l.Add(3, 3)
+	l.Add(4, 4)
+	// A single candidate is plain LRU
+	if l.Contains(1) {
+		t.Fatalf("1 should be evicted: %v", l.Keys())
+	}
+}
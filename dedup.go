@@ -0,0 +1,73 @@
+package lru
+
+// WithDedup shares a single copy of the values which are equal according to
+// equal, and hash to the same value, across the keys of the cache. Values
+// are reference counted, and dropped from the shared table once no entry
+// holds them. It saves memory when many keys map to a few large identical
+// values of reference types, such as strings, slices or pointers, which
+// must then not be modified by the callers.
+func WithDedup[K comparable, V any](hash func(value V) uint64, equal func(a, b V) bool) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		c.dedup = &dedupTable[V]{
+			hash:  hash,
+			equal: equal,
+			slots: make(map[uint64][]*dedupSlot[V]),
+		}
+	}
+}
+
+// dedupTable interns the values of a cache.
+type dedupTable[V any] struct {
+	hash  func(value V) uint64
+	equal func(a, b V) bool
+	slots map[uint64][]*dedupSlot[V]
+}
+
+// dedupSlot is a shared value and its number of references.
+type dedupSlot[V any] struct {
+	value V
+	refs  int
+}
+
+// intern returns the shared copy of the value, adding a reference to it.
+func (d *dedupTable[V]) intern(value V) V {
+	h := d.hash(value)
+	for _, s := range d.slots[h] {
+		if d.equal(s.value, value) {
+			s.refs++
+			return s.value
+		}
+	}
+	d.slots[h] = append(d.slots[h], &dedupSlot[V]{value: value, refs: 1})
+	return value
+}
+
+// release drops a reference to the shared copy of the value.
+func (d *dedupTable[V]) release(value V) {
+	h := d.hash(value)
+	slots := d.slots[h]
+	for i, s := range slots {
+		if !d.equal(s.value, value) {
+			continue
+		}
+		if s.refs--; s.refs > 0 {
+			return
+		}
+		if len(slots) == 1 {
+			delete(d.slots, h)
+			return
+		}
+		slots[i] = slots[len(slots)-1]
+		slots[len(slots)-1] = nil
+		d.slots[h] = slots[:len(slots)-1]
+		return
+	}
+}
+
+// distinct returns the number of distinct values.
+func (d *dedupTable[V]) distinct() (n int) {
+	for _, slots := range d.slots {
+		n += len(slots)
+	}
+	return n
+}
@@ -0,0 +1,51 @@
+package lru
+
+import (
+	"bytes"
+	"hash/fnv"
+	"testing"
+)
+
+func TestLru_WithDedup(t *testing.T) {
+	hash := func(v []byte) uint64 {
+		h := fnv.New64a()
+		h.Write(v)
+		return h.Sum64()
+	}
+	l := NewUnsafeLru[int, []byte](3, WithDedup[int](hash, bytes.Equal)).(*unsafeCache[int, []byte])
+	l.Add(1, []byte("blob"))
+	l.Add(2, []byte("blob"))
+	l.Add(3, []byte("other"))
+
+	v1, _ := l.Peek(1)
+	v2, _ := l.Peek(2)
+	if &v1[0] != &v2[0] {
+		t.Fatal("equal values should be shared")
+	}
+	if n := l.dedup.distinct(); n != 2 {
+		t.Fatalf("Expected %v, got %v", 2, n)
+	}
+
+	// Updates, evictions and removals drop the references
+	l.Add(3, []byte("blob"))
+	if n := l.dedup.distinct(); n != 1 {
+		t.Fatalf("Expected %v, got %v", 1, n)
+	}
+	l.Add(4, []byte("new"))
+	l.Remove(2)
+	l.Remove(3)
+	if n := l.dedup.distinct(); n != 1 {
+		t.Fatalf("Expected %v, got %v", 1, n)
+	}
+	if l.AddNew(4, []byte("dup")) || l.dedup.distinct() != 1 {
+		t.Fatal("rejected value should not be referenced")
+	}
+	if v, _ := l.Peek(4); string(v) != "new" {
+		t.Fatalf("Expected %v, got %v", "new", v)
+	}
+
+	l.Clear()
+	if n := l.dedup.distinct(); n != 0 {
+		t.Fatalf("Expected %v, got %v", 0, n)
+	}
+}
@@ -0,0 +1,82 @@
+package lru
+
+import (
+	"sort"
+	"time"
+)
+
+// Entry is a cached key and value with their metadata.
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
+
+	// Hits is the number of Get hits served by the entry.
+	// It is always zero unless the cache was created WithHitCounting.
+	Hits uint64
+
+	// Cost is the weight of the entry, as computed by the weigher.
+	Cost int64
+
+	// ExpiresAt is when the entry becomes stale, zero means never.
+	ExpiresAt time.Time
+
+	// Source is the writer of the entry, set by AddWithSource.
+	Source string
+}
+
+func (c *unsafeCache[K, V]) GetEntry(key K) (e Entry[K, V], ok bool) {
+	key = c.normalize(key)
+	if _, ok = c.Get(key); !ok {
+		return e, false
+	}
+	elem, _ := c.bucket.get(key)
+	return c.export(elem.Value), true
+}
+
+func (c *unsafeCache[K, V]) PeekEntry(key K) (e Entry[K, V], ok bool) {
+	key = c.normalize(key)
+	elem, ok := c.bucket.get(key)
+	if !ok || c.expired(elem.Value) {
+		return e, false
+	}
+	return c.export(elem.Value), true
+}
+
+func (c *unsafeCache[K, V]) Items() []Entry[K, V] {
+	items := make([]Entry[K, V], c.entries.Len())
+	for i, elem := 0, c.entries.Back(); elem != nil; i, elem = i+1, elem.Prev() {
+		items[i] = c.export(elem.Value)
+	}
+	return items
+}
+
+func (c *unsafeCache[K, V]) MostAccessed(n int) []Entry[K, V] {
+	if !c.countHits || n <= 0 {
+		return nil
+	}
+
+	items := make([]Entry[K, V], 0, c.entries.Len())
+	for elem := c.entries.Front(); elem != nil; elem = elem.Next() {
+		items = append(items, c.export(elem.Value))
+	}
+	// Stable sort keeps the more recently used entry first on ties
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Hits > items[j].Hits
+	})
+	if n < len(items) {
+		items = items[:n]
+	}
+	return items
+}
+
+// export returns the exported form of the entry, with its loaded value.
+func (c *unsafeCache[K, V]) export(ent *entry[K, V]) Entry[K, V] {
+	return Entry[K, V]{
+		Key:       ent.key,
+		Value:     c.loadValue(ent.value),
+		Hits:      ent.hits,
+		Cost:      ent.cost,
+		ExpiresAt: ent.expiresAt,
+		Source:    ent.source,
+	}
+}
@@ -0,0 +1,75 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_unsafeCache_GetEntry(t *testing.T) {
+	c := NewUnsafeLru[int, int](10, WithHitCounting[int, int]())
+	c.Add(1, 10)
+	c.Get(1)
+	c.Get(1)
+
+	e, ok := c.GetEntry(1)
+	if !ok {
+		t.Fatal("should exist")
+	}
+	if e.Key != 1 || e.Value != 10 || e.Hits != 3 {
+		t.Fatalf("bad entry: %+v", e)
+	}
+	if e, _ = c.PeekEntry(1); e.Hits != 3 {
+		t.Fatalf("Expected %v, got %v", 3, e.Hits)
+	}
+
+	if _, ok = c.GetEntry(2); ok {
+		t.Fatal("should not exist")
+	}
+
+	c = NewUnsafeLru[int, int](10)
+	c.Add(1, 10)
+	c.Get(1)
+	if e, _ = c.GetEntry(1); e.Hits != 0 {
+		t.Fatalf("Expected %v, got %v", 0, e.Hits)
+	}
+}
+
+func Test_unsafeCache_Items(t *testing.T) {
+	c := NewUnsafeLru[int, int](10)
+	for i := 0; i < 3; i++ {
+		c.Add(i, i*10)
+	}
+	c.Get(0)
+
+	expected := []Entry[int, int]{{Key: 1, Value: 10}, {Key: 2, Value: 20}, {Key: 0, Value: 0}}
+	if items := c.Items(); !reflect.DeepEqual(items, expected) {
+		t.Fatalf("Expected %v, got %v", expected, items)
+	}
+}
+
+func Test_unsafeCache_MostAccessed(t *testing.T) {
+	c := NewUnsafeLru[int, int](10, WithHitCounting[int, int]())
+	for i := 0; i < 5; i++ {
+		c.Add(i, i)
+		for j := 0; j < i%3; j++ {
+			c.Get(i)
+		}
+	}
+
+	items := c.MostAccessed(3)
+	keys := make([]int, 0, len(items))
+	for _, e := range items {
+		keys = append(keys, e.Key)
+	}
+	if expected := []int{2, 4, 1}; !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("Expected %v, got %v", expected, keys)
+	}
+
+	if items = c.MostAccessed(100); len(items) != c.Len() {
+		t.Fatalf("Expected %v, got %v", c.Len(), len(items))
+	}
+
+	if items = NewUnsafeLru[int, int](10).MostAccessed(3); items != nil {
+		t.Fatalf("Expected nil, got %v", items)
+	}
+}
@@ -0,0 +1,42 @@
+package lru
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors of the loading and persistence layers. Errors returned by
+// this module wrap them, so callers should test with errors.Is instead of
+// comparing or matching strings.
+var (
+	// ErrNotFound is returned when a key is missing and cannot be loaded.
+	// Loaders should return it, or an error wrapping it, for missing keys.
+	ErrNotFound = errors.New("lru: not found")
+
+	// ErrLoaderTimeout is matched by a LoadError whose loader ran out of time.
+	ErrLoaderTimeout = errors.New("lru: loader timeout")
+
+	// ErrClosed is returned when using a component that has been closed.
+	ErrClosed = errors.New("lru: closed")
+)
+
+// LoadError records a failed load and the key it was for.
+// Use errors.As to retrieve it, and errors.Is to test the cause.
+type LoadError[K comparable] struct {
+	Key K
+	Err error
+}
+
+func (e *LoadError[K]) Error() string {
+	return fmt.Sprintf("lru: load %v: %v", e.Key, e.Err)
+}
+
+func (e *LoadError[K]) Unwrap() error {
+	return e.Err
+}
+
+// Is reports a load cancelled by its deadline as ErrLoaderTimeout.
+func (e *LoadError[K]) Is(target error) bool {
+	return target == ErrLoaderTimeout && errors.Is(e.Err, context.DeadlineExceeded)
+}
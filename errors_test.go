@@ -0,0 +1,28 @@
+package lru
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestLoadError(t *testing.T) {
+	var err error = &LoadError[string]{Key: "k", Err: fmt.Errorf("db: %w", ErrNotFound)}
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected %v, got %v", ErrNotFound, err)
+	}
+	if errors.Is(err, ErrLoaderTimeout) {
+		t.Fatalf("should not be %v", ErrLoaderTimeout)
+	}
+
+	err = fmt.Errorf("wrapped: %w", &LoadError[string]{Key: "k", Err: context.DeadlineExceeded})
+	if !errors.Is(err, ErrLoaderTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected %v, got %v", ErrLoaderTimeout, err)
+	}
+
+	var loadErr *LoadError[string]
+	if !errors.As(err, &loadErr) || loadErr.Key != "k" {
+		t.Fatalf("Expected %v, got %v", "k", err)
+	}
+}
@@ -0,0 +1,158 @@
+package lru
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+const defaultEventBuffer = 64
+
+// EventKind identifies what happened to a cache entry.
+type EventKind uint8
+
+const (
+	// EventAdd is published when a new key is added.
+	EventAdd EventKind = iota + 1
+	// EventUpdate is published when the value of an existing key is replaced.
+	EventUpdate
+	// EventHit is published when Get finds the key.
+	EventHit
+	// EventMiss is published when Get does not find the key.
+	EventMiss
+	// EventEvict is published when an entry is evicted to make room.
+	EventEvict
+	// EventExpire is published when an entry is dropped for being stale.
+	EventExpire
+	// EventRemove is published when an entry is removed by the caller.
+	EventRemove
+	// EventClear is published once when the cache is cleared.
+	EventClear
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventAdd:
+		return "add"
+	case EventUpdate:
+		return "update"
+	case EventHit:
+		return "hit"
+	case EventMiss:
+		return "miss"
+	case EventEvict:
+		return "evict"
+	case EventExpire:
+		return "expire"
+	case EventRemove:
+		return "remove"
+	case EventClear:
+		return "clear"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is published to subscribers of a cache. The entry is the zero
+// value for EventClear, and only has its Key for EventMiss.
+type Event[K comparable, V any] struct {
+	Kind EventKind
+	Entry[K, V]
+}
+
+// WithEventBuffer sets the buffer size of the channels returned by Subscribe.
+// Events published to a full channel are dropped and counted by DroppedEvents.
+func WithEventBuffer[K comparable, V any](size int) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		if size > 0 {
+			c.events.buffer = size
+		}
+	}
+}
+
+// eventHub fans out events to subscribers without ever blocking the publisher.
+// It has its own lock so subscriptions can be cancelled from any goroutine.
+type eventHub[K comparable, V any] struct {
+	// n is the number of subscribers, read atomically on the hot path
+	// so that subscriptions need not hold the lock of the cache.
+	n       int32
+	dropped uint64
+
+	buffer int
+	subs   map[chan Event[K, V]]struct{}
+
+	// observers are notified synchronously, they are only
+	// registered by the options so they need no lock.
+	observers []Observer[K, V]
+
+	mu sync.RWMutex
+}
+
+func newEventHub[K comparable, V any]() *eventHub[K, V] {
+	return &eventHub[K, V]{
+		buffer: defaultEventBuffer,
+		subs:   make(map[chan Event[K, V]]struct{}),
+	}
+}
+
+func (h *eventHub[K, V]) subscribe() (<-chan Event[K, V], func()) {
+	ch := make(chan Event[K, V], h.buffer)
+
+	h.mu.Lock()
+	h.subs[ch] = struct{}{}
+	atomic.AddInt32(&h.n, 1)
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subs, ch)
+			atomic.AddInt32(&h.n, -1)
+			close(ch)
+			h.mu.Unlock()
+		})
+	}
+}
+
+// listened reports whether the events are observed or subscribed to.
+func (h *eventHub[K, V]) listened() bool {
+	return len(h.observers) > 0 || atomic.LoadInt32(&h.n) > 0
+}
+
+func (h *eventHub[K, V]) publish(kind EventKind, e Entry[K, V]) {
+	if len(h.observers) > 0 {
+		h.notify(kind, e)
+	}
+	if atomic.LoadInt32(&h.n) == 0 {
+		return
+	}
+
+	ev := Event[K, V]{Kind: kind, Entry: e}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch := range h.subs {
+		select {
+		case ch <- ev:
+		default:
+			atomic.AddUint64(&h.dropped, 1)
+		}
+	}
+}
+
+// publish publishes the event of an entry, which is only exported, loading
+// its value, when the events are listened to.
+func (c *unsafeCache[K, V]) publish(kind EventKind, ent *entry[K, V]) {
+	if c.events.listened() {
+		c.events.publish(kind, c.export(ent))
+	}
+}
+
+func (c *unsafeCache[K, V]) Subscribe() (events <-chan Event[K, V], cancel func()) {
+	return c.events.subscribe()
+}
+
+func (c *unsafeCache[K, V]) DroppedEvents() uint64 {
+	return atomic.LoadUint64(&c.events.dropped)
+}
@@ -0,0 +1,56 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_unsafeCache_Subscribe(t *testing.T) {
+	c := NewUnsafeLru[int, int](2)
+	events, cancel := c.Subscribe()
+
+	c.Add(1, 1)
+	c.Add(1, 10)
+	c.Get(1)
+	c.Get(2)
+	c.Add(2, 2)
+	c.Add(3, 3)
+	c.Remove(2)
+	c.Clear()
+	cancel()
+	cancel()
+
+	var kinds []EventKind
+	for ev := range events {
+		kinds = append(kinds, ev.Kind)
+	}
+	expected := []EventKind{
+		EventAdd, EventUpdate, EventHit, EventMiss, EventAdd,
+		EventAdd, EventEvict, EventRemove, EventClear,
+	}
+	if !reflect.DeepEqual(kinds, expected) {
+		t.Fatalf("Expected %v, got %v", expected, kinds)
+	}
+
+	// No subscribers, nothing is published
+	c.Add(4, 4)
+	if c.DroppedEvents() != 0 {
+		t.Fatalf("Expected %v, got %v", 0, c.DroppedEvents())
+	}
+}
+
+func Test_unsafeCache_DroppedEvents(t *testing.T) {
+	c := NewUnsafeLru[int, int](10, WithEventBuffer[int, int](2))
+	events, cancel := c.Subscribe()
+	defer cancel()
+
+	for i := 0; i < 5; i++ {
+		c.Add(i, i)
+	}
+	if c.DroppedEvents() != 3 {
+		t.Fatalf("Expected %v, got %v", 3, c.DroppedEvents())
+	}
+	if ev := <-events; ev.Kind != EventAdd || ev.Key != 0 {
+		t.Fatalf("bad event: %v", ev)
+	}
+}
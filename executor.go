@@ -0,0 +1,111 @@
+package lru
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// callbackExecutor is the executor of SetCallbackExecutor.
+var callbackExecutor atomic.Pointer[CallbackExecutor]
+
+// SetCallbackExecutor makes the asynchronous eviction callbacks of every
+// cache, from WithOnEvictedAsync and WithOnEvictedContext, run on e, so
+// that an application with many caches bounds their total concurrency in
+// one place. A nil e restores the default of a goroutine per callback.
+// WithOrderedCallbacks still runs the callbacks of its cache on their own
+// goroutine.
+func SetCallbackExecutor(e *CallbackExecutor) {
+	callbackExecutor.Store(e)
+}
+
+// ExecutorStats are the queue metrics of a CallbackExecutor.
+type ExecutorStats struct {
+	Running   int    // Callbacks running
+	Queued    int    // Callbacks waiting for a goroutine
+	MaxQueued int    // High-water mark of Queued
+	Executed  uint64 // Callbacks which returned
+}
+
+// CallbackExecutor runs callbacks on at most a fixed number of goroutines.
+// Callbacks submitted while all of them are busy are queued without
+// blocking, in submission order.
+type CallbackExecutor struct {
+	maxGoroutines int
+
+	mu    sync.Mutex
+	queue []func()
+	stats ExecutorStats
+}
+
+// NewCallbackExecutor creates an executor running at most maxGoroutines
+// callbacks at once, at least one.
+func NewCallbackExecutor(maxGoroutines int) *CallbackExecutor {
+	if maxGoroutines < 1 {
+		maxGoroutines = 1
+	}
+	return &CallbackExecutor{maxGoroutines: maxGoroutines}
+}
+
+// Go runs fn on a goroutine of the executor, or queues it if they are busy.
+func (e *CallbackExecutor) Go(fn func()) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.stats.Running < e.maxGoroutines {
+		e.stats.Running++
+		go e.work(fn)
+		return
+	}
+	e.queue = append(e.queue, fn)
+	e.stats.Queued++
+	if e.stats.Queued > e.stats.MaxQueued {
+		e.stats.MaxQueued = e.stats.Queued
+	}
+}
+
+// work runs fn, then the queued callbacks until the queue is empty.
+func (e *CallbackExecutor) work(fn func()) {
+	for {
+		fn()
+
+		e.mu.Lock()
+		e.stats.Executed++
+		if len(e.queue) == 0 {
+			e.queue = nil
+			e.stats.Running--
+			e.mu.Unlock()
+			return
+		}
+		fn = e.queue[0]
+		e.queue[0] = nil
+		e.queue = e.queue[1:]
+		e.stats.Queued--
+		e.mu.Unlock()
+	}
+}
+
+// Stats returns the queue metrics of the executor.
+func (e *CallbackExecutor) Stats() ExecutorStats {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.stats
+}
+
+// goAsync runs an asynchronous callback, on the executor of
+// SetCallbackExecutor if any, tracked by the wait group of Close.
+// It is skipped once the cache is closed.
+func (c *unsafeCache[K, V]) goAsync(fn func()) {
+	if !c.track() {
+		return
+	}
+	run := func() {
+		defer c.untrack()
+		fn()
+	}
+	if e := callbackExecutor.Load(); e != nil {
+		e.Go(run)
+		return
+	}
+	go run()
+}
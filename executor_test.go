@@ -0,0 +1,67 @@
+package lru
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCallbackExecutor(t *testing.T) {
+	e := NewCallbackExecutor(2)
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		e.Go(func() {
+			defer wg.Done()
+			<-release
+		})
+	}
+	if s := e.Stats(); s.Running != 2 || s.Queued != 3 || s.MaxQueued != 3 {
+		t.Fatalf("Expected %v, got %v", ExecutorStats{Running: 2, Queued: 3, MaxQueued: 3}, s)
+	}
+
+	close(release)
+	wg.Wait()
+	deadline := time.Now().Add(time.Second)
+	for e.Stats().Running != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if s := e.Stats(); s != (ExecutorStats{MaxQueued: 3, Executed: 5}) {
+		t.Fatalf("Expected %v, got %v", ExecutorStats{MaxQueued: 3, Executed: 5}, s)
+	}
+}
+
+func TestSetCallbackExecutor(t *testing.T) {
+	e := NewCallbackExecutor(1)
+	SetCallbackExecutor(e)
+	defer SetCallbackExecutor(nil)
+
+	release := make(chan struct{})
+	evicted := make(chan int, 10)
+	onEvicted := func(k, v int) {
+		<-release
+		evicted <- k
+	}
+	c1 := New[int, int](1, WithOnEvictedAsync(onEvicted))
+	c2 := New[int, int](1, WithOnEvictedAsync(onEvicted))
+	for i := 0; i < 3; i++ {
+		c1.Add(i, i)
+		c2.Add(i, i)
+	}
+
+	// The 4 callbacks of both caches share a single goroutine
+	if s := e.Stats(); s.Running != 1 || s.Queued != 3 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, 3, s.Running, s.Queued)
+	}
+	close(release)
+	for i := 0; i < 4; i++ {
+		select {
+		case <-evicted:
+		case <-time.After(time.Second):
+			t.Fatalf("Expected %v, got %v", 4, i)
+		}
+	}
+	c1.Close()
+	c2.Close()
+}
@@ -0,0 +1,130 @@
+package lru
+
+import "time"
+
+// expiryHeap is a min-heap of the entries with an expiry, ordered by
+// expiresAt. Entries store their 1-based position in heapIndex, zero
+// means that they are not in the heap.
+type expiryHeap[K comparable, V any] []*entry[K, V]
+
+// update moves the entry to its place after its expiry changed,
+// adding it to or removing it from the heap as needed.
+func (h *expiryHeap[K, V]) update(ent *entry[K, V]) {
+	switch {
+	case ent.expiresAt.IsZero():
+		h.remove(ent)
+	case ent.heapIndex == 0:
+		*h = append(*h, ent)
+		ent.heapIndex = len(*h)
+		h.up(len(*h) - 1)
+	default:
+		i := ent.heapIndex - 1
+		if !h.down(i) {
+			h.up(i)
+		}
+	}
+}
+
+// remove removes the entry from the heap if it is in it.
+func (h *expiryHeap[K, V]) remove(ent *entry[K, V]) {
+	if ent.heapIndex == 0 {
+		return
+	}
+	i, last := ent.heapIndex-1, len(*h)-1
+	if i != last {
+		h.swap(i, last)
+	}
+	(*h)[last] = nil
+	*h = (*h)[:last]
+	ent.heapIndex = 0
+	if i != last {
+		if !h.down(i) {
+			h.up(i)
+		}
+	}
+}
+
+// peek returns the entry expiring first.
+func (h expiryHeap[K, V]) peek() (ent *entry[K, V], ok bool) {
+	if len(h) == 0 {
+		return nil, false
+	}
+	return h[0], true
+}
+
+func (h expiryHeap[K, V]) less(i, j int) bool {
+	return h[i].expiresAt.Before(h[j].expiresAt)
+}
+
+func (h expiryHeap[K, V]) swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIndex = i + 1
+	h[j].heapIndex = j + 1
+}
+
+func (h expiryHeap[K, V]) up(j int) {
+	for j > 0 {
+		i := (j - 1) / 2 // parent
+		if !h.less(j, i) {
+			break
+		}
+		h.swap(i, j)
+		j = i
+	}
+}
+
+// down reports whether the element moved.
+func (h expiryHeap[K, V]) down(i0 int) bool {
+	i, n := i0, len(h)
+	for {
+		j1 := 2*i + 1
+		if j1 >= n {
+			break
+		}
+		j := j1 // left child
+		if j2 := j1 + 1; j2 < n && h.less(j2, j1) {
+			j = j2 // right child
+		}
+		if !h.less(j, i) {
+			break
+		}
+		h.swap(i, j)
+		i = j
+	}
+	return i > i0
+}
+
+func (c *unsafeCache[K, V]) NextExpiry() (at time.Time, ok bool) {
+	ent, ok := c.expiries.peek()
+	if !ok {
+		return at, false
+	}
+	return ent.expiresAt, true
+}
+
+// ByExpiry returns an iterator over the entries with a TTL or TTI, soonest
+// expiring first, skipping the ones already expired. It has the shape of
+// an iter.Seq2 and walks a snapshot of the expiry index, so the cache can
+// be modified during the iteration.
+func (c *unsafeCache[K, V]) ByExpiry() func(yield func(key K, value V) bool) {
+	h := make(expiryHeap[K, V], len(c.expiries))
+	for i, ent := range c.expiries {
+		h[i] = &entry[K, V]{key: ent.key, value: c.loadValue(ent.value), expiresAt: ent.expiresAt, heapIndex: i + 1}
+	}
+	now := c.now()
+	return func(yield func(key K, value V) bool) {
+		for {
+			ent, ok := h.peek()
+			if !ok {
+				return
+			}
+			h.remove(ent)
+			if !now.Before(ent.expiresAt) {
+				continue
+			}
+			if !yield(ent.key, ent.value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package lru
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func Test_unsafeCache_NextExpiry(t *testing.T) {
+	c, clock := newTTLCache(time.Hour)
+	if _, ok := c.NextExpiry(); ok {
+		t.Fatal("should be empty")
+	}
+
+	start := clock.t
+	c.AddWithTTL("a", 1, time.Minute)
+	c.AddWithTTL("b", 2, time.Second)
+	c.Add("c", 3)
+	if at, ok := c.NextExpiry(); !ok || !at.Equal(start.Add(time.Second)) {
+		t.Fatalf("Expected %v, %v, got %v, %v", start.Add(time.Second), true, at, ok)
+	}
+
+	// Without a time to live, the entry leaves the heap
+	c.AddWithTTL("b", 2, NoExpiration)
+	if at, _ := c.NextExpiry(); !at.Equal(start.Add(time.Minute)) {
+		t.Fatalf("Expected %v, got %v", start.Add(time.Minute), at)
+	}
+
+	c.Remove("a")
+	if at, _ := c.NextExpiry(); !at.Equal(start.Add(time.Hour)) {
+		t.Fatalf("Expected %v, got %v", start.Add(time.Hour), at)
+	}
+
+	c.Clear()
+	if _, ok := c.NextExpiry(); ok {
+		t.Fatal("should be empty")
+	}
+}
+
+func Test_expiryHeap_RandomOps(t *testing.T) {
+	c, clock := newTTLCache(0)
+	for i := 0; i < 20000; i++ {
+		key := string(rune('a' + rand.Intn(10)))
+		switch rand.Intn(4) {
+		case 0, 1:
+			c.AddWithTTL(key, i, time.Duration(rand.Intn(100))*time.Second)
+		case 2:
+			c.Remove(key)
+		case 3:
+			clock.advance(time.Duration(rand.Intn(10)) * time.Second)
+			c.RemoveExpired()
+		}
+
+		for j, ent := range c.expiries {
+			if ent.heapIndex != j+1 {
+				t.Fatalf("bad heap index: %d != %d", ent.heapIndex, j+1)
+			}
+			if j > 0 && ent.expiresAt.Before(c.expiries[(j-1)/2].expiresAt) {
+				t.Fatalf("heap order violated at %d", j)
+			}
+		}
+		for elem := c.entries.Front(); elem != nil; elem = elem.Next() {
+			if !elem.Value.expiresAt.IsZero() && elem.Value.heapIndex == 0 {
+				t.Fatalf("entry %v missing from heap", elem.Value.key)
+			}
+		}
+	}
+}
+
+func Test_unsafeCache_ByExpiry(t *testing.T) {
+	c, clock := newTTLCache(time.Hour)
+	c.AddWithTTL("a", 1, time.Minute)
+	c.AddWithTTL("b", 2, time.Second)
+	c.AddWithTTL("c", 3, NoExpiration)
+	c.Add("d", 4)
+	c.AddWithTTL("e", 5, time.Millisecond)
+	clock.advance(time.Millisecond)
+
+	var keys []string
+	c.ByExpiry()(func(k string, v int) bool {
+		keys = append(keys, k)
+		// The cache can be modified during the iteration
+		c.Remove(k)
+		return true
+	})
+	if fmt.Sprint(keys) != "[b a d]" || c.Len() != 2 {
+		t.Fatalf("Expected %v, got %v", "[b a d]", keys)
+	}
+
+	// Stops when yield returns false
+	c.AddWithTTL("f", 6, time.Second)
+	n := 0
+	c.ByExpiry()(func(k string, v int) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Fatalf("Expected %v, got %v", 1, n)
+	}
+}
@@ -0,0 +1,94 @@
+package lru
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Codec creates the encoders and decoders of the streaming Export and Import.
+// GobCodec is built in, the codec module provides msgpack and CBOR.
+type Codec interface {
+	NewEncoder(w io.Writer) Encoder
+	NewDecoder(r io.Reader) Decoder
+}
+
+// Encoder writes values to a stream.
+type Encoder interface {
+	Encode(v any) error
+}
+
+// Decoder reads values written by an Encoder, returning io.EOF at the end
+// of the stream.
+type Decoder interface {
+	Decode(v any) error
+}
+
+// GobCodec is a Codec using encoding/gob.
+type GobCodec struct{}
+
+func (GobCodec) NewEncoder(w io.Writer) Encoder {
+	return gob.NewEncoder(w)
+}
+
+func (GobCodec) NewDecoder(r io.Reader) Decoder {
+	return gob.NewDecoder(r)
+}
+
+// Export writes the entries of the cache to w one at a time, from oldest
+// to newest, with the codec, which defaults to GobCodec.
+func Export[K comparable, V any](w io.Writer, c Lru[K, V], codec Codec) error {
+	if codec == nil {
+		codec = GobCodec{}
+	}
+	enc := codec.NewEncoder(w)
+	for _, e := range c.Items() {
+		if err := enc.Encode(e); err != nil {
+			return fmt.Errorf("lru: export: %w", err)
+		}
+	}
+	return nil
+}
+
+// Import adds the entries written by Export to the cache until the end of
+// the stream, keeping their recency order and expirations, and returns the
+// number of entries added. The entries expired since the export are skipped.
+// An idle deadline of WithTTI is restored as a fixed time to live.
+func Import[K comparable, V any](r io.Reader, c Lru[K, V], codec Codec) (n int, err error) {
+	if codec == nil {
+		codec = GobCodec{}
+	}
+	dec := codec.NewDecoder(r)
+	for {
+		var e Entry[K, V]
+		err = dec.Decode(&e)
+		if errors.Is(err, io.EOF) {
+			return n, nil
+		}
+		if err != nil {
+			return n, fmt.Errorf("lru: import: %w", err)
+		}
+		if restore(c, e, time.Now()) {
+			n++
+		}
+	}
+}
+
+// restore adds the entry to the cache with its remaining time to live,
+// unless it expired. An entry without expiration is restored without one,
+// rather than with the default TTL of the cache. The expiration of an
+// entry does not tell a TTL from a TTI deadline, so both are restored as
+// a TTL.
+func restore[K comparable, V any](c Lru[K, V], e Entry[K, V], now time.Time) bool {
+	if e.ExpiresAt.IsZero() {
+		c.AddWithTTL(e.Key, e.Value, NoExpiration)
+		return true
+	}
+	if ttl := e.ExpiresAt.Sub(now); ttl > 0 {
+		c.AddWithTTL(e.Key, e.Value, ttl)
+		return true
+	}
+	return false
+}
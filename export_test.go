@@ -0,0 +1,69 @@
+package lru
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExport(t *testing.T) {
+	l := New[string, int](128)
+	l.Add("a", 1)
+	l.AddWithTTL("b", 2, time.Hour)
+	l.AddWithTTL("c", 3, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	var buf bytes.Buffer
+	if err := Export[string, int](&buf, l, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	imported := New[string, int](128, WithTTL[string, int](time.Minute))
+	n, err := Import[string, int](&buf, imported, GobCodec{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || !reflect.DeepEqual(imported.Keys(), []string{"a", "b"}) {
+		t.Fatalf("Expected %v, got %v", []string{"a", "b"}, imported.Keys())
+	}
+	// The entry without expiration does not get the default TTL
+	if e, _ := imported.PeekEntry("a"); !e.ExpiresAt.IsZero() {
+		t.Fatalf("Expected %v, got %v", time.Time{}, e.ExpiresAt)
+	}
+
+	if _, err = Import[string, int](bytes.NewBufferString("garbage"), imported, nil); err == nil {
+		t.Fatal("should fail")
+	}
+	if err = Export[string, int](failingWriter{}, l, nil); !errors.Is(err, errWrite) {
+		t.Fatalf("Expected %v, got %v", errWrite, err)
+	}
+}
+
+func TestExport_WithValueTransform(t *testing.T) {
+	tenfold := WithValueTransform[string, int](func(v int) int { return v * 10 }, func(v int) int { return v / 10 })
+	l := New[string, int](128, tenfold)
+	l.Add("a", 1)
+	l.AddWithTTL("b", 2, time.Hour)
+
+	var buf bytes.Buffer
+	if err := Export[string, int](&buf, l, nil); err != nil {
+		t.Fatal(err)
+	}
+	imported := New[string, int](128, tenfold)
+	if _, err := Import[string, int](&buf, imported, nil); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]int{"a": 1, "b": 2} {
+		if v, ok := imported.Peek(key); !ok || v != want {
+			t.Fatalf("Expected %v, got %v", want, v)
+		}
+	}
+}
+
+var errWrite = errors.New("write failed")
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errWrite }
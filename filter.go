@@ -0,0 +1,61 @@
+package lru
+
+import "github.com/electricbubble/lru/list"
+
+// evictionFilterScan bounds the number of entries vetoed by the eviction
+// filter which are skipped by one eviction.
+const evictionFilterScan = 16
+
+// WithEvictionFilter consults filter before evicting an entry to respect
+// the limits of the cache, e.g. not to evict the entries currently in use.
+// An entry for which it returns false is skipped, and the next oldest is
+// considered instead. After evictionFilterScan vetoes in a row, the first
+// candidate is evicted anyway, so that the cache stays within its limits.
+// RemoveOldest, Remove and expirations do not consult the filter.
+func WithEvictionFilter[K comparable, V any](filter func(key K, value V) bool) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		c.evictionFilter = filter
+	}
+}
+
+// filterVictim returns the first entry from elem which the eviction filter
+// allows to evict, in the order of the policy if it is a VictimsPolicy, of
+// recency otherwise, or elem after too many vetoes.
+func (c *unsafeCache[K, V]) filterVictim(elem *list.Element[*entry[K, V]]) *list.Element[*entry[K, V]] {
+	policy, ranked := c.policy.(VictimsPolicy[K])
+	var (
+		vetoed []K
+		skip   func(key K) bool
+	)
+
+	candidate := elem
+	for i := 0; candidate != nil && i < evictionFilterScan; i++ {
+		if c.evictionFilter(candidate.Value.key, candidate.Value.value) {
+			return candidate
+		}
+		if !ranked {
+			candidate = candidate.Prev()
+			continue
+		}
+		if skip == nil {
+			skip = func(key K) bool {
+				for _, k := range vetoed {
+					if k == key {
+						return true
+					}
+				}
+				return false
+			}
+		}
+		vetoed = append(vetoed, candidate.Value.key)
+		candidate = nil
+		if key, ok := policy.NextVictim(skip); ok {
+			candidate, _ = c.bucket.get(key)
+		}
+	}
+	if ranked {
+		// Make elem the victim of the policy again
+		policy.Victim()
+	}
+	return elem
+}
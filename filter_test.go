@@ -0,0 +1,91 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLru_WithEvictionFilter(t *testing.T) {
+	inUse := map[int]bool{1: true, 2: true}
+	l := New[int, int](3, WithEvictionFilter(func(key, value int) bool {
+		return !inUse[key]
+	}))
+	l.Add(1, 1)
+	l.Add(2, 2)
+	l.Add(3, 3)
+	l.Add(4, 4)
+	if !reflect.DeepEqual(l.Keys(), []int{1, 2, 4}) {
+		t.Fatalf("Expected %v, got %v", []int{1, 2, 4}, l.Keys())
+	}
+
+	// RemoveOldest does not consult the filter
+	if k, _, _ := l.RemoveOldest(); k != 1 {
+		t.Fatalf("Expected %v, got %v", 1, k)
+	}
+}
+
+func TestLru_WithEvictionFilter_Bounded(t *testing.T) {
+	l := New[int, int](evictionFilterScan+2, WithEvictionFilter(func(key, value int) bool {
+		return false
+	}))
+	for i := 0; i < evictionFilterScan+4; i++ {
+		l.Add(i, i)
+	}
+	// Everything is vetoed, the oldest entries are evicted anyway
+	if l.Len() != evictionFilterScan+2 || l.Contains(0) || l.Contains(1) {
+		t.Fatalf("bad keys: %v", l.Keys())
+	}
+}
+
+func TestLru_WithEvictionFilter_ExactBound(t *testing.T) {
+	calls := 0
+	l := New[int, int](evictionFilterScan+1, WithEvictionFilter(func(key, value int) bool {
+		calls++
+		return key >= evictionFilterScan
+	}))
+	for i := 0; i <= evictionFilterScan; i++ {
+		l.Add(i, i)
+	}
+
+	// The entry after evictionFilterScan vetoes is not considered
+	l.Add(-1, -1)
+	if calls != evictionFilterScan {
+		t.Fatalf("Expected %v, got %v", evictionFilterScan, calls)
+	}
+	if l.Contains(0) || !l.Contains(evictionFilterScan) {
+		t.Fatalf("bad keys: %v", l.Keys())
+	}
+}
+
+func TestLru_WithEvictionFilter_Policy(t *testing.T) {
+	l := New[int, int](3, WithPolicy[int, int](NewFIFOPolicy[int]), WithEvictionFilter(func(key, value int) bool {
+		return key != 1
+	}))
+	l.Add(1, 1)
+	l.Add(2, 2)
+	l.Add(3, 3)
+	l.Get(2)
+
+	// The next candidate is the next inserted key, not the next oldest used
+	l.Add(4, 4)
+	if !l.Contains(1) || l.Contains(2) || !l.Contains(3) {
+		t.Fatalf("bad keys: %v", l.Keys())
+	}
+}
+
+func TestARC_WithEvictionFilter(t *testing.T) {
+	c := NewUnsafeLru[int, int](2, WithPolicy[int, int](func() Policy[int] { return NewARCPolicy[int](2) }),
+		WithEvictionFilter(func(key, value int) bool {
+			return key != 1
+		})).(*unsafeCache[int, int])
+	c.Add(1, 1)
+	c.Add(2, 2)
+	c.Add(3, 3)
+	if !c.Contains(1) || c.Contains(2) {
+		t.Fatalf("bad keys: %v", c.Keys())
+	}
+	// The key evicted instead of the vetoed one goes to the ghost list
+	if p := c.policy.(*arcPolicy[int]); !p.b1.Contains(2) {
+		t.Fatalf("Expected %v, got %v", []int{2}, p.b1.Keys())
+	}
+}
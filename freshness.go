@@ -0,0 +1,64 @@
+package lru
+
+import "time"
+
+// Freshness tells whether a cached value is past its soft TTL.
+type Freshness uint8
+
+const (
+	// Fresh values are within their soft TTL.
+	Fresh Freshness = iota
+	// Stale values are past their soft TTL but not yet expired,
+	// they are still served while being refreshed.
+	Stale
+)
+
+func (f Freshness) String() string {
+	if f == Stale {
+		return "stale"
+	}
+	return "fresh"
+}
+
+// WithSoftTTL flags the entries as Stale softTTL after they were last
+// added or updated, while they keep being served until they expire after
+// the hard TTL of WithTTL or AddWithTTL. The first GetWithFreshness of a
+// stale entry asynchronously invokes refresh, if not nil, which typically
+// reloads the value and adds it back, making the entry fresh again.
+func WithSoftTTL[K comparable, V any](softTTL time.Duration, refresh func(key K, value V)) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		if softTTL > 0 {
+			c.softTTL = softTTL
+			c.refresh = refresh
+		}
+	}
+}
+
+func (c *unsafeCache[K, V]) GetWithFreshness(key K) (value V, freshness Freshness, ok bool) {
+	key = c.normalize(key)
+	if value, ok = c.Get(key); !ok {
+		return value, Fresh, false
+	}
+	elem, _ := c.bucket.get(key)
+	ent := elem.Value
+	if ent.staleAt.IsZero() || c.now().Before(ent.staleAt) {
+		return value, Fresh, true
+	}
+	if c.refresh != nil && !ent.refreshing {
+		ent.refreshing = true
+		c.refreshing(key, value)
+	}
+	return value, Stale, true
+}
+
+// refreshing invokes the refresh callback of a stale entry, unless
+// the cache is closed.
+func (c *unsafeCache[K, V]) refreshing(key K, value V) {
+	if !c.track() {
+		return
+	}
+	go func() {
+		defer c.untrack()
+		c.refresh(key, value)
+	}()
+}
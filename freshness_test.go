@@ -0,0 +1,69 @@
+package lru
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+// waitBackground waits for the background work of the cache to return.
+func waitBackground[K comparable, V any](c *unsafeCache[K, V]) {
+	for c.background.Load()&^backgroundClosed != 0 {
+		runtime.Gosched()
+	}
+}
+
+func Test_unsafeCache_WithSoftTTL(t *testing.T) {
+	refreshed := make(chan string, 10)
+	c, clock := newTTLCache(time.Minute, WithSoftTTL(10*time.Second, func(k string, v int) {
+		refreshed <- k
+	}))
+	defer c.Close()
+
+	c.Add("a", 1)
+	if v, f, ok := c.GetWithFreshness("a"); !ok || v != 1 || f != Fresh {
+		t.Fatalf("Expected %v, %v, %v, got %v, %v, %v", 1, Fresh, true, v, f, ok)
+	}
+
+	clock.advance(10 * time.Second)
+	if v, f, ok := c.GetWithFreshness("a"); !ok || v != 1 || f != Stale {
+		t.Fatalf("Expected %v, %v, %v, got %v, %v, %v", 1, Stale, true, v, f, ok)
+	}
+	// A single refresh per stale entry
+	c.GetWithFreshness("a")
+	if k := <-refreshed; k != "a" {
+		t.Fatalf("Expected %v, got %v", "a", k)
+	}
+	waitBackground(c)
+	if len(refreshed) != 0 {
+		t.Fatal("entry should be refreshed once")
+	}
+
+	// Writing the entry makes it fresh again
+	c.Add("a", 2)
+	if v, f, ok := c.GetWithFreshness("a"); !ok || v != 2 || f != Fresh {
+		t.Fatalf("Expected %v, %v, %v, got %v, %v, %v", 2, Fresh, true, v, f, ok)
+	}
+
+	// A miss after the hard TTL
+	clock.advance(time.Minute)
+	if _, _, ok := c.GetWithFreshness("a"); ok {
+		t.Fatal("entry should be expired")
+	}
+	waitBackground(c)
+	if len(refreshed) != 0 {
+		t.Fatal("expired entry should not be refreshed")
+	}
+}
+
+func TestLru_GetWithFreshness(t *testing.T) {
+	l := New[int, int](10, WithSoftTTL[int, int](time.Nanosecond, nil))
+	l.Add(1, 1)
+	time.Sleep(time.Millisecond)
+	if v, f, ok := l.GetWithFreshness(1); !ok || v != 1 || f != Stale || f.String() != "stale" {
+		t.Fatalf("Expected %v, %v, %v, got %v, %v, %v", 1, Stale, true, v, f, ok)
+	}
+	if _, f, ok := l.GetWithFreshness(2); ok || f != Fresh {
+		t.Fatal("missing key should be a miss")
+	}
+}
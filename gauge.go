@@ -0,0 +1,45 @@
+package lru
+
+import "math"
+
+// sizeGaugeSteps is the number of steps of the capacity reported by the
+// size gauge, so that it is only invoked when the size changed noticeably.
+const sizeGaugeSteps = 100
+
+// WithSizeGauge sets a gauge invoked with the number of entries and the
+// capacity of the cache when they change, e.g. to feed an autoscaler.
+// Calls are debounced: the gauge is only invoked once the size moved by
+// at least 1% of the capacity, when the cache gets empty or full, or when
+// the capacity changes. It is called while the cache is locked, so it
+// must not use the cache.
+func WithSizeGauge[K comparable, V any](gauge func(len, cap int)) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		c.sizeGauge = gauge
+		c.gaugedLen, c.gaugedCap = -1, -1
+	}
+}
+
+// gauge invokes the size gauge if the size changed noticeably.
+func (c *unsafeCache[K, V]) gauge() {
+	if c.sizeGauge == nil {
+		return
+	}
+	n, capacity := c.entries.Len(), c.maxEntries
+
+	step := 1
+	if capacity != math.MaxInt && capacity/sizeGaugeSteps > 1 {
+		step = capacity / sizeGaugeSteps
+	}
+	diff := n - c.gaugedLen
+	if diff < 0 {
+		diff = -diff
+	}
+	if capacity == c.gaugedCap && diff < step && n != 0 && n != capacity {
+		return
+	}
+	if capacity == c.gaugedCap && n == c.gaugedLen {
+		return
+	}
+	c.gaugedLen, c.gaugedCap = n, capacity
+	c.sizeGauge(n, capacity)
+}
@@ -0,0 +1,32 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_unsafeCache_WithSizeGauge(t *testing.T) {
+	var reports [][2]int
+	c := NewUnsafeLru[int, int](300, WithSizeGauge[int, int](func(n, capacity int) {
+		reports = append(reports, [2]int{n, capacity})
+	}))
+
+	for i := 0; i < 400; i++ {
+		c.Add(i, i)
+	}
+	// The first entry, then every 3 entries until full
+	if len(reports) != 101 || reports[0] != [2]int{1, 300} || reports[100] != [2]int{300, 300} {
+		t.Fatalf("bad reports: %d %v", len(reports), reports)
+	}
+
+	reports = nil
+	c.Resize(150)
+	c.Clear()
+	expected := [][2]int{{297, 300}, {294, 300}}
+	if !reflect.DeepEqual(reports[:2], expected) {
+		t.Fatalf("Expected %v, got %v", expected, reports[:2])
+	}
+	if last := reports[len(reports)-1]; last != [2]int{0, 150} {
+		t.Fatalf("Expected %v, got %v", [2]int{0, 150}, last)
+	}
+}
@@ -0,0 +1,47 @@
+package lru
+
+import "time"
+
+// ValueWithExpiry is a cached value with its expiry.
+type ValueWithExpiry[V any] struct {
+	Value V
+
+	// ExpiresAt is when the entry becomes stale, zero means never.
+	ExpiresAt time.Time
+
+	// TTL is the remaining time to live of the entry when it was read,
+	// zero if it never expires.
+	TTL time.Duration
+}
+
+func (c *unsafeCache[K, V]) GetManyWithExpiry(keys []K) map[K]ValueWithExpiry[V] {
+	values := make(map[K]ValueWithExpiry[V], len(keys))
+	now := c.now()
+	for _, key := range keys {
+		e, ok := c.GetEntry(key)
+		if !ok {
+			continue
+		}
+		v := ValueWithExpiry[V]{Value: e.Value, ExpiresAt: e.ExpiresAt}
+		if !e.ExpiresAt.IsZero() {
+			v.TTL = e.ExpiresAt.Sub(now)
+		}
+		values[key] = v
+	}
+	return values
+}
+
+// MinTTL returns the smallest remaining TTL of the values, typically to
+// derive the max-age of a response combining them. ok is false if none
+// of them expires.
+func MinTTL[K comparable, V any](values map[K]ValueWithExpiry[V]) (ttl time.Duration, ok bool) {
+	for _, v := range values {
+		if v.ExpiresAt.IsZero() {
+			continue
+		}
+		if !ok || v.TTL < ttl {
+			ttl, ok = v.TTL, true
+		}
+	}
+	return ttl, ok
+}
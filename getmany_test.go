@@ -0,0 +1,35 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_unsafeCache_GetManyWithExpiry(t *testing.T) {
+	c, clock := newTTLCache(time.Hour)
+	c.Add("a", 1)
+	c.AddWithTTL("b", 2, time.Minute)
+	c.AddWithTTL("c", 3, NoExpiration)
+	clock.advance(10 * time.Second)
+
+	values := c.GetManyWithExpiry([]string{"a", "b", "c", "d"})
+	if len(values) != 3 {
+		t.Fatalf("Expected %v, got %v", 3, len(values))
+	}
+	if v := values["a"]; v.Value != 1 || v.TTL != time.Hour-10*time.Second {
+		t.Fatalf("bad value: %+v", v)
+	}
+	if v := values["b"]; v.Value != 2 || v.TTL != 50*time.Second || !v.ExpiresAt.Equal(clock.t.Add(50*time.Second)) {
+		t.Fatalf("bad value: %+v", v)
+	}
+	if v := values["c"]; v.Value != 3 || v.TTL != 0 || !v.ExpiresAt.IsZero() {
+		t.Fatalf("bad value: %+v", v)
+	}
+	if ttl, ok := MinTTL(values); !ok || ttl != 50*time.Second {
+		t.Fatalf("Expected %v, %v, got %v, %v", 50*time.Second, true, ttl, ok)
+	}
+
+	if _, ok := MinTTL(c.GetManyWithExpiry([]string{"c"})); ok {
+		t.Fatal("entry should never expire")
+	}
+}
@@ -0,0 +1,111 @@
+package lru
+
+import (
+	"sync"
+
+	"github.com/electricbubble/lru/list"
+)
+
+// Handle refers to a cache entry, so that it can be operated on repeatedly
+// without hashing its key every time. It becomes invalid once the entry
+// leaves the cache, its methods then report false. The handles of a Cache
+// take its lock, the ones of an unsafe Lru are not safe for concurrent use.
+type Handle[K comparable, V any] struct {
+	c    *unsafeCache[K, V]
+	elem *list.Element[*entry[K, V]]
+	mu   *sync.RWMutex
+}
+
+func (c *unsafeCache[K, V]) GetHandle(key K) (h Handle[K, V], ok bool) {
+	key = c.normalize(key)
+	if _, ok = c.Get(key); !ok {
+		return h, false
+	}
+	elem, _ := c.bucket.get(key)
+	return Handle[K, V]{c: c, elem: elem}, true
+}
+
+// Key returns the key of the entry.
+func (h Handle[K, V]) Key() (key K, ok bool) {
+	h.lock(false)
+	defer h.unlock(false)
+
+	if !h.valid() {
+		return key, false
+	}
+	return h.elem.Value.key, true
+}
+
+// Value returns the value of the entry, without updating its recency.
+func (h Handle[K, V]) Value() (value V, ok bool) {
+	h.lock(false)
+	defer h.unlock(false)
+
+	if !h.valid() {
+		return value, false
+	}
+	return h.c.loadValue(h.elem.Value.value), true
+}
+
+// Touch makes the entry the most recently used one, and restarts its
+// time to idle, without counting a hit.
+func (h Handle[K, V]) Touch() (ok bool) {
+	h.lock(true)
+	defer h.unlock(true)
+
+	if !h.valid() {
+		return false
+	}
+	h.c.observe(h.elem)
+	return true
+}
+
+// SetValue replaces the value of the entry like Add.
+func (h Handle[K, V]) SetValue(value V) (ok bool) {
+	h.lock(true)
+	defer h.unlock(true)
+
+	if !h.valid() {
+		return false
+	}
+	stored := h.c.storeValue(value)
+	h.c.update(h.elem, stored, h.c.weigh(h.elem.Value.key, stored), h.c.ttl)
+	return true
+}
+
+// Remove removes the entry from the cache.
+func (h Handle[K, V]) Remove() (ok bool) {
+	h.lock(true)
+	defer h.unlock(true)
+
+	if h.elem == nil || h.elem.List() != h.c.entries {
+		return false
+	}
+	h.c.removeElement(h.elem, EventRemove)
+	return true
+}
+
+// valid reports whether the entry is still cached and fresh.
+func (h Handle[K, V]) valid() bool {
+	return h.elem != nil && h.elem.List() == h.c.entries && !h.c.expired(h.elem.Value)
+}
+
+func (h Handle[K, V]) lock(write bool) {
+	switch {
+	case h.mu == nil:
+	case write:
+		h.mu.Lock()
+	default:
+		h.mu.RLock()
+	}
+}
+
+func (h Handle[K, V]) unlock(write bool) {
+	switch {
+	case h.mu == nil:
+	case write:
+		h.mu.Unlock()
+	default:
+		h.mu.RUnlock()
+	}
+}
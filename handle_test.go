@@ -0,0 +1,75 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLru_GetHandle(t *testing.T) {
+	l := New[int, int](2, WithSlabAllocation[int, int](2))
+	l.Add(1, 1)
+	l.Add(2, 2)
+	if _, ok := l.GetHandle(3); ok {
+		t.Fatal("missing key should have no handle")
+	}
+
+	h, ok := l.GetHandle(1)
+	if !ok {
+		t.Fatal("handle should be returned")
+	}
+	if k, _ := h.Key(); k != 1 {
+		t.Fatalf("Expected %v, got %v", 1, k)
+	}
+	if !h.SetValue(10) {
+		t.Fatal("value should be set")
+	}
+	if v, ok := h.Value(); !ok || v != 10 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 10, true, v, ok)
+	}
+	l.Get(2)
+	if !h.Touch() {
+		t.Fatal("entry should be touched")
+	}
+	l.Add(3, 3)
+	if !l.Contains(1) || l.Contains(2) {
+		t.Fatal("touched entry should be kept")
+	}
+
+	if !h.Remove() || l.Contains(1) {
+		t.Fatal("entry should be removed")
+	}
+	// The handle is invalid, even if the entry memory is reused
+	l.Add(4, 4)
+	if _, ok := h.Value(); ok || h.Touch() || h.SetValue(1) || h.Remove() {
+		t.Fatal("handle should be invalid")
+	}
+	if l.Len() != 2 {
+		t.Fatalf("Expected %v, got %v", 2, l.Len())
+	}
+
+	// Clear invalidates the handles
+	h, _ = l.GetHandle(4)
+	l.Clear()
+	l.Add(4, 4)
+	if _, ok := h.Value(); ok {
+		t.Fatal("handle should be invalid")
+	}
+
+	var zero Handle[int, int]
+	if _, ok := zero.Value(); ok || zero.Remove() {
+		t.Fatal("zero handle should be invalid")
+	}
+}
+
+func Test_unsafeCache_GetHandle_Expired(t *testing.T) {
+	c, clock := newTTLCache(time.Minute)
+	c.Add("a", 1)
+	h, _ := c.GetHandle("a")
+	clock.advance(time.Minute)
+	if _, ok := h.Value(); ok || h.Touch() {
+		t.Fatal("expired entry should be invalid")
+	}
+	if !h.Remove() || c.Len() != 0 {
+		t.Fatal("expired entry should be removed")
+	}
+}
@@ -0,0 +1,75 @@
+package hashicorplru
+
+import (
+	"errors"
+
+	"github.com/electricbubble/lru"
+)
+
+const (
+	// Default2QRecentRatio is the ratio of the 2Q cache dedicated
+	// to recently added entries that have only been accessed once.
+	Default2QRecentRatio = 0.25
+
+	// Default2QGhostEntries is the default ratio of ghost
+	// entries kept to track entries recently evicted
+	Default2QGhostEntries = 0.50
+)
+
+// TwoQueueCache is a thread-safe fixed size 2Q cache, see lru.TwoQueueCache.
+type TwoQueueCache[K comparable, V any] struct {
+	*lru.TwoQueueCache[K, V]
+}
+
+// New2Q creates a new TwoQueueCache using the default
+// values for the parameters.
+func New2Q[K comparable, V any](size int) (*TwoQueueCache[K, V], error) {
+	return New2QParams[K, V](size, Default2QRecentRatio, Default2QGhostEntries)
+}
+
+// New2QWithEvict creates a new TwoQueueCache using the default
+// values for the parameters and a callback to receive evicted values.
+func New2QWithEvict[K comparable, V any](size int, onEvicted func(key K, value V)) (*TwoQueueCache[K, V], error) {
+	return new2Q(size, Default2QRecentRatio, Default2QGhostEntries, onEvicted)
+}
+
+// New2QParams creates a new TwoQueueCache using the provided
+// parameter values.
+func New2QParams[K comparable, V any](size int, recentRatio, ghostRatio float64) (*TwoQueueCache[K, V], error) {
+	return new2Q[K, V](size, recentRatio, ghostRatio, nil)
+}
+
+func new2Q[K comparable, V any](size int, recentRatio, ghostRatio float64, onEvicted func(key K, value V)) (*TwoQueueCache[K, V], error) {
+	if size <= 0 {
+		return nil, errSize
+	}
+	if recentRatio < 0.0 || recentRatio > 1.0 {
+		return nil, errors.New("invalid recent ratio")
+	}
+	if ghostRatio < 0.0 || ghostRatio > 1.0 {
+		return nil, errors.New("invalid ghost ratio")
+	}
+	var opts []lru.Option[K, V]
+	if onEvicted != nil {
+		opts = append(opts, lru.WithOnEvicted(onEvicted))
+	}
+	return &TwoQueueCache[K, V]{lru.New2QParams(size, recentRatio, ghostRatio, opts...)}, nil
+}
+
+// Values returns a slice of the values in the cache.
+// The frequently used values are first in the returned slice.
+func (c *TwoQueueCache[K, V]) Values() []V {
+	keys := c.Keys()
+	values := make([]V, 0, len(keys))
+	for _, k := range keys {
+		if v, ok := c.Peek(k); ok {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// Purge is used to completely clear the cache.
+func (c *TwoQueueCache[K, V]) Purge() {
+	c.Clear()
+}
@@ -0,0 +1,40 @@
+// Package arc mirrors the arc package of github.com/hashicorp/golang-lru/v2
+// on top of lru.ARCCache.
+package arc
+
+import (
+	"errors"
+
+	"github.com/electricbubble/lru"
+)
+
+// ARCCache is a thread-safe fixed size Adaptive Replacement Cache (ARC),
+// see lru.ARCCache.
+type ARCCache[K comparable, V any] struct {
+	*lru.ARCCache[K, V]
+}
+
+// NewARC creates an ARC of the given size.
+func NewARC[K comparable, V any](size int) (*ARCCache[K, V], error) {
+	if size <= 0 {
+		return nil, errors.New("must provide a positive size")
+	}
+	return &ARCCache[K, V]{lru.NewARC[K, V](size)}, nil
+}
+
+// Values returns all the cached values.
+func (c *ARCCache[K, V]) Values() []V {
+	keys := c.Keys()
+	values := make([]V, 0, len(keys))
+	for _, k := range keys {
+		if v, ok := c.Peek(k); ok {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// Purge is used to clear the cache.
+func (c *ARCCache[K, V]) Purge() {
+	c.Clear()
+}
@@ -0,0 +1,27 @@
+package arc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestARC(t *testing.T) {
+	if _, err := NewARC[int, int](0); err == nil {
+		t.Fatal("should fail")
+	}
+
+	l, err := NewARC[int, int](2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Add(1, 1)
+	l.Add(2, 2)
+	l.Get(1)
+	if !reflect.DeepEqual(l.Values(), []int{2, 1}) {
+		t.Fatalf("bad values: %v", l.Values())
+	}
+	l.Purge()
+	if l.Len() != 0 {
+		t.Fatalf("bad len: %v", l.Len())
+	}
+}
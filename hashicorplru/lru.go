@@ -0,0 +1,167 @@
+// Package hashicorplru mirrors the API of the root package of
+// github.com/hashicorp/golang-lru/v2 on top of the caches of package lru,
+// so that a project can switch with an aliased import,
+//
+//	lru "github.com/electricbubble/lru/hashicorplru"
+//
+// and adopt the features of package lru gradually. The ARC cache is in
+// the arc subpackage, as in hashicorp/golang-lru.
+package hashicorplru
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/electricbubble/lru"
+)
+
+// errSize is returned for a size that is not positive, like hashicorp/golang-lru does.
+var errSize = errors.New("must provide a positive size")
+
+// New creates an LRU of the given size.
+func New[K comparable, V any](size int) (*Cache[K, V], error) {
+	return NewWithEvict[K, V](size, nil)
+}
+
+// NewWithEvict constructs a fixed size cache with the given eviction
+// callback.
+func NewWithEvict[K comparable, V any](size int, onEvicted func(key K, value V)) (c *Cache[K, V], err error) {
+	if size <= 0 {
+		return nil, errSize
+	}
+	var opts []lru.Option[K, V]
+	if onEvicted != nil {
+		opts = append(opts, lru.WithOnEvicted(onEvicted))
+	}
+	return &Cache[K, V]{lru: lru.NewUnsafeLru[K, V](size, opts...)}, nil
+}
+
+// Cache is a thread-safe fixed size LRU cache.
+type Cache[K comparable, V any] struct {
+	lru lru.Lru[K, V]
+
+	lock sync.RWMutex
+}
+
+// Purge is used to completely clear the cache.
+func (c *Cache[K, V]) Purge() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.lru.Clear()
+}
+
+// Add adds a value to the cache. Returns true if an eviction occurred.
+func (c *Cache[K, V]) Add(key K, value V) (evicted bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.lru.Add(key, value)
+}
+
+// Get looks up a key's value from the cache.
+func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.lru.Get(key)
+}
+
+// Contains checks if a key is in the cache, without updating the
+// recent-ness or deleting it for being stale.
+func (c *Cache[K, V]) Contains(key K) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.lru.Contains(key)
+}
+
+// Peek returns the key value (or undefined if not found) without updating
+// the "recently used"-ness of the key.
+func (c *Cache[K, V]) Peek(key K) (value V, ok bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.lru.Peek(key)
+}
+
+// ContainsOrAdd checks if a key is in the cache without updating the
+// recent-ness or deleting it for being stale, and if not, adds the value.
+// Returns whether found and whether an eviction occurred.
+func (c *Cache[K, V]) ContainsOrAdd(key K, value V) (ok, evicted bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.lru.ContainsOrAdd(key, value)
+}
+
+// PeekOrAdd checks if a key is in the cache without updating the
+// recent-ness or deleting it for being stale, and if not, adds the value.
+// Returns whether found and whether an eviction occurred.
+func (c *Cache[K, V]) PeekOrAdd(key K, value V) (previous V, ok, evicted bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.lru.PeekOrAdd(key, value)
+}
+
+// Remove removes the provided key from the cache.
+func (c *Cache[K, V]) Remove(key K) (present bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.lru.Remove(key)
+}
+
+// Resize changes the cache size.
+func (c *Cache[K, V]) Resize(size int) (evicted int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.lru.Resize(size)
+}
+
+// RemoveOldest removes the oldest item from the cache.
+func (c *Cache[K, V]) RemoveOldest() (key K, value V, ok bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.lru.RemoveOldest()
+}
+
+// GetOldest returns the oldest entry
+func (c *Cache[K, V]) GetOldest() (key K, value V, ok bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.lru.GetOldest()
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest.
+func (c *Cache[K, V]) Keys() []K {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.lru.Keys()
+}
+
+// Values returns a slice of the values in the cache, from oldest to newest.
+func (c *Cache[K, V]) Values() []V {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	items := c.lru.Items()
+	values := make([]V, len(items))
+	for i, e := range items {
+		values[i] = e.Value
+	}
+	return values
+}
+
+// Len returns the number of items in the cache.
+func (c *Cache[K, V]) Len() int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.lru.Len()
+}
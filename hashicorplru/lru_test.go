@@ -0,0 +1,96 @@
+package hashicorplru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLRU(t *testing.T) {
+	if _, err := New[int, int](0); err == nil {
+		t.Fatal("should fail")
+	}
+
+	evictCounter := 0
+	l, err := NewWithEvict(128, func(k int, v int) {
+		if k != v {
+			t.Fatalf("Evict values not equal (%v!=%v)", k, v)
+		}
+		evictCounter++
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 0; i < 256; i++ {
+		l.Add(i, i)
+	}
+	if l.Len() != 128 {
+		t.Fatalf("bad len: %v", l.Len())
+	}
+	if evictCounter != 128 {
+		t.Fatalf("bad evict count: %v", evictCounter)
+	}
+
+	for i, k := range l.Keys() {
+		if v, ok := l.Get(k); !ok || v != k || v != i+128 {
+			t.Fatalf("bad key: %v", k)
+		}
+	}
+	for i, v := range l.Values() {
+		if v != i+128 {
+			t.Fatalf("bad value: %v", v)
+		}
+	}
+
+	if ok, evicted := l.ContainsOrAdd(200, 0); !ok || evicted {
+		t.Fatal("200 should be contained")
+	}
+	if prev, ok, _ := l.PeekOrAdd(1, 1); ok || prev != 0 {
+		t.Fatal("1 should be added")
+	}
+	if k, _, ok := l.GetOldest(); !ok || k != 129 {
+		t.Fatalf("bad oldest: %v", k)
+	}
+	if k, _, ok := l.RemoveOldest(); !ok || k != 129 {
+		t.Fatalf("bad oldest: %v", k)
+	}
+	if !l.Remove(200) || l.Contains(200) {
+		t.Fatal("200 should be removed")
+	}
+	if evicted := l.Resize(10); evicted != 116 {
+		t.Fatalf("bad evicted: %v", evicted)
+	}
+
+	l.Purge()
+	if l.Len() != 0 {
+		t.Fatalf("bad len: %v", l.Len())
+	}
+}
+
+func Test2Q(t *testing.T) {
+	if _, err := New2Q[int, int](0); err == nil {
+		t.Fatal("should fail")
+	}
+	if _, err := New2QParams[int, int](128, 2, 0.5); err == nil {
+		t.Fatal("should fail")
+	}
+
+	evicted := 0
+	l, err := New2QWithEvict(2, func(k, v int) { evicted++ })
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Add(1, 1)
+	l.Add(2, 2)
+	l.Get(1)
+	l.Add(3, 3)
+	if evicted == 0 || l.Contains(2) {
+		t.Fatalf("bad keys: %v", l.Keys())
+	}
+	if !reflect.DeepEqual(l.Values(), []int{1, 3}) {
+		t.Fatalf("bad values: %v", l.Values())
+	}
+	l.Purge()
+	if l.Len() != 0 {
+		t.Fatalf("bad len: %v", l.Len())
+	}
+}
@@ -0,0 +1,69 @@
+package lru
+
+import "time"
+
+// Eviction records an entry that left the cache.
+type Eviction[K comparable, V any] struct {
+	Key K
+	// Value is only recorded WithEvictionHistory keeping values.
+	Value V
+	// Kind is EventEvict, EventExpire or EventRemove.
+	Kind EventKind
+	At   time.Time
+	// Source is the writer of the entry, set by AddWithSource.
+	Source string
+}
+
+// WithEvictionHistory keeps the last size entries evicted, expired or
+// removed from the cache, queryable with RecentEvictions. Values are only
+// kept if keepValues is set, so that the history does not retain them.
+func WithEvictionHistory[K comparable, V any](size int, keepValues bool) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		if size <= 0 {
+			c.history = nil
+			return
+		}
+		c.history = &evictionRing[K, V]{
+			records:    make([]Eviction[K, V], size),
+			keepValues: keepValues,
+		}
+	}
+}
+
+// evictionRing is a fixed size ring buffer of evictions.
+type evictionRing[K comparable, V any] struct {
+	records    []Eviction[K, V]
+	next       int
+	n          int
+	keepValues bool
+}
+
+func (r *evictionRing[K, V]) record(ent *entry[K, V], kind EventKind, at time.Time) {
+	rec := Eviction[K, V]{Key: ent.key, Kind: kind, At: at, Source: ent.source}
+	if r.keepValues {
+		rec.Value = ent.value
+	}
+	r.records[r.next] = rec
+	r.next = (r.next + 1) % len(r.records)
+	if r.n < len(r.records) {
+		r.n++
+	}
+}
+
+func (c *unsafeCache[K, V]) RecentEvictions(n int) []Eviction[K, V] {
+	r := c.history
+	if r == nil || n <= 0 {
+		return nil
+	}
+	if n > r.n {
+		n = r.n
+	}
+	evictions := make([]Eviction[K, V], n)
+	for i := 0; i < n; i++ {
+		evictions[i] = r.records[(r.next-1-i+len(r.records))%len(r.records)]
+		if r.keepValues {
+			evictions[i].Value = c.loadValue(evictions[i].Value)
+		}
+	}
+	return evictions
+}
@@ -0,0 +1,48 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_unsafeCache_RecentEvictions(t *testing.T) {
+	c := NewUnsafeLru[int, int](2, WithEvictionHistory[int, int](3, false))
+	for i := 0; i < 5; i++ {
+		c.Add(i, i)
+	}
+	c.Remove(4)
+
+	var (
+		keys  []int
+		kinds []EventKind
+	)
+	for _, ev := range c.RecentEvictions(10) {
+		keys = append(keys, ev.Key)
+		kinds = append(kinds, ev.Kind)
+		if ev.Value != 0 || ev.At.IsZero() {
+			t.Fatalf("bad eviction: %+v", ev)
+		}
+	}
+	if expected := []int{4, 2, 1}; !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("Expected %v, got %v", expected, keys)
+	}
+	if expected := []EventKind{EventRemove, EventEvict, EventEvict}; !reflect.DeepEqual(kinds, expected) {
+		t.Fatalf("Expected %v, got %v", expected, kinds)
+	}
+
+	if evictions := c.RecentEvictions(1); len(evictions) != 1 || evictions[0].Key != 4 {
+		t.Fatalf("bad evictions: %v", evictions)
+	}
+	if evictions := NewUnsafeLru[int, int](2).RecentEvictions(1); evictions != nil {
+		t.Fatalf("Expected nil, got %v", evictions)
+	}
+}
+
+func Test_unsafeCache_RecentEvictionsValues(t *testing.T) {
+	c := NewUnsafeLru[int, int](1, WithEvictionHistory[int, int](3, true))
+	c.Add(1, 10)
+	c.Add(2, 20)
+	if evictions := c.RecentEvictions(1); len(evictions) != 1 || evictions[0].Value != 10 {
+		t.Fatalf("bad evictions: %v", evictions)
+	}
+}
@@ -0,0 +1,266 @@
+package lru
+
+import (
+	"math/bits"
+	"sync"
+)
+
+// Integer is the set of key types supported by NewIntKeyed.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// NewIntKeyed creates an LRU cache specialized for integer keys.
+func NewIntKeyed[K Integer, V any](maxEntries int) *IntKeyedCache[K, V] {
+	if maxEntries <= 0 {
+		maxEntries = defaultSize
+	}
+	// Keep the load factor of the table at most 1/2
+	bitLen := bits.Len(uint(maxEntries*2 - 1))
+	c := &IntKeyedCache[K, V]{
+		maxEntries: maxEntries,
+		nodes:      make([]intNode[K, V], 0, maxEntries),
+		table:      make([]int32, 1<<bitLen),
+		shift:      uint(64 - bitLen),
+	}
+	c.head, c.tail = -1, -1
+	return c
+}
+
+var _ Cacher[int, int] = (*IntKeyedCache[int, int])(nil)
+
+// IntKeyedCache is a thread-safe fixed size LRU cache for integer keys.
+// It replaces the map and the list elements of the standard LRU cache with
+// an open addressing table and a preallocated array of entries linked by
+// index, which saves allocations and hashing work on Add and Get.
+type IntKeyedCache[K Integer, V any] struct {
+	maxEntries int
+
+	// nodes holds the entries, linked from the most (head)
+	// to the least (tail) recently used
+	nodes      []intNode[K, V]
+	head, tail int32
+
+	// table maps the hash of a key to its node index plus one,
+	// zero is an empty slot. It uses linear probing.
+	table []int32
+	shift uint
+
+	sync.Mutex
+}
+
+// intNode is an entry of the IntKeyedCache
+type intNode[K Integer, V any] struct {
+	key        K
+	value      V
+	prev, next int32
+}
+
+// Add a value to the cache. Returns true if an eviction occurred.
+func (c *IntKeyedCache[K, V]) Add(key K, value V) (evicted bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, n := c.find(key); n >= 0 {
+		c.nodes[n].value = value
+		c.moveToFront(n)
+		return false
+	}
+
+	var n int32
+	if len(c.nodes) < c.maxEntries {
+		n = int32(len(c.nodes))
+		c.nodes = append(c.nodes, intNode[K, V]{})
+	} else {
+		// Reuse the node of the oldest entry
+		n = c.tail
+		c.unlink(n)
+		c.deleteSlot(c.nodes[n].key)
+		evicted = true
+	}
+	c.nodes[n].key, c.nodes[n].value = key, value
+	c.pushFront(n)
+	slot, _ := c.find(key)
+	c.table[slot] = n + 1
+	return evicted
+}
+
+// Get looks up a key's value from the cache
+func (c *IntKeyedCache[K, V]) Get(key K) (value V, ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	_, n := c.find(key)
+	if n < 0 {
+		return value, false
+	}
+	c.moveToFront(n)
+	return c.nodes[n].value, true
+}
+
+// Contains checks if a key is in the cache, without updating the recent-ness.
+func (c *IntKeyedCache[K, V]) Contains(key K) (ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	_, n := c.find(key)
+	return n >= 0
+}
+
+// Peek returns the key value without updating the "recently used"-ness of the key.
+func (c *IntKeyedCache[K, V]) Peek(key K) (value V, ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	_, n := c.find(key)
+	if n < 0 {
+		return value, false
+	}
+	return c.nodes[n].value, true
+}
+
+// Remove removes the provided key from the cache, returning if the
+// key was contained.
+func (c *IntKeyedCache[K, V]) Remove(key K) (ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	_, n := c.find(key)
+	if n < 0 {
+		return false
+	}
+	c.unlink(n)
+	c.deleteSlot(key)
+
+	// Keep the nodes dense by moving the last one into the hole
+	last := int32(len(c.nodes) - 1)
+	if n != last {
+		c.nodes[n] = c.nodes[last]
+		slot, _ := c.find(c.nodes[n].key)
+		c.table[slot] = n + 1
+		c.relink(n)
+	}
+	c.nodes[last] = intNode[K, V]{}
+	c.nodes = c.nodes[:last]
+	return true
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest.
+func (c *IntKeyedCache[K, V]) Keys() []K {
+	c.Lock()
+	defer c.Unlock()
+
+	keys := make([]K, 0, len(c.nodes))
+	for n := c.tail; n >= 0; n = c.nodes[n].prev {
+		keys = append(keys, c.nodes[n].key)
+	}
+	return keys
+}
+
+// Len returns the number of items in the cache.
+func (c *IntKeyedCache[K, V]) Len() int {
+	c.Lock()
+	defer c.Unlock()
+
+	return len(c.nodes)
+}
+
+// Clear is used to completely clear the cache
+func (c *IntKeyedCache[K, V]) Clear() {
+	c.Lock()
+	defer c.Unlock()
+
+	for i := range c.nodes {
+		c.nodes[i] = intNode[K, V]{}
+	}
+	c.nodes = c.nodes[:0]
+	for i := range c.table {
+		c.table[i] = 0
+	}
+	c.head, c.tail = -1, -1
+}
+
+// hash returns the home slot of a key using Fibonacci hashing.
+func (c *IntKeyedCache[K, V]) hash(key K) int {
+	return int((uint64(key) * 0x9E3779B97F4A7C15) >> c.shift)
+}
+
+// find returns the slot of the key and its node, or the empty slot
+// where it belongs and -1.
+func (c *IntKeyedCache[K, V]) find(key K) (slot int, n int32) {
+	mask := len(c.table) - 1
+	for slot = c.hash(key); ; slot = (slot + 1) & mask {
+		idx := c.table[slot]
+		if idx == 0 {
+			return slot, -1
+		}
+		if c.nodes[idx-1].key == key {
+			return slot, idx - 1
+		}
+	}
+}
+
+// deleteSlot removes the key from the table, shifting back the
+// following entries of its probe sequence to keep them reachable.
+func (c *IntKeyedCache[K, V]) deleteSlot(key K) {
+	mask := len(c.table) - 1
+	hole, _ := c.find(key)
+	for slot := (hole + 1) & mask; c.table[slot] != 0; slot = (slot + 1) & mask {
+		home := c.hash(c.nodes[c.table[slot]-1].key)
+		// Move the entry if its home is not between the hole and its slot
+		if (slot-home)&mask >= (slot-hole)&mask {
+			c.table[hole] = c.table[slot]
+			hole = slot
+		}
+	}
+	c.table[hole] = 0
+}
+
+func (c *IntKeyedCache[K, V]) pushFront(n int32) {
+	c.nodes[n].prev, c.nodes[n].next = -1, c.head
+	if c.head >= 0 {
+		c.nodes[c.head].prev = n
+	}
+	c.head = n
+	if c.tail < 0 {
+		c.tail = n
+	}
+}
+
+func (c *IntKeyedCache[K, V]) unlink(n int32) {
+	prev, next := c.nodes[n].prev, c.nodes[n].next
+	if prev >= 0 {
+		c.nodes[prev].next = next
+	} else {
+		c.head = next
+	}
+	if next >= 0 {
+		c.nodes[next].prev = prev
+	} else {
+		c.tail = prev
+	}
+}
+
+func (c *IntKeyedCache[K, V]) moveToFront(n int32) {
+	if c.head == n {
+		return
+	}
+	c.unlink(n)
+	c.pushFront(n)
+}
+
+// relink points the neighbours of a node moved to index to at it.
+func (c *IntKeyedCache[K, V]) relink(to int32) {
+	prev, next := c.nodes[to].prev, c.nodes[to].next
+	if prev >= 0 {
+		c.nodes[prev].next = to
+	} else {
+		c.head = to
+	}
+	if next >= 0 {
+		c.nodes[next].prev = to
+	} else {
+		c.tail = to
+	}
+}
@@ -0,0 +1,107 @@
+package lru
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func BenchmarkIntKeyed_Add(b *testing.B) {
+	c := NewIntKeyed[int, int](defaultSize)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c.Add(i, i)
+	}
+}
+
+func BenchmarkIntKeyed_Get(b *testing.B) {
+	c := NewIntKeyed[int, int](defaultSize)
+	for i := 0; i < defaultSize; i++ {
+		c.Add(i, i)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c.Get(i % (defaultSize * 2))
+	}
+}
+
+func BenchmarkSafeLru_AddSerial(b *testing.B) {
+	c := New[int, int](defaultSize)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c.Add(i, i)
+	}
+}
+
+func BenchmarkSafeLru_Get(b *testing.B) {
+	c := New[int, int](defaultSize)
+	for i := 0; i < defaultSize; i++ {
+		c.Add(i, i)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c.Get(i % (defaultSize * 2))
+	}
+}
+
+func TestIntKeyed_RandomOps(t *testing.T) {
+	var (
+		size = 64
+		l    = NewIntKeyed[int64, int64](size)
+		ref  = NewUnsafeLru[int64, int64](size)
+	)
+
+	for i := 0; i < 200000; i++ {
+		key := rand.Int63()%256 - 128
+		switch rand.Int63() % 4 {
+		case 0, 1:
+			if l.Add(key, key*2) != ref.Add(key, key*2) {
+				t.Fatalf("evicted mismatch on Add(%d)", key)
+			}
+		case 2:
+			v1, ok1 := l.Get(key)
+			v2, ok2 := ref.Get(key)
+			if v1 != v2 || ok1 != ok2 {
+				t.Fatalf("Get(%d): Expected %v, %v, got %v, %v", key, v2, ok2, v1, ok1)
+			}
+		case 3:
+			if l.Remove(key) != ref.Remove(key) {
+				t.Fatalf("Remove(%d) mismatch", key)
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(l.Keys(), ref.Keys()) {
+		t.Fatalf("keys not equal: (%v != %v)", l.Keys(), ref.Keys())
+	}
+	for _, k := range ref.Keys() {
+		if !l.Contains(k) {
+			t.Fatalf("missing: %d", k)
+		}
+		if v, _ := l.Peek(k); v != k*2 {
+			t.Fatalf("Expected %v, got %v", k*2, v)
+		}
+	}
+
+	l.Clear()
+	if l.Len() != 0 || l.Contains(0) {
+		t.Fatalf("Expected %v, got %v", 0, l.Len())
+	}
+}
+
+// go test -bench='Benchmark(IntKeyed|SafeLru_AddSerial|SafeLru_Get)' . -benchmem
+// goos: linux
+// goarch: amd64
+// pkg: github.com/electricbubble/lru
+// BenchmarkIntKeyed_Add            50481880                25.07 ns/op            0 B/op          0 allocs/op
+// BenchmarkIntKeyed_Get            61936851                19.88 ns/op            0 B/op          0 allocs/op
+// BenchmarkSafeLru_AddSerial        6885872               180.4 ns/op           112 B/op          2 allocs/op
+// BenchmarkSafeLru_Get             28164841                43.63 ns/op            0 B/op          0 allocs/op
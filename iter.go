@@ -0,0 +1,37 @@
+package lru
+
+// All returns an iterator over a snapshot of the entries taken when it is
+// called, from oldest to newest. Entries added, updated or removed later do
+// not affect it. It has the shape of an iter.Seq2. The lock is not held
+// while yielding, so the loop body can use the cache.
+func (c *Cache[K, V]) All() func(yield func(key K, value V) bool) {
+	items := c.Items()
+	return func(yield func(key K, value V) bool) {
+		for _, e := range items {
+			if !yield(e.Key, e.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Range calls fn for the entries in the cache when it is called, from
+// oldest to newest, until fn returns false. It skips the entries removed,
+// evicted or expired before being visited, and passes the value current
+// when visited. Entries added meanwhile are not visited. The lock is only
+// held to look each entry up, not while calling fn, so fn can use the
+// cache and the other callers are not blocked by the scan. Range does not
+// update the recency of the entries.
+func (c *Cache[K, V]) Range(fn func(key K, value V) bool) {
+	for _, key := range c.Keys() {
+		c.rlock()
+		e, ok := c.lru.PeekEntry(key)
+		c.RUnlock()
+		if !ok {
+			continue
+		}
+		if !fn(e.Key, e.Value) {
+			return
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package lru
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestCache_All(t *testing.T) {
+	c := New[int, int](10)
+	for i := 0; i < 3; i++ {
+		c.Add(i, i)
+	}
+
+	var keys []int
+	c.All()(func(k, v int) bool {
+		keys = append(keys, k)
+		// The snapshot is not affected by the changes
+		c.Remove(2)
+		c.Add(10+k, k)
+		return true
+	})
+	if !reflect.DeepEqual(keys, []int{0, 1, 2}) {
+		t.Fatalf("Expected %v, got %v", []int{0, 1, 2}, keys)
+	}
+
+	keys = nil
+	c.All()(func(k, v int) bool {
+		keys = append(keys, k)
+		return len(keys) < 2
+	})
+	if len(keys) != 2 {
+		t.Fatalf("Expected %v, got %v", 2, len(keys))
+	}
+}
+
+func TestCache_Range(t *testing.T) {
+	c := New[int, int](10)
+	for i := 0; i < 4; i++ {
+		c.Add(i, i)
+	}
+
+	var keys, values []int
+	c.Range(func(k, v int) bool {
+		keys = append(keys, k)
+		values = append(values, v)
+		if k == 0 {
+			c.Remove(2)
+			c.Add(3, 30)
+			c.Add(4, 4)
+		}
+		return true
+	})
+	// 2 is skipped, 3 is visited with its current value, 4 is not visited
+	if !reflect.DeepEqual(keys, []int{0, 1, 3}) {
+		t.Fatalf("Expected %v, got %v", []int{0, 1, 3}, keys)
+	}
+	if !reflect.DeepEqual(values, []int{0, 1, 30}) {
+		t.Fatalf("Expected %v, got %v", []int{0, 1, 30}, values)
+	}
+}
+
+func TestCache_RangeConcurrent(t *testing.T) {
+	c := New[int, int](64)
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; ; i++ {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				k := (i*7 + g) % 128
+				if i%3 == 0 {
+					c.Remove(k)
+				} else {
+					c.Add(k, k)
+				}
+			}
+		}(g)
+	}
+
+	for n := 0; n < 100; n++ {
+		seen := make(map[int]bool)
+		c.Range(func(k, v int) bool {
+			if seen[k] || k != v {
+				t.Errorf("Unexpected %v, %v", k, v)
+			}
+			seen[k] = true
+			return true
+		})
+		c.All()(func(k, v int) bool {
+			if k != v {
+				t.Errorf("Unexpected %v, %v", k, v)
+			}
+			return true
+		})
+	}
+	close(done)
+	wg.Wait()
+}
@@ -0,0 +1,11 @@
+package lru
+
+import "strings"
+
+// HasPrefix returns a KeysWhere predicate matching the keys starting
+// with prefix.
+func HasPrefix(prefix string) func(key string) bool {
+	return func(key string) bool {
+		return strings.HasPrefix(key, prefix)
+	}
+}
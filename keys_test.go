@@ -0,0 +1,23 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLru_KeysWhere(t *testing.T) {
+	l := New[string, int](10)
+	for i, k := range []string{"user:1", "team:1", "user:2", "user", "team:2"} {
+		l.Add(k, i)
+	}
+
+	if keys := l.KeysWhere(HasPrefix("user:")); !reflect.DeepEqual(keys, []string{"user:1", "user:2"}) {
+		t.Fatalf("Expected %v, got %v", []string{"user:1", "user:2"}, keys)
+	}
+	if keys := l.KeysWhere(func(k string) bool { return len(k) == 4 }); !reflect.DeepEqual(keys, []string{"user"}) {
+		t.Fatalf("Expected %v, got %v", []string{"user"}, keys)
+	}
+	if keys := l.KeysWhere(HasPrefix("group:")); len(keys) != 0 {
+		t.Fatalf("Expected %v, got %v", 0, len(keys))
+	}
+}
@@ -0,0 +1,184 @@
+package lru
+
+import (
+	"sync"
+
+	"github.com/electricbubble/lru/list"
+)
+
+// NewKeySet creates a KeySet of the given size.
+func NewKeySet[K comparable](maxEntries int) *KeySet[K] {
+	return &KeySet[K]{set: newKeySet[K](maxEntries)}
+}
+
+// KeySet is a thread-safe fixed size LRU set of keys, e.g. for a window of
+// recently seen keys to deduplicate. It stores only the keys, without the
+// values, costs and expirations of cache entries.
+type KeySet[K comparable] struct {
+	set *keySet[K]
+
+	sync.Mutex
+}
+
+// Add a key to the set, or marks it as recently used if it is contained.
+// Returns true if the oldest key was evicted.
+func (s *KeySet[K]) Add(key K) (evicted bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.set.Add(key)
+}
+
+// Contains checks if a key is in the set, without updating its
+// "recently used"-ness.
+func (s *KeySet[K]) Contains(key K) (ok bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.set.Contains(key)
+}
+
+// Remove removes the key from the set, returning if it was contained.
+func (s *KeySet[K]) Remove(key K) (ok bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.set.Remove(key)
+}
+
+// RemoveOldest removes the oldest key from the set.
+func (s *KeySet[K]) RemoveOldest() (key K, ok bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.set.RemoveOldest()
+}
+
+// Keys returns a slice of the keys in the set, from oldest to newest.
+func (s *KeySet[K]) Keys() []K {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.set.Keys()
+}
+
+// Len returns the number of keys in the set.
+func (s *KeySet[K]) Len() int {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.set.Len()
+}
+
+// Clear removes all the keys.
+func (s *KeySet[K]) Clear() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.set.Clear()
+}
+
+// keySet is the not thread-safe KeySet. The ARC and 2Q caches use it as
+// ghost lists, to remember recently evicted entries without holding their
+// values, and without executing the callbacks of the cache when ghosts are
+// dropped.
+type keySet[K comparable] struct {
+	maxEntries int
+
+	keys   *list.List[K]
+	bucket map[K]*list.Element[K]
+}
+
+func newKeySet[K comparable](maxEntries int) *keySet[K] {
+	if maxEntries <= 0 {
+		maxEntries = defaultSize
+	}
+	return &keySet[K]{
+		maxEntries: maxEntries,
+		keys:       list.New[K](),
+		bucket:     make(map[K]*list.Element[K]),
+	}
+}
+
+// Add a key to the set, evicting the oldest key if it is full.
+func (s *keySet[K]) Add(key K) (evicted bool) {
+	if elem, ok := s.bucket[key]; ok {
+		s.keys.MoveToFront(elem)
+		return false
+	}
+
+	s.bucket[key] = s.keys.PushFront(key)
+	if s.keys.Len() > s.maxEntries {
+		s.RemoveOldest()
+		return true
+	}
+	return false
+}
+
+// Contains checks if a key is in the set.
+func (s *keySet[K]) Contains(key K) (ok bool) {
+	_, ok = s.bucket[key]
+	return ok
+}
+
+// Remove removes the key from the set, returning if it was contained.
+func (s *keySet[K]) Remove(key K) (ok bool) {
+	elem, ok := s.bucket[key]
+	if !ok {
+		return false
+	}
+	s.keys.Remove(elem)
+	delete(s.bucket, key)
+	return true
+}
+
+// RemoveOldest removes the oldest key from the set.
+func (s *keySet[K]) RemoveOldest() (key K, ok bool) {
+	elem := s.keys.Back()
+	if elem == nil {
+		return key, false
+	}
+	s.keys.Remove(elem)
+	delete(s.bucket, elem.Value)
+	return elem.Value, true
+}
+
+// Oldest returns the oldest key of the set.
+func (s *keySet[K]) Oldest() (key K, ok bool) {
+	elem := s.keys.Back()
+	if elem == nil {
+		return key, false
+	}
+	return elem.Value, true
+}
+
+// oldestExcept returns the oldest key of the set for which skip, if not
+// nil, returns false.
+func (s *keySet[K]) oldestExcept(skip func(key K) bool) (key K, ok bool) {
+	for elem := s.keys.Back(); elem != nil; elem = elem.Prev() {
+		if skip == nil || !skip(elem.Value) {
+			return elem.Value, true
+		}
+	}
+	return key, false
+}
+
+// Keys returns a slice of the keys in the set, from oldest to newest.
+func (s *keySet[K]) Keys() []K {
+	keys := make([]K, 0, s.keys.Len())
+	for elem := s.keys.Back(); elem != nil; elem = elem.Prev() {
+		keys = append(keys, elem.Value)
+	}
+	return keys
+}
+
+// Len returns the number of keys in the set.
+func (s *keySet[K]) Len() int {
+	return s.keys.Len()
+}
+
+// Clear removes all the keys.
+func (s *keySet[K]) Clear() {
+	s.keys.Init()
+	s.bucket = make(map[K]*list.Element[K])
+}
@@ -0,0 +1,74 @@
+package lru
+
+import "testing"
+
+func Test_keySet(t *testing.T) {
+	g := newKeySet[int](2)
+	g.Add(1)
+	g.Add(2)
+	g.Add(1)
+	g.Add(3)
+
+	if g.Len() != 2 || g.Contains(2) || !g.Contains(1) || !g.Contains(3) {
+		t.Fatalf("bad ghosts: len %v", g.Len())
+	}
+
+	if k, ok := g.RemoveOldest(); !ok || k != 1 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, true, k, ok)
+	}
+	if !g.Remove(3) || g.Remove(3) {
+		t.Fatal("should be removed once")
+	}
+	if _, ok := g.RemoveOldest(); ok {
+		t.Fatal("should be empty")
+	}
+
+	g.Add(4)
+	g.Clear()
+	if g.Len() != 0 || g.Contains(4) {
+		t.Fatalf("Expected %v, got %v", 0, g.Len())
+	}
+}
+
+func Test2Q_GhostsSkipCallbacks(t *testing.T) {
+	evicted := make(map[int]int)
+	l := New2Q[int, int](4, WithOnEvicted(func(k, v int) {
+		evicted[k] = v
+	}))
+	for i := 1; i <= 20; i++ {
+		l.Add(i, i)
+	}
+	for k, v := range evicted {
+		if k != v {
+			t.Fatalf("callback executed for ghost %v with %v", k, v)
+		}
+	}
+}
+
+func TestKeySet(t *testing.T) {
+	s := NewKeySet[string](2)
+	if s.Add("a") || s.Add("b") {
+		t.Fatal("should not evict")
+	}
+	s.Add("a")
+	if !s.Add("c") {
+		t.Fatal("should evict")
+	}
+	if s.Contains("b") || !s.Contains("a") {
+		t.Fatal("b should be evicted")
+	}
+	if keys := s.Keys(); len(keys) != 2 || keys[0] != "a" || keys[1] != "c" {
+		t.Fatalf("Expected %v, got %v", []string{"a", "c"}, keys)
+	}
+	if !s.Remove("a") || s.Len() != 1 {
+		t.Fatal("a should be removed")
+	}
+	if k, ok := s.RemoveOldest(); !ok || k != "c" {
+		t.Fatalf("Expected %v, %v, got %v, %v", "c", true, k, ok)
+	}
+	s.Add("d")
+	s.Clear()
+	if s.Len() != 0 {
+		t.Fatalf("Expected %v, got %v", 0, s.Len())
+	}
+}
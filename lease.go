@@ -0,0 +1,51 @@
+package lru
+
+import "time"
+
+// keyLease is a lease of TryLockKey, which expires at expiresAt unless
+// it is zero.
+type keyLease struct {
+	id        uint64
+	expiresAt time.Time
+}
+
+// TryLockKey grants an exclusive lease on the key until unlock is called,
+// or until lease elapsed if positive, so that writers coordinating the
+// read-modify-write of a cached value do not clobber each other, and a
+// writer which never unlocks does not hold the key forever. It returns
+// false if the key is already leased. Leases are advisory: they do not
+// block the methods of the cache, only other TryLockKey calls, and they do
+// not require the key to be cached. The key is normalized WithKeyNormalizer
+// and the lease measured with the clock of the cache. Calling unlock more
+// than once, or after the lease expired, is a no-op.
+func (c *Cache[K, V]) TryLockKey(key K, lease time.Duration) (unlock func(), ok bool) {
+	now := time.Now
+	if u, isUnsafe := c.lru.(*unsafeCache[K, V]); isUnsafe {
+		key, now = u.normalize(key), u.now
+	}
+
+	c.leaseMu.Lock()
+	defer c.leaseMu.Unlock()
+
+	if l, leased := c.leases[key]; leased && (l.expiresAt.IsZero() || now().Before(l.expiresAt)) {
+		return nil, false
+	}
+	if c.leases == nil {
+		c.leases = make(map[K]keyLease)
+	}
+	c.leaseID++
+	l := keyLease{id: c.leaseID}
+	if lease > 0 {
+		l.expiresAt = now().Add(lease)
+	}
+	c.leases[key] = l
+
+	return func() {
+		c.leaseMu.Lock()
+		defer c.leaseMu.Unlock()
+
+		if c.leases[key].id == l.id {
+			delete(c.leases, key)
+		}
+	}, true
+}
@@ -0,0 +1,88 @@
+package lru
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCache_TryLockKey(t *testing.T) {
+	l := New[string, int](128)
+
+	unlock, ok := l.TryLockKey("a", 0)
+	if !ok {
+		t.Fatal("should lock")
+	}
+	if _, ok = l.TryLockKey("a", 0); ok {
+		t.Fatal("should be leased")
+	}
+	unlockB, ok := l.TryLockKey("b", 0)
+	if !ok {
+		t.Fatal("other keys should lock")
+	}
+
+	unlock()
+	unlock2, ok := l.TryLockKey("a", 0)
+	if !ok {
+		t.Fatal("should lock after unlock")
+	}
+	// A stale unlock does not release the new lease
+	unlock()
+	if _, ok = l.TryLockKey("a", 0); ok {
+		t.Fatal("should still be leased")
+	}
+	unlock2()
+	unlockB()
+}
+
+func TestCache_TryLockKey_Lease(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	l := New[string, int](128, WithClock[string, int](clock.now), WithKeyNormalizer[string, int](strings.ToLower))
+
+	unlock, ok := l.TryLockKey("a", time.Minute)
+	if !ok {
+		t.Fatal("should lock")
+	}
+	if _, ok = l.TryLockKey("A", time.Minute); ok {
+		t.Fatal("normalized key should be leased")
+	}
+
+	// An expired lease is granted again, and its unlock is a no-op
+	clock.advance(time.Minute)
+	if _, ok = l.TryLockKey("A", time.Minute); !ok {
+		t.Fatal("should lock after the lease expired")
+	}
+	unlock()
+	if _, ok = l.TryLockKey("a", time.Minute); ok {
+		t.Fatal("should still be leased")
+	}
+}
+
+func TestCache_TryLockKey_ReadModifyWrite(t *testing.T) {
+	l := New[string, int](128)
+	l.Add("counter", 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 100; {
+				unlock, ok := l.TryLockKey("counter", 0)
+				if !ok {
+					continue
+				}
+				v, _ := l.Get("counter")
+				l.Add("counter", v+1)
+				unlock()
+				n++
+			}
+		}()
+	}
+	wg.Wait()
+
+	if v, _ := l.Get("counter"); v != 800 {
+		t.Fatalf("Expected %v, got %v", 800, v)
+	}
+}
@@ -26,6 +26,11 @@ func (e *Element[V]) Next() *Element[V] {
 	return nil
 }
 
+// List returns the list to which e belongs, or nil if e was removed.
+func (e *Element[V]) List() *List[V] {
+	return e.list
+}
+
 // Prev returns the previous list element or nil.
 func (e *Element[V]) Prev() *Element[V] {
 	if p := e.prev; e.list != nil && p != &e.list.root {
@@ -0,0 +1,128 @@
+package lru
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// LockWaitBounds are the upper bounds of the buckets of a
+// LockWaitHistogram, the last bucket counts the longer waits.
+var LockWaitBounds = [...]time.Duration{
+	time.Microsecond,
+	10 * time.Microsecond,
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+}
+
+// LockWaitHistogram counts the sampled waits for the lock of a Cache by
+// duration: bucket i counts the waits up to LockWaitBounds[i], and the
+// last bucket the waits beyond all of them.
+type LockWaitHistogram [len(LockWaitBounds) + 1]uint64
+
+// Samples returns the number of waits measured.
+func (h LockWaitHistogram) Samples() (n uint64) {
+	for _, count := range h {
+		n += count
+	}
+	return n
+}
+
+// Quantile returns the upper bound of the bucket holding the q quantile
+// of the waits, for q in [0, 1]. Waits beyond the last bound are reported
+// as -1, and zero is returned when there are no samples.
+func (h LockWaitHistogram) Quantile(q float64) time.Duration {
+	n := h.Samples()
+	if n == 0 {
+		return 0
+	}
+	rank := uint64(q * float64(n))
+	if rank >= n {
+		rank = n - 1
+	}
+	var seen uint64
+	for i, count := range h {
+		if seen += count; seen > rank {
+			if i == len(LockWaitBounds) {
+				return -1
+			}
+			return LockWaitBounds[i]
+		}
+	}
+	return -1
+}
+
+// Sub returns the waits counted since the older histogram h0.
+func (h LockWaitHistogram) Sub(h0 LockWaitHistogram) LockWaitHistogram {
+	for i := range h {
+		h[i] -= h0[i]
+	}
+	return h
+}
+
+// WithLockWaitSampling measures, every sampleEvery acquisitions, how long
+// the callers of the Cache returned by New wait for its lock, reported in
+// Stats.LockWait, so that contention can be diagnosed. It has no effect on
+// the caches without a lock.
+func WithLockWaitSampling[K comparable, V any](sampleEvery int) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		if sampleEvery < 1 {
+			sampleEvery = 1
+		}
+		c.lockSampleEvery = uint64(sampleEvery)
+	}
+}
+
+// lockWait samples the waits for the lock of a Cache.
+type lockWait struct {
+	every     uint64
+	calls     atomic.Uint64
+	histogram [len(LockWaitBounds) + 1]atomic.Uint64
+	// base is the histogram at the previous StatsDelta,
+	// guarded by the write lock.
+	base LockWaitHistogram
+}
+
+// sample tells whether the next acquisition is to be measured.
+func (w *lockWait) sample() bool {
+	return w.every > 0 && w.calls.Add(1)%w.every == 0
+}
+
+func (w *lockWait) record(wait time.Duration) {
+	i := 0
+	for i < len(LockWaitBounds) && wait > LockWaitBounds[i] {
+		i++
+	}
+	w.histogram[i].Add(1)
+}
+
+// load returns the histogram since the creation of the cache.
+func (w *lockWait) load() (h LockWaitHistogram) {
+	for i := range h {
+		h[i] = w.histogram[i].Load()
+	}
+	return h
+}
+
+// lock takes the write lock, measuring the wait if sampled.
+func (c *Cache[K, V]) lock() {
+	if !c.lockWait.sample() {
+		c.Lock()
+		return
+	}
+	start := time.Now()
+	c.Lock()
+	c.lockWait.record(time.Since(start))
+}
+
+// rlock takes the read lock, measuring the wait if sampled.
+func (c *Cache[K, V]) rlock() {
+	if !c.lockWait.sample() {
+		c.RLock()
+		return
+	}
+	start := time.Now()
+	c.RLock()
+	c.lockWait.record(time.Since(start))
+}
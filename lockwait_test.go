@@ -0,0 +1,75 @@
+package lru
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLockWaitHistogram(t *testing.T) {
+	var w lockWait
+	for _, d := range []time.Duration{0, time.Microsecond, 50 * time.Microsecond, 5 * time.Millisecond, time.Second} {
+		w.record(d)
+	}
+	h := w.load()
+	if want := (LockWaitHistogram{2, 0, 1, 0, 1, 0, 1}); h != want {
+		t.Fatalf("Expected %v, got %v", want, h)
+	}
+	if h.Samples() != 5 {
+		t.Fatalf("Expected %v, got %v", 5, h.Samples())
+	}
+	if q := h.Quantile(0.5); q != 100*time.Microsecond {
+		t.Fatalf("Expected %v, got %v", 100*time.Microsecond, q)
+	}
+	if q := h.Quantile(1); q != -1 {
+		t.Fatalf("Expected %v, got %v", -1, q)
+	}
+	if q := (LockWaitHistogram{}).Quantile(0.5); q != 0 {
+		t.Fatalf("Expected %v, got %v", 0, q)
+	}
+
+	w.record(time.Second)
+	if h = w.load().Sub(h); h != (LockWaitHistogram{0, 0, 0, 0, 0, 0, 1}) {
+		t.Fatalf("Expected %v, got %v", 1, h)
+	}
+}
+
+func TestCache_WithLockWaitSampling(t *testing.T) {
+	c := New[int, int](10, WithLockWaitSampling[int, int](2))
+	for i := 0; i < 10; i++ {
+		c.Add(i, i)
+	}
+	// The Stats call is the 11th acquisition, the 10 Adds were sampled 5 times
+	if n := c.Stats().LockWait.Samples(); n != 5 {
+		t.Fatalf("Expected %v, got %v", 5, n)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				c.Get(i % 10)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := c.StatsDelta().LockWait.Samples(); n != 206 {
+		t.Fatalf("Expected %v, got %v", 206, n)
+	}
+	// Stats keeps accumulating, the next delta has the one of StatsDelta
+	if n := c.Stats().LockWait.Samples(); n != 206 {
+		t.Fatalf("Expected %v, got %v", 206, n)
+	}
+	if n := c.StatsDelta().LockWait.Samples(); n != 1 {
+		t.Fatalf("Expected %v, got %v", 1, n)
+	}
+
+	c = New[int, int](10)
+	c.Add(1, 1)
+	if n := c.Stats().LockWait.Samples(); n != 0 {
+		t.Fatalf("Expected %v, got %v", 0, n)
+	}
+}
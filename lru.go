@@ -1,6 +1,10 @@
 package lru
 
-import "sync"
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
 const defaultSize = 128
 
@@ -8,9 +12,56 @@ type Lru[K comparable, V any] interface {
 	// Add a value to the cache. Returns true if an eviction occurred.
 	Add(key K, value V) (evicted bool)
 
+	// AddWithTTL is like Add, with a time to live overriding the default
+	// of the cache for this entry. Zero means the default of the cache,
+	// and NoExpiration that the entry does not expire after being written.
+	// Either way, the entry still expires after being idle WithTTI.
+	AddWithTTL(key K, value V, ttl time.Duration) (evicted bool)
+
+	// AddReturningEvicted is like Add, returning the entries it evicted
+	// instead of executing the eviction callbacks for them.
+	AddReturningEvicted(key K, value V) (evicted []Entry[K, V])
+
+	// AddWithSource is like Add, recording the writer of the entry, which
+	// is reported by the entries, events and evictions, and by Sources.
+	// Updates without a source clear it.
+	AddWithSource(key K, value V, source string) (evicted bool)
+
+	// AddNew adds a value to the cache only if the key is missing,
+	// returning whether it was added.
+	AddNew(key K, value V) (added bool)
+
+	// Put is like Add, and tells whether the entry was added, updated,
+	// added by evicting others, or rejected by the overflow policy.
+	Put(key K, value V) (result AddResult)
+
 	// Get looks up a key's value from the cache
 	Get(key K) (value V, ok bool)
 
+	// GetOrAdd returns the value of the key if it is in the cache, like
+	// Get, and otherwise adds value. loaded tells whether actual was found
+	// in the cache.
+	GetOrAdd(key K, value V) (actual V, loaded bool)
+
+	// ContainsOrAdd checks if a key is in the cache without updating the
+	// recent-ness, and otherwise adds the value. Returns whether found
+	// and whether an eviction occurred.
+	ContainsOrAdd(key K, value V) (ok, evicted bool)
+
+	// PeekOrAdd returns the value of a key in the cache without updating
+	// the recent-ness, and otherwise adds the value. Returns whether found
+	// and whether an eviction occurred.
+	PeekOrAdd(key K, value V) (previous V, ok, evicted bool)
+
+	// GetOk3 is like Get, but tells a key that was never cached apart from
+	// a stale one: for an expired entry it returns the stale value with
+	// present false and expired true, and removes the entry.
+	GetOk3(key K) (value V, present bool, expired bool)
+
+	// GetWithFreshness is like Get, also telling whether the value is
+	// past the soft TTL of WithSoftTTL.
+	GetWithFreshness(key K) (value V, freshness Freshness, ok bool)
+
 	// Contains checks if a key is in the cache, without updating the recent-ness
 	// or deleting it for being stale.
 	Contains(key K) (ok bool)
@@ -23,61 +74,390 @@ type Lru[K comparable, V any] interface {
 	// key was contained.
 	Remove(key K) (ok bool)
 
+	// RemoveGet removes the provided key from the cache, returning its
+	// value if it was contained and not expired.
+	RemoveGet(key K) (value V, ok bool)
+
+	// Pop is RemoveGet, for the work-queue usage where each entry must
+	// be consumed exactly once.
+	Pop(key K) (value V, ok bool)
+
+	// Tombstones returns the removals of the last ttl of WithTombstones,
+	// oldest first.
+	Tombstones() []Tombstone[K]
+
+	// Compute replaces the value of a key with the one returned by fn,
+	// called with the current value if exists, removing the key unless
+	// keep. Returns the new value and whether the key is in the cache.
+	Compute(key K, fn func(old V, exists bool) (value V, keep bool)) (value V, ok bool)
+
+	// RemoveAll removes the provided keys from the cache, returning the
+	// number of keys which were contained.
+	RemoveAll(keys []K) (removed int)
+
 	// RemoveOldest removes the oldest item from the cache.
 	RemoveOldest() (key K, value V, ok bool)
 
 	// GetOldest returns the oldest entry
 	GetOldest() (key K, value V, ok bool)
 
+	// PopOldestIf removes and returns the least recently used entry for
+	// which fn returns true, a nil fn matches every entry.
+	PopOldestIf(fn func(key K, value V) bool) (key K, value V, ok bool)
+
+	// PopNewestIf removes and returns the most recently used entry for
+	// which fn returns true, a nil fn matches every entry.
+	PopNewestIf(fn func(key K, value V) bool) (key K, value V, ok bool)
+
 	// Keys returns a slice of the keys in the cache, from oldest to newest.
 	Keys() []K
 
+	// Sources returns the number of entries, and their total cost, per
+	// source of AddWithSource.
+	Sources() map[string]SourceUsage
+
+	// KeysWhere returns the keys for which fn returns true, from oldest
+	// to newest, without copying the other keys.
+	KeysWhere(fn func(key K) bool) []K
+
 	// Len returns the number of items in the cache.
 	Len() int
 
+	// RemoveExpired removes all the expired entries from the cache.
+	RemoveExpired() (removed int)
+
+	// NextExpiry returns when the next entry expires, so that a scheduler
+	// can sleep until then instead of polling.
+	NextExpiry() (at time.Time, ok bool)
+
 	// Resize changes the cache size.
 	Resize(size int) (evicted int)
 
+	// Trim evicts the oldest entries until the cache is within its limits,
+	// which is only needed WithBackgroundTrim.
+	Trim() (evicted int)
+
+	// SetCapacityLazy changes the cache size like Resize, but does not
+	// evict anything. The excess entries are evicted by subsequent Adds.
+	SetCapacityLazy(size int) (excess int)
+
+	// Clear is used to completely clear the cache
+	Clear()
+
+	// PauseEvictions freezes the composition of the cache: until
+	// ResumeEvictions, the new entries which do not fit are rejected like
+	// with RejectNew instead of evicting others. Expirations, removals
+	// and updates still happen.
+	PauseEvictions()
+
+	// ResumeEvictions restores the evictions, evicting the entries over
+	// the limits, which updates can have grown the cost beyond.
+	ResumeEvictions() (evicted int)
+
+	// Rebuild replaces the eviction policy, migrating the entries to the
+	// new one in recency order, so that the cache stays warm. The policy
+	// set WithPolicy and the decayed eviction are dropped. It returns an
+	// error wrapping ErrUnknownPolicy for an unknown kind.
+	Rebuild(kind PolicyKind) error
+
+	// Close cancels the context of the asynchronous eviction callbacks,
+	// skips the pending ones and waits for the running ones to return.
+	Close() error
+
+	// Cost returns the total cost of the entries, as computed by the weigher.
+	Cost() int64
+
+	// SetMaxCost changes the maximum total cost of a cache WithWeigher,
+	// returning the number of entries evicted.
+	SetMaxCost(maxCost int64) (evicted int)
+
+	// UpdateCost changes the cost of an entry of a cache WithWeigher,
+	// returning if the key was contained.
+	UpdateCost(key K, cost int64) (ok bool)
+
+	// GetEntry is like Get, returning the entry with its metadata.
+	GetEntry(key K) (e Entry[K, V], ok bool)
+
+	// GetHandle is like Get, returning a handle to operate on the entry
+	// without looking its key up again.
+	GetHandle(key K) (h Handle[K, V], ok bool)
+
+	// PeekEntry is like Peek, returning the entry with its metadata.
+	PeekEntry(key K) (e Entry[K, V], ok bool)
+
+	// GetManyWithExpiry gets the values of the keys found in the cache,
+	// with their expiry, like Get.
+	GetManyWithExpiry(keys []K) map[K]ValueWithExpiry[V]
+
+	// Items returns a slice of the entries in the cache, from oldest to newest.
+	Items() []Entry[K, V]
+
+	// ByExpiry returns an iterator over the entries with a TTL or TTI,
+	// soonest expiring first. It has the shape of an iter.Seq2.
+	ByExpiry() func(yield func(key K, value V) bool)
+
+	// MostAccessed returns up to n entries with the highest hit counts,
+	// most accessed first. It requires WithHitCounting.
+	MostAccessed(n int) []Entry[K, V]
+
+	// RecentEvictions returns up to n of the entries which recently left
+	// the cache, most recent first. It requires WithEvictionHistory.
+	RecentEvictions(n int) []Eviction[K, V]
+
+	// Subscribe returns a channel of cache events and a function that
+	// cancels the subscription and closes the channel. Publishing never
+	// blocks, events that do not fit in the buffer are dropped.
+	Subscribe() (events <-chan Event[K, V], cancel func())
+
+	// DroppedEvents returns the number of events dropped because
+	// a subscriber was too slow.
+	DroppedEvents() uint64
+
+	// Stats returns the counters of the cache since its creation.
+	Stats() Stats
+
+	// StatsDelta returns the counters accumulated since the previous
+	// call, or since the creation of the cache. It does not reset the
+	// counters of Stats.
+	StatsDelta() Stats
+}
+
+// Cacher is the subset of Lru shared by the caches, views and compositions
+// of this package, so that they can be stacked on each other.
+type Cacher[K comparable, V any] interface {
+	// Add a value to the cache. Returns true if an eviction occurred.
+	Add(key K, value V) (evicted bool)
+
+	// Get looks up a key's value from the cache
+	Get(key K) (value V, ok bool)
+
+	// Contains checks if a key is in the cache, without updating the recent-ness
+	// or deleting it for being stale.
+	Contains(key K) (ok bool)
+
+	// Peek returns the key value (or undefined if not found) without updating
+	// the "recently used"-ness of the key.
+	Peek(key K) (value V, ok bool)
+
+	// Remove removes the provided key from the cache, returning if the
+	// key was contained.
+	Remove(key K) (ok bool)
+
 	// Clear is used to completely clear the cache
 	Clear()
 }
 
 func New[K comparable, V any](maxEntries int, opts ...Option[K, V]) *Cache[K, V] {
-	return &Cache[K, V]{
+	c := &Cache[K, V]{
 		lru: NewUnsafeLru[K, V](maxEntries, opts...),
 	}
+	u := c.lru.(*unsafeCache[K, V])
+	c.strict = u.strict
+	c.lockWait.every = u.lockSampleEvery
+	if u.trimSignal != nil && u.track() {
+		go c.backgroundTrim(u)
+	}
+	if u.janitorInterval > 0 && u.track() {
+		go c.janitor(u, u.janitorInterval)
+	}
+	return c
 }
 
-var _ Lru[int, int] = (*Cache[int, int])(nil)
+var (
+	_ Lru[int, int]    = (*Cache[int, int])(nil)
+	_ Cacher[int, int] = (Lru[int, int])(nil)
+)
 
 // Cache is an LRU cache. It is safe for concurrent access.
 type Cache[K comparable, V any] struct {
 	lru Lru[K, V]
 
+	disabled int32
+
+	// strict is set WithStrictLRU, Contains and Peek take the write lock.
+	strict bool
+
+	// lockWait samples the waits for the lock WithLockWaitSampling.
+	lockWait lockWait
+
+	// weak is the snapshot of KeysApprox and ItemsApprox.
+	weak atomic.Pointer[weakSnapshot[K, V]]
+
+	// leases are the keys locked by TryLockKey.
+	leases  map[K]keyLease
+	leaseID uint64
+	leaseMu sync.Mutex
+
 	sync.RWMutex
 }
 
 // Add a value to the cache. Returns true if an eviction occurred.
 func (c *Cache[K, V]) Add(key K, value V) (evicted bool) {
-	c.Lock()
+	if c.bypassed() {
+		return false
+	}
+	c.lock()
 	defer c.Unlock()
 
 	return c.lru.Add(key, value)
 }
 
+// AddReturningEvicted is like Add, returning the entries it evicted, which
+// can be several WithWeigher, instead of executing the eviction callbacks
+// for them, so that the displaced entries can be handled inline.
+func (c *Cache[K, V]) AddReturningEvicted(key K, value V) (evicted []Entry[K, V]) {
+	if c.bypassed() {
+		return nil
+	}
+	c.lock()
+	defer c.Unlock()
+
+	return c.lru.AddReturningEvicted(key, value)
+}
+
+// AddWithSource is like Add, recording the writer of the entry, which
+// is reported by the entries, events and evictions, and by Sources, to
+// attribute the churn and occupancy of a cache shared by several writers.
+// Updates without a source clear it.
+func (c *Cache[K, V]) AddWithSource(key K, value V, source string) (evicted bool) {
+	if c.bypassed() {
+		return false
+	}
+	c.lock()
+	defer c.Unlock()
+
+	return c.lru.AddWithSource(key, value, source)
+}
+
+// AddNew adds a value to the cache only if the key is missing, returning
+// whether it was added. It is a fast path for bulk loads of unique keys,
+// skipping the update of existing entries, which are left untouched. With
+// WithMapBackend(SwissMap), the key is also indexed in a single probe
+// instead of being looked up first.
+func (c *Cache[K, V]) AddNew(key K, value V) (added bool) {
+	if c.bypassed() {
+		return false
+	}
+	c.lock()
+	defer c.Unlock()
+
+	return c.lru.AddNew(key, value)
+}
+
+// AddWithTTL is like Add, with a time to live overriding the default
+// of the cache for this entry. Zero means the default of the cache,
+// and NoExpiration that the entry does not expire after being written.
+// Either way, the entry still expires after being idle WithTTI.
+func (c *Cache[K, V]) AddWithTTL(key K, value V, ttl time.Duration) (evicted bool) {
+	if c.bypassed() {
+		return false
+	}
+	c.lock()
+	defer c.Unlock()
+
+	return c.lru.AddWithTTL(key, value, ttl)
+}
+
+// Put is like Add, and tells whether the entry was added, updated,
+// added by evicting others, or rejected by the overflow policy.
+func (c *Cache[K, V]) Put(key K, value V) (result AddResult) {
+	if c.bypassed() {
+		return AddResultRejected
+	}
+	c.lock()
+	defer c.Unlock()
+
+	return c.lru.Put(key, value)
+}
+
 // Get looks up a key's value from the cache
 func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
-	c.Lock()
+	if c.bypassed() {
+		return value, false
+	}
+	c.lock()
 	defer c.Unlock()
 
 	return c.lru.Get(key)
 }
 
+// GetOrAdd returns the value of the key if it is in the cache, like Get,
+// and otherwise adds value, under a single lock acquisition. loaded tells
+// whether actual was found in the cache.
+func (c *Cache[K, V]) GetOrAdd(key K, value V) (actual V, loaded bool) {
+	if c.bypassed() {
+		return value, false
+	}
+	c.lock()
+	defer c.Unlock()
+
+	return c.lru.GetOrAdd(key, value)
+}
+
+// ContainsOrAdd checks if a key is in the cache without updating the
+// recent-ness, and otherwise adds the value, under a single lock
+// acquisition. Returns whether found and whether an eviction occurred.
+func (c *Cache[K, V]) ContainsOrAdd(key K, value V) (ok, evicted bool) {
+	if c.bypassed() {
+		return false, false
+	}
+	c.lock()
+	defer c.Unlock()
+
+	return c.lru.ContainsOrAdd(key, value)
+}
+
+// PeekOrAdd returns the value of a key in the cache without updating the
+// recent-ness, and otherwise adds the value, under a single lock
+// acquisition. Returns whether found and whether an eviction occurred.
+func (c *Cache[K, V]) PeekOrAdd(key K, value V) (previous V, ok, evicted bool) {
+	if c.bypassed() {
+		return previous, false, false
+	}
+	c.lock()
+	defer c.Unlock()
+
+	return c.lru.PeekOrAdd(key, value)
+}
+
+// GetOk3 is like Get, but tells a key that was never cached apart from
+// a stale one: for an expired entry it returns the stale value with
+// present false and expired true, and removes the entry.
+func (c *Cache[K, V]) GetOk3(key K) (value V, present bool, expired bool) {
+	if c.bypassed() {
+		return value, false, false
+	}
+	c.lock()
+	defer c.Unlock()
+
+	return c.lru.GetOk3(key)
+}
+
+// GetWithFreshness is like Get, also telling whether the value is
+// past the soft TTL of WithSoftTTL.
+func (c *Cache[K, V]) GetWithFreshness(key K) (value V, freshness Freshness, ok bool) {
+	if c.bypassed() {
+		return value, Fresh, false
+	}
+	c.lock()
+	defer c.Unlock()
+
+	return c.lru.GetWithFreshness(key)
+}
+
 // Contains checks if a key is in the cache, without updating the recent-ness
 // or deleting it for being stale.
 func (c *Cache[K, V]) Contains(key K) (ok bool) {
-	c.RLock()
-	defer c.RUnlock()
+	if c.bypassed() {
+		return false
+	}
+	if c.strict {
+		c.lock()
+		defer c.Unlock()
+	} else {
+		c.rlock()
+		defer c.RUnlock()
+	}
 
 	return c.lru.Contains(key)
 }
@@ -85,8 +465,16 @@ func (c *Cache[K, V]) Contains(key K) (ok bool) {
 // Peek returns the key value (or undefined if not found) without updating
 // the "recently used"-ness of the key.
 func (c *Cache[K, V]) Peek(key K) (value V, ok bool) {
-	c.RLock()
-	defer c.RUnlock()
+	if c.bypassed() {
+		return value, false
+	}
+	if c.strict {
+		c.lock()
+		defer c.Unlock()
+	} else {
+		c.rlock()
+		defer c.RUnlock()
+	}
 
 	return c.lru.Peek(key)
 }
@@ -94,56 +482,422 @@ func (c *Cache[K, V]) Peek(key K) (value V, ok bool) {
 // Remove removes the provided key from the cache, returning if the
 // key was contained.
 func (c *Cache[K, V]) Remove(key K) (ok bool) {
-	c.Lock()
+	c.lock()
 	defer c.Unlock()
 
 	return c.lru.Remove(key)
 }
 
+// RemoveGet removes the provided key from the cache, returning its
+// value if it was contained and not expired. While the cache is disabled,
+// the key is removed and reported missing.
+func (c *Cache[K, V]) RemoveGet(key K) (value V, ok bool) {
+	c.lock()
+	defer c.Unlock()
+
+	if c.bypassed() {
+		c.lru.Remove(key)
+		return value, false
+	}
+	return c.lru.RemoveGet(key)
+}
+
+// Pop atomically retrieves and removes the entry of the key, like
+// RemoveGet, so that concurrent callers consume each entry exactly once.
+func (c *Cache[K, V]) Pop(key K) (value V, ok bool) {
+	return c.RemoveGet(key)
+}
+
+// Tombstones returns the removals of the last ttl of WithTombstones,
+// oldest first, so that replication consumers can tell a deleted key apart
+// from one that never existed.
+func (c *Cache[K, V]) Tombstones() []Tombstone[K] {
+	c.lock()
+	defer c.Unlock()
+
+	return c.lru.Tombstones()
+}
+
+// Compute replaces the value of a key with the one returned by fn, called
+// with the current value if exists, removing the key unless keep, under a
+// single lock acquisition, e.g. to increment a counter atomically. Returns
+// the new value and whether the key is in the cache. fn must not use the
+// cache. While the cache is disabled, fn is called as for a missing key
+// and its value is dropped.
+func (c *Cache[K, V]) Compute(key K, fn func(old V, exists bool) (value V, keep bool)) (value V, ok bool) {
+	if c.bypassed() {
+		var old V
+		value, _ = fn(old, false)
+		return value, false
+	}
+	c.lock()
+	defer c.Unlock()
+
+	return c.lru.Compute(key, fn)
+}
+
+// RemoveAll removes the provided keys from the cache under a single lock,
+// returning the number of keys which were contained.
+func (c *Cache[K, V]) RemoveAll(keys []K) (removed int) {
+	c.lock()
+	defer c.Unlock()
+
+	return c.lru.RemoveAll(keys)
+}
+
 // RemoveOldest removes the oldest item from the cache.
 func (c *Cache[K, V]) RemoveOldest() (key K, value V, ok bool) {
-	c.Lock()
+	c.lock()
 	defer c.Unlock()
 
+	if c.bypassed() {
+		c.lru.RemoveOldest()
+		return key, value, false
+	}
 	return c.lru.RemoveOldest()
 }
 
 // GetOldest returns the oldest entry
 func (c *Cache[K, V]) GetOldest() (key K, value V, ok bool) {
-	c.RLock()
+	if c.bypassed() {
+		return key, value, false
+	}
+	c.rlock()
 	defer c.RUnlock()
 
 	return c.lru.GetOldest()
 }
 
+// PopOldestIf atomically removes and returns the least recently used entry
+// for which fn returns true, a nil fn matches every entry. It allows to
+// claim the stalest matching entry when several consumers share the cache.
+// fn is called with the lock held and must not use the cache.
+func (c *Cache[K, V]) PopOldestIf(fn func(key K, value V) bool) (key K, value V, ok bool) {
+	if c.bypassed() {
+		return key, value, false
+	}
+	c.lock()
+	defer c.Unlock()
+
+	return c.lru.PopOldestIf(fn)
+}
+
+// PopNewestIf atomically removes and returns the most recently used entry
+// for which fn returns true, a nil fn matches every entry. fn is called
+// with the lock held and must not use the cache.
+func (c *Cache[K, V]) PopNewestIf(fn func(key K, value V) bool) (key K, value V, ok bool) {
+	if c.bypassed() {
+		return key, value, false
+	}
+	c.lock()
+	defer c.Unlock()
+
+	return c.lru.PopNewestIf(fn)
+}
+
 // Keys returns a slice of the keys in the cache, from oldest to newest.
 func (c *Cache[K, V]) Keys() []K {
-	c.RLock()
+	if c.bypassed() {
+		return nil
+	}
+	c.rlock()
 	defer c.RUnlock()
 
 	return c.lru.Keys()
 }
 
+// Sources returns the number of entries, and their total cost WithWeigher,
+// per source of AddWithSource. The entries added without a source are
+// counted under "".
+func (c *Cache[K, V]) Sources() map[string]SourceUsage {
+	c.rlock()
+	defer c.RUnlock()
+
+	return c.lru.Sources()
+}
+
+// KeysWhere returns the keys for which fn returns true, from oldest
+// to newest, without copying the other keys. fn must not use the cache.
+func (c *Cache[K, V]) KeysWhere(fn func(key K) bool) []K {
+	if c.bypassed() {
+		return nil
+	}
+	c.rlock()
+	defer c.RUnlock()
+
+	return c.lru.KeysWhere(fn)
+}
+
 // Len returns the number of items in the cache.
 func (c *Cache[K, V]) Len() int {
-	c.RLock()
+	c.rlock()
 	defer c.RUnlock()
 
 	return c.lru.Len()
 }
 
+// RemoveExpired removes all the expired entries from the cache.
+func (c *Cache[K, V]) RemoveExpired() (removed int) {
+	c.lock()
+	defer c.Unlock()
+
+	return c.lru.RemoveExpired()
+}
+
+// NextExpiry returns when the next entry expires, so that a scheduler
+// can sleep until then instead of polling.
+func (c *Cache[K, V]) NextExpiry() (at time.Time, ok bool) {
+	c.rlock()
+	defer c.RUnlock()
+
+	return c.lru.NextExpiry()
+}
+
 // Resize changes the cache size.
 func (c *Cache[K, V]) Resize(size int) (evicted int) {
-	c.Lock()
+	c.lock()
 	defer c.Unlock()
 
 	return c.lru.Resize(size)
 }
 
-// Clear is used to completely clear the cache
+// Trim evicts the oldest entries until the cache is within its limits,
+// which is only needed WithBackgroundTrim.
+func (c *Cache[K, V]) Trim() (evicted int) {
+	c.lock()
+	defer c.Unlock()
+
+	return c.lru.Trim()
+}
+
+// SetCapacityLazy changes the cache size like Resize, but does not
+// evict anything. The excess entries are evicted by subsequent Adds.
+func (c *Cache[K, V]) SetCapacityLazy(size int) (excess int) {
+	c.lock()
+	defer c.Unlock()
+
+	return c.lru.SetCapacityLazy(size)
+}
+
+// Clear is used to completely clear the cache. The entries are swapped
+// for an empty generation in constant time, and the eviction callbacks of
+// the retired one are fired by a background goroutine WithOnEvictedAsync,
+// so that Clear does not stall the other callers on a large cache.
 func (c *Cache[K, V]) Clear() {
-	c.Lock()
+	c.lock()
 	defer c.Unlock()
 
 	c.lru.Clear()
 }
+
+// GetEntry is like Get, returning the entry with its metadata.
+func (c *Cache[K, V]) GetEntry(key K) (e Entry[K, V], ok bool) {
+	if c.bypassed() {
+		return e, false
+	}
+	c.lock()
+	defer c.Unlock()
+
+	return c.lru.GetEntry(key)
+}
+
+// GetManyWithExpiry gets the values of the keys found in the cache,
+// with their expiry, like Get.
+func (c *Cache[K, V]) GetManyWithExpiry(keys []K) map[K]ValueWithExpiry[V] {
+	if c.bypassed() {
+		return map[K]ValueWithExpiry[V]{}
+	}
+	c.lock()
+	defer c.Unlock()
+
+	return c.lru.GetManyWithExpiry(keys)
+}
+
+// GetHandle is like Get, returning a handle to operate on the entry
+// without looking its key up again, for tight loops on the same entry.
+func (c *Cache[K, V]) GetHandle(key K) (h Handle[K, V], ok bool) {
+	if c.bypassed() {
+		return h, false
+	}
+	c.lock()
+	defer c.Unlock()
+
+	if h, ok = c.lru.GetHandle(key); ok {
+		h.mu = &c.RWMutex
+	}
+	return h, ok
+}
+
+// PeekEntry is like Peek, returning the entry with its metadata.
+func (c *Cache[K, V]) PeekEntry(key K) (e Entry[K, V], ok bool) {
+	if c.bypassed() {
+		return e, false
+	}
+	c.rlock()
+	defer c.RUnlock()
+
+	return c.lru.PeekEntry(key)
+}
+
+// Items returns a slice of the entries in the cache, from oldest to newest.
+func (c *Cache[K, V]) Items() []Entry[K, V] {
+	if c.bypassed() {
+		return nil
+	}
+	c.rlock()
+	defer c.RUnlock()
+
+	return c.lru.Items()
+}
+
+// ByExpiry returns an iterator over the entries with a TTL or TTI, soonest
+// expiring first, skipping the ones already expired. It has the shape of
+// an iter.Seq2 and walks a snapshot taken when ByExpiry is called, so the
+// cache can be used during the iteration.
+func (c *Cache[K, V]) ByExpiry() func(yield func(key K, value V) bool) {
+	if c.bypassed() {
+		return func(yield func(key K, value V) bool) {}
+	}
+	c.rlock()
+	defer c.RUnlock()
+
+	return c.lru.ByExpiry()
+}
+
+// MostAccessed returns up to n entries with the highest hit counts,
+// most accessed first. It requires WithHitCounting.
+func (c *Cache[K, V]) MostAccessed(n int) []Entry[K, V] {
+	if c.bypassed() {
+		return nil
+	}
+	c.rlock()
+	defer c.RUnlock()
+
+	return c.lru.MostAccessed(n)
+}
+
+// RecentEvictions returns up to n of the entries which recently left
+// the cache, most recent first. It requires WithEvictionHistory.
+func (c *Cache[K, V]) RecentEvictions(n int) []Eviction[K, V] {
+	c.rlock()
+	defer c.RUnlock()
+
+	return c.lru.RecentEvictions(n)
+}
+
+// Subscribe returns a channel of cache events and a function that
+// cancels the subscription and closes the channel. Publishing never
+// blocks, events that do not fit in the buffer are dropped.
+func (c *Cache[K, V]) Subscribe() (events <-chan Event[K, V], cancel func()) {
+	c.lock()
+	defer c.Unlock()
+
+	return c.lru.Subscribe()
+}
+
+// DroppedEvents returns the number of events dropped because
+// a subscriber was too slow.
+func (c *Cache[K, V]) DroppedEvents() uint64 {
+	c.rlock()
+	defer c.RUnlock()
+
+	return c.lru.DroppedEvents()
+}
+
+// Stats returns the counters of the cache since its creation.
+func (c *Cache[K, V]) Stats() Stats {
+	c.rlock()
+	defer c.RUnlock()
+
+	stats := c.lru.Stats()
+	stats.LockWait = c.lockWait.load()
+	return stats
+}
+
+// StatsDelta returns the counters accumulated since the previous call,
+// or since the creation of the cache, atomically, so that periodic
+// scrapers can compute per-interval rates. The counters of Stats keep
+// accumulating, e.g. for a Coordinator. The high-water marks restart from
+// the current Len and cost.
+func (c *Cache[K, V]) StatsDelta() Stats {
+	c.lock()
+	defer c.Unlock()
+
+	stats := c.lru.StatsDelta()
+	h := c.lockWait.load()
+	stats.LockWait = h.Sub(c.lockWait.base)
+	c.lockWait.base = h
+	return stats
+}
+
+// PauseEvictions freezes the composition of the cache, e.g. during critical
+// traffic or while taking a consistent snapshot: until ResumeEvictions, the
+// new entries which do not fit are rejected like with RejectNew instead of
+// evicting others. Expirations, removals and updates still happen.
+func (c *Cache[K, V]) PauseEvictions() {
+	c.lock()
+	defer c.Unlock()
+
+	c.lru.PauseEvictions()
+}
+
+// ResumeEvictions restores the evictions, evicting the entries over the
+// limits, which updates can have grown the cost beyond.
+func (c *Cache[K, V]) ResumeEvictions() (evicted int) {
+	c.lock()
+	defer c.Unlock()
+
+	return c.lru.ResumeEvictions()
+}
+
+// Rebuild replaces the eviction policy, migrating the entries to the
+// new one in recency order, so that the cache stays warm, e.g. from LRU to
+// ARC. The policy set WithPolicy and the decayed eviction are dropped. It
+// returns an error wrapping ErrUnknownPolicy for an unknown kind.
+func (c *Cache[K, V]) Rebuild(kind PolicyKind) error {
+	c.lock()
+	defer c.Unlock()
+
+	return c.lru.Rebuild(kind)
+}
+
+// Close cancels the context of the asynchronous eviction callbacks,
+// skips the pending ones and waits for the running ones to return.
+// It does not hold the lock while waiting, so callbacks may use the cache.
+func (c *Cache[K, V]) Close() error {
+	if u, ok := c.lru.(*unsafeCache[K, V]); ok {
+		c.lock()
+		u.shutdown()
+		c.Unlock()
+	}
+	return c.lru.Close()
+}
+
+// Cost returns the total cost of the entries, as computed by the weigher.
+func (c *Cache[K, V]) Cost() int64 {
+	c.rlock()
+	defer c.RUnlock()
+
+	return c.lru.Cost()
+}
+
+// SetMaxCost changes the maximum total cost of a cache WithWeigher,
+// returning the number of entries evicted.
+func (c *Cache[K, V]) SetMaxCost(maxCost int64) (evicted int) {
+	c.lock()
+	defer c.Unlock()
+
+	return c.lru.SetMaxCost(maxCost)
+}
+
+// UpdateCost changes the cost of an entry of a cache WithWeigher, for values
+// which grew or shrank in place after being added, and evicts the oldest
+// entries if the total cost exceeds the maximum. The cost is computed by
+// the weigher again when the entry is updated. It returns if the key was
+// contained, and false without a weigher.
+func (c *Cache[K, V]) UpdateCost(key K, cost int64) (ok bool) {
+	c.lock()
+	defer c.Unlock()
+
+	return c.lru.UpdateCost(key, cost)
+}
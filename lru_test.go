@@ -95,3 +95,133 @@ func BenchmarkSafeLru_Add(b *testing.B) {
 // cpu: Intel(R) Core(TM) i5-8259U CPU @ 2.30GHz
 // BenchmarkUnsafeLru_Add-8         2793051               430.8 ns/op            81 B/op          3 allocs/op
 // BenchmarkSafeLru_Add-8           1059967              1181 ns/op             262 B/op          5 allocs/op
+
+func TestLru_GetOrAdd(t *testing.T) {
+	l := New[int, int](2)
+	if v, loaded := l.GetOrAdd(1, 1); loaded || v != 1 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, false, v, loaded)
+	}
+	if v, loaded := l.GetOrAdd(1, 10); !loaded || v != 1 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, true, v, loaded)
+	}
+	if s := l.Stats(); s.Hits != 1 || s.Misses != 1 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, 1, s.Hits, s.Misses)
+	}
+
+	var wg sync.WaitGroup
+	var added int32
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, loaded := l.GetOrAdd(2, i); !loaded {
+				atomic.AddInt32(&added, 1)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if added != 1 {
+		t.Fatalf("Expected %v, got %v", 1, added)
+	}
+}
+
+func TestLru_ContainsOrAdd(t *testing.T) {
+	l := New[int, int](2)
+	if ok, evicted := l.ContainsOrAdd(1, 1); ok || evicted {
+		t.Fatalf("Expected %v, %v, got %v, %v", false, false, ok, evicted)
+	}
+	l.Add(2, 2)
+	if ok, evicted := l.ContainsOrAdd(1, 10); !ok || evicted {
+		t.Fatalf("Expected %v, %v, got %v, %v", true, false, ok, evicted)
+	}
+	if v, _ := l.Peek(1); v != 1 {
+		t.Fatalf("Expected %v, got %v", 1, v)
+	}
+	// The recency of 1 is not updated, so it is evicted
+	if ok, evicted := l.ContainsOrAdd(3, 3); ok || !evicted {
+		t.Fatalf("Expected %v, %v, got %v, %v", false, true, ok, evicted)
+	}
+	if l.Contains(1) {
+		t.Fatalf("Expected %v, got %v", false, true)
+	}
+}
+
+func TestLru_PeekOrAdd(t *testing.T) {
+	l := New[int, int](2)
+	if prev, ok, evicted := l.PeekOrAdd(1, 1); ok || evicted || prev != 0 {
+		t.Fatalf("Expected %v, %v, %v, got %v, %v, %v", 0, false, false, prev, ok, evicted)
+	}
+	l.Add(2, 2)
+	if prev, ok, evicted := l.PeekOrAdd(1, 10); !ok || evicted || prev != 1 {
+		t.Fatalf("Expected %v, %v, %v, got %v, %v, %v", 1, true, false, prev, ok, evicted)
+	}
+	// The recency of 1 is not updated, so it is evicted
+	if _, ok, evicted := l.PeekOrAdd(3, 3); ok || !evicted {
+		t.Fatalf("Expected %v, %v, got %v, %v", false, true, ok, evicted)
+	}
+	if l.Contains(1) {
+		t.Fatalf("Expected %v, got %v", false, true)
+	}
+}
+
+func TestLru_Pop(t *testing.T) {
+	l := New[int, int](10)
+	for i := 0; i < 100; i++ {
+		l.Add(i%10, i)
+	}
+
+	var wg sync.WaitGroup
+	var popped int32
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := 0; k < 10; k++ {
+				if _, ok := l.Pop(k); ok {
+					atomic.AddInt32(&popped, 1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if popped != 10 || l.Len() != 0 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 10, 0, popped, l.Len())
+	}
+}
+
+func TestLru_Compute(t *testing.T) {
+	l := New[string, int](10)
+	incr := func(old int, exists bool) (int, bool) {
+		return old + 1, true
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				l.Compute("n", incr)
+			}
+		}()
+	}
+	wg.Wait()
+	if v, _ := l.Get("n"); v != 400 {
+		t.Fatalf("Expected %v, got %v", 400, v)
+	}
+
+	// Not keeping the value removes the key
+	if _, ok := l.Compute("n", func(old int, exists bool) (int, bool) {
+		return 0, false
+	}); ok || l.Contains("n") {
+		t.Fatalf("Expected %v, got %v", false, true)
+	}
+	if v, ok := l.Compute("m", func(old int, exists bool) (int, bool) {
+		if exists {
+			t.Fatalf("Expected %v, got %v", false, exists)
+		}
+		return 7, true
+	}); !ok || v != 7 {
+		t.Fatalf("Expected %v, got %v", 7, v)
+	}
+}
@@ -0,0 +1,61 @@
+package lru
+
+// Map returns a typed view over an existing cache, converting values with
+// to when reading and with from when writing. It is useful when one
+// subsystem caches serialized bytes while another reads decoded structs.
+// The view shares the entries, recency and locking of the underlying cache.
+func Map[K comparable, V1, V2 any](c Cacher[K, V1], to func(V1) V2, from func(V2) V1) *MappedCache[K, V1, V2] {
+	return &MappedCache[K, V1, V2]{
+		cache: c,
+		to:    to,
+		from:  from,
+	}
+}
+
+var _ Cacher[int, string] = (*MappedCache[int, []byte, string])(nil)
+
+// MappedCache is a view of a cache with values of another type.
+type MappedCache[K comparable, V1, V2 any] struct {
+	cache Cacher[K, V1]
+	to    func(V1) V2
+	from  func(V2) V1
+}
+
+// Add a value to the underlying cache. Returns true if an eviction occurred.
+func (c *MappedCache[K, V1, V2]) Add(key K, value V2) (evicted bool) {
+	return c.cache.Add(key, c.from(value))
+}
+
+// Get looks up a key's value from the underlying cache
+func (c *MappedCache[K, V1, V2]) Get(key K) (value V2, ok bool) {
+	v, ok := c.cache.Get(key)
+	if !ok {
+		return value, false
+	}
+	return c.to(v), true
+}
+
+// Contains checks if a key is in the underlying cache.
+func (c *MappedCache[K, V1, V2]) Contains(key K) (ok bool) {
+	return c.cache.Contains(key)
+}
+
+// Peek returns the key value without updating the "recently used"-ness of the key.
+func (c *MappedCache[K, V1, V2]) Peek(key K) (value V2, ok bool) {
+	v, ok := c.cache.Peek(key)
+	if !ok {
+		return value, false
+	}
+	return c.to(v), true
+}
+
+// Remove removes the provided key from the underlying cache,
+// returning if the key was contained.
+func (c *MappedCache[K, V1, V2]) Remove(key K) (ok bool) {
+	return c.cache.Remove(key)
+}
+
+// Clear clears the underlying cache
+func (c *MappedCache[K, V1, V2]) Clear() {
+	c.cache.Clear()
+}
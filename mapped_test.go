@@ -0,0 +1,47 @@
+package lru
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestMap(t *testing.T) {
+	raw := New[string, []byte](10)
+	view := Map[string, []byte, int](raw,
+		func(b []byte) int {
+			n, _ := strconv.Atoi(string(b))
+			return n
+		},
+		func(n int) []byte {
+			return []byte(strconv.Itoa(n))
+		},
+	)
+
+	view.Add("a", 42)
+	if b, ok := raw.Peek("a"); !ok || string(b) != "42" {
+		t.Fatalf("Expected %v, %v, got %v, %v", "42", true, string(b), ok)
+	}
+
+	raw.Add("b", []byte("7"))
+	if v, ok := view.Get("b"); !ok || v != 7 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 7, true, v, ok)
+	}
+	if _, ok := view.Peek("c"); ok {
+		t.Fatal("should not exist")
+	}
+
+	if !view.Remove("a") || raw.Contains("a") || view.Contains("a") {
+		t.Fatal("should be removed")
+	}
+
+	// Views compose with the other caches
+	c := NewChain[string, int](NewUnsafeLru[string, int](1), view)
+	if v, ok := c.Get("b"); !ok || v != 7 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 7, true, v, ok)
+	}
+
+	view.Clear()
+	if raw.Len() != 0 {
+		t.Fatalf("Expected %v, got %v", 0, raw.Len())
+	}
+}
@@ -0,0 +1,37 @@
+package lru
+
+// WithMaxValueCost rejects the entries whose cost, as computed by the
+// weigher of WithWeigher, exceeds limit. Put reports them as
+// AddResultTooLarge, and an existing entry keeps its previous value,
+// so that a single pathological value cannot flush most of the cache.
+// It has no effect without a weigher.
+func WithMaxValueCost[K comparable, V any](limit int64) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		c.guardSize(func(key K, value V, cost int64) bool {
+			return c.weigher != nil && cost > limit
+		})
+	}
+}
+
+// WithMaxKeyLength rejects the string keys longer than limit bytes,
+// Put reports them as AddResultTooLarge.
+func WithMaxKeyLength[V any](limit int) Option[string, V] {
+	return func(c *unsafeCache[string, V]) {
+		c.guardSize(func(key string, value V, cost int64) bool {
+			return len(key) > limit
+		})
+	}
+}
+
+// guardSize adds a check to the oversized entries guard, which is passed
+// the cost of the entry computed by weigh.
+func (c *unsafeCache[K, V]) guardSize(oversized func(key K, value V, cost int64) bool) {
+	prev := c.oversized
+	if prev == nil {
+		c.oversized = oversized
+		return
+	}
+	c.oversized = func(key K, value V, cost int64) bool {
+		return prev(key, value, cost) || oversized(key, value, cost)
+	}
+}
@@ -0,0 +1,58 @@
+package lru
+
+import "testing"
+
+func TestLru_WithMaxValueCost(t *testing.T) {
+	evicted, weighs := 0, 0
+	l := New[int, int](10,
+		WithWeigher(func(k, v int) int64 { weighs++; return int64(v) }, 100),
+		WithMaxValueCost[int, int](10),
+		WithOnEvicted(func(k, v int) { evicted++ }),
+	)
+	l.Add(1, 5)
+	l.Add(2, 5)
+	if r := l.Put(3, 50); r != AddResultTooLarge {
+		t.Fatalf("Expected %v, got %v", AddResultTooLarge, r)
+	}
+	if l.Contains(3) || l.Len() != 2 || evicted != 0 || l.Cost() != 10 {
+		t.Fatal("oversized entry should be rejected")
+	}
+
+	// An existing entry keeps its value
+	if r := l.Put(1, 50); r != AddResultTooLarge {
+		t.Fatalf("Expected %v, got %v", AddResultTooLarge, r)
+	}
+	if v, _ := l.Peek(1); v != 5 {
+		t.Fatalf("Expected %v, got %v", 5, v)
+	}
+	if r := l.Put(1, 10); r != AddResultUpdated {
+		t.Fatalf("Expected %v, got %v", AddResultUpdated, r)
+	}
+	// Each write weighs its entry once
+	if weighs != 5 {
+		t.Fatalf("Expected %v, got %v", 5, weighs)
+	}
+
+	// No weigher, no limit
+	u := New[int, int](10, WithMaxValueCost[int, int](10))
+	if r := u.Put(1, 50); r != AddResultAdded {
+		t.Fatalf("Expected %v, got %v", AddResultAdded, r)
+	}
+}
+
+func TestLru_WithMaxKeyLength(t *testing.T) {
+	l := New[string, int](10,
+		WithMaxKeyLength[int](3),
+		WithWeigher(func(k string, v int) int64 { return int64(v) }, 100),
+		WithMaxValueCost[string, int](10),
+	)
+	if r := l.Put("abcd", 1); r != AddResultTooLarge {
+		t.Fatalf("Expected %v, got %v", AddResultTooLarge, r)
+	}
+	if r := l.Put("abc", 11); r != AddResultTooLarge {
+		t.Fatalf("Expected %v, got %v", AddResultTooLarge, r)
+	}
+	if l.Add("abc", 1); !l.Contains("abc") || l.Len() != 1 {
+		t.Fatal("small entry should be added")
+	}
+}
@@ -0,0 +1,105 @@
+package lru
+
+import "sync"
+
+// NewMulti creates a MultiCache holding up to maxKeys keys, with up to
+// maxValues values each. The options apply to the underlying LRU of
+// value slices.
+func NewMulti[K comparable, V any](maxKeys, maxValues int, opts ...Option[K, []V]) *MultiCache[K, V] {
+	if maxValues <= 0 {
+		maxValues = defaultSize
+	}
+	return &MultiCache[K, V]{
+		maxValues: maxValues,
+		lru:       NewUnsafeLru[K, []V](maxKeys, opts...),
+	}
+}
+
+// MultiCache is a thread-safe LRU cache mapping each key to a bounded
+// list of values, e.g. the recent events of each user. Appending to a key
+// with maxValues values drops its oldest value, and the least recently
+// used keys are evicted with all their values.
+type MultiCache[K comparable, V any] struct {
+	maxValues int
+
+	lru Lru[K, []V]
+
+	sync.Mutex
+}
+
+// Append a value to the values of the key. Returns true if a key was evicted.
+func (c *MultiCache[K, V]) Append(key K, value V) (evicted bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	values, _ := c.lru.Peek(key)
+	if len(values) >= c.maxValues {
+		// Drop the oldest values, without retaining them
+		n := copy(values, values[len(values)-c.maxValues+1:])
+		for i := n; i < len(values); i++ {
+			var zero V
+			values[i] = zero
+		}
+		values = values[:n]
+	}
+	return c.lru.Add(key, append(values, value))
+}
+
+// GetAll returns a copy of the values of the key, from oldest to newest,
+// and updates the "recently used"-ness of the key.
+func (c *MultiCache[K, V]) GetAll(key K) (values []V, ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	values, ok = c.lru.Get(key)
+	return append([]V(nil), values...), ok
+}
+
+// PeekAll is like GetAll, without updating the "recently used"-ness of the key.
+func (c *MultiCache[K, V]) PeekAll(key K) (values []V, ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	values, ok = c.lru.Peek(key)
+	return append([]V(nil), values...), ok
+}
+
+// Contains checks if the key has values.
+func (c *MultiCache[K, V]) Contains(key K) (ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.lru.Contains(key)
+}
+
+// Remove removes the key with all its values, returning if it was contained.
+func (c *MultiCache[K, V]) Remove(key K) (ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.lru.Remove(key)
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest.
+func (c *MultiCache[K, V]) Keys() []K {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.lru.Keys()
+}
+
+// Len returns the number of keys in the cache.
+func (c *MultiCache[K, V]) Len() int {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.lru.Len()
+}
+
+// Clear is used to completely clear the cache
+func (c *MultiCache[K, V]) Clear() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.lru.Clear()
+}
@@ -0,0 +1,45 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMultiCache(t *testing.T) {
+	c := NewMulti[string, int](2, 3)
+	for i := 0; i < 5; i++ {
+		c.Append("a", i)
+	}
+	c.Append("b", 10)
+
+	values, ok := c.GetAll("a")
+	if expected := []int{2, 3, 4}; !ok || !reflect.DeepEqual(values, expected) {
+		t.Fatalf("Expected %v, got %v", expected, values)
+	}
+
+	// The returned slice is a copy
+	values[0] = 100
+	if values, _ = c.PeekAll("a"); values[0] != 2 {
+		t.Fatalf("Expected %v, got %v", 2, values[0])
+	}
+
+	// b is the least recently used key
+	if !c.Append("c", 20) || c.Contains("b") {
+		t.Fatal("b should be evicted")
+	}
+	if !reflect.DeepEqual(c.Keys(), []string{"a", "c"}) {
+		t.Fatalf("Expected %v, got %v", []string{"a", "c"}, c.Keys())
+	}
+
+	if !c.Remove("a") || c.Len() != 1 {
+		t.Fatal("a should be removed")
+	}
+	if _, ok = c.GetAll("a"); ok {
+		t.Fatal("should not exist")
+	}
+
+	c.Clear()
+	if c.Len() != 0 {
+		t.Fatalf("Expected %v, got %v", 0, c.Len())
+	}
+}
@@ -0,0 +1,19 @@
+package lru
+
+// WithKeyNormalizer canonicalizes the keys of every operation with normalize,
+// e.g. lowercasing or trimming strings, so that semantically equal keys do
+// not create duplicate entries. It must be idempotent. The cache holds and
+// returns the normalized keys.
+func WithKeyNormalizer[K comparable, V any](normalize func(key K) K) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		c.keyNormalizer = normalize
+	}
+}
+
+// normalize returns the canonical form of the key.
+func (c *unsafeCache[K, V]) normalize(key K) K {
+	if c.keyNormalizer == nil {
+		return key
+	}
+	return c.keyNormalizer(key)
+}
@@ -0,0 +1,45 @@
+package lru
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLru_WithKeyNormalizer(t *testing.T) {
+	l := New[string, int](128, WithKeyNormalizer[string, int](func(key string) string {
+		return strings.ToLower(strings.TrimSpace(key))
+	}))
+	l.Add("Alice", 1)
+	l.Add(" alice ", 2)
+	if l.Len() != 1 || !reflect.DeepEqual(l.Keys(), []string{"alice"}) {
+		t.Fatalf("Expected %v, got %v", []string{"alice"}, l.Keys())
+	}
+
+	if v, ok := l.Get("ALICE"); !ok || v != 2 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 2, true, v, ok)
+	}
+	if v, ok := l.Peek("Alice "); !ok || v != 2 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 2, true, v, ok)
+	}
+	if !l.Contains("aLiCe") {
+		t.Fatal("should contain")
+	}
+	if e, ok := l.GetEntry("Alice"); !ok || e.Key != "alice" {
+		t.Fatalf("bad entry: %+v", e)
+	}
+	if _, ok := l.PeekEntry("Alice"); !ok {
+		t.Fatal("should peek")
+	}
+	if l.Put("ALICE", 3) != AddResultUpdated {
+		t.Fatal("should update")
+	}
+	if !l.Remove("ALICE") {
+		t.Fatal("should remove")
+	}
+
+	l.Add("Bob", 1)
+	if removed := l.RemoveAll([]string{"BOB", "bob"}); removed != 1 {
+		t.Fatalf("Expected %v, got %v", 1, removed)
+	}
+}
@@ -0,0 +1,56 @@
+package lru
+
+// Observer is notified synchronously of what happens to the entries of a
+// cache, with the lock held, so it must be fast and must not use the cache.
+// Embed NopObserver to implement only some of the methods. Unlike the
+// channels of Subscribe, observers never miss a notification.
+type Observer[K comparable, V any] interface {
+	// OnAdd is called when a new key is added.
+	OnAdd(e Entry[K, V])
+	// OnHit is called when a lookup finds the key.
+	OnHit(e Entry[K, V])
+	// OnMiss is called when a lookup does not find the key.
+	OnMiss(key K)
+	// OnEvict is called when an entry is evicted to make room.
+	OnEvict(e Entry[K, V])
+	// OnExpire is called when an entry is dropped for being stale.
+	OnExpire(e Entry[K, V])
+}
+
+// NopObserver is an Observer ignoring every notification.
+type NopObserver[K comparable, V any] struct{}
+
+func (NopObserver[K, V]) OnAdd(e Entry[K, V])    {}
+func (NopObserver[K, V]) OnHit(e Entry[K, V])    {}
+func (NopObserver[K, V]) OnMiss(key K)           {}
+func (NopObserver[K, V]) OnEvict(e Entry[K, V])  {}
+func (NopObserver[K, V]) OnExpire(e Entry[K, V]) {}
+
+// WithObserver registers an observer, the option can be repeated to
+// register several of them, which are notified in order. It has no
+// effect WithNoStats.
+func WithObserver[K comparable, V any](observer Observer[K, V]) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		if observer != nil && !c.noStats {
+			c.events.observers = append(c.events.observers, observer)
+		}
+	}
+}
+
+// notify dispatches an event to the observers.
+func (h *eventHub[K, V]) notify(kind EventKind, e Entry[K, V]) {
+	for _, o := range h.observers {
+		switch kind {
+		case EventAdd:
+			o.OnAdd(e)
+		case EventHit:
+			o.OnHit(e)
+		case EventMiss:
+			o.OnMiss(e.Key)
+		case EventEvict:
+			o.OnEvict(e)
+		case EventExpire:
+			o.OnExpire(e)
+		}
+	}
+}
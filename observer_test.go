@@ -0,0 +1,49 @@
+package lru
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+type recordingObserver struct {
+	NopObserver[string, int]
+	name string
+	log  *[]string
+}
+
+func (o recordingObserver) OnHit(e Entry[string, int]) {
+	*o.log = append(*o.log, fmt.Sprintf("%s hit %s", o.name, e.Key))
+}
+
+func (o recordingObserver) OnMiss(key string) {
+	*o.log = append(*o.log, fmt.Sprintf("%s miss %s", o.name, key))
+}
+
+func (o recordingObserver) OnEvict(e Entry[string, int]) {
+	*o.log = append(*o.log, fmt.Sprintf("%s evict %s", o.name, e.Key))
+}
+
+func (o recordingObserver) OnExpire(e Entry[string, int]) {
+	*o.log = append(*o.log, fmt.Sprintf("%s expire %s", o.name, e.Key))
+}
+
+func Test_unsafeCache_WithObserver(t *testing.T) {
+	var log []string
+	c, clock := newTTLCache(time.Minute,
+		WithObserver[string, int](recordingObserver{name: "a", log: &log}),
+		WithObserver[string, int](recordingObserver{name: "b", log: &log}),
+	)
+	c.Resize(1)
+	c.Add("x", 1)
+	c.Get("x")
+	c.Add("y", 2)
+	c.Get("x")
+	clock.advance(time.Minute)
+	c.Get("y")
+
+	expected := "[a hit x b hit x a evict x b evict x a miss x b miss x a expire y b expire y a miss y b miss y]"
+	if fmt.Sprint(log) != expected {
+		t.Fatalf("Expected %v, got %v", expected, log)
+	}
+}
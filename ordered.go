@@ -0,0 +1,83 @@
+package lru
+
+import "sync"
+
+// WithOrderedCallbacks makes the asynchronous eviction callbacks of
+// WithOnEvictedAsync and WithOnEvictedContext run one at a time on a single
+// goroutine, in eviction order, e.g. to replicate evictions to a system
+// where ordering matters. Evictions are queued without blocking the cache.
+func WithOrderedCallbacks[K comparable, V any]() Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		c.ordered = true
+	}
+}
+
+// callbackQueue is an unbounded FIFO of evicted entries
+// consumed by a single goroutine.
+type callbackQueue[K comparable, V any] struct {
+	items  []Entry[K, V]
+	closed bool
+
+	mu   sync.Mutex
+	cond *sync.Cond
+}
+
+// enqueue queues an eviction, starting the consumer on first use.
+// Evictions after Close are dropped.
+func (c *unsafeCache[K, V]) enqueue(key K, value V) {
+	c.closeMu.Lock()
+	q := c.queue
+	if q == nil {
+		if !c.track() {
+			c.closeMu.Unlock()
+			return
+		}
+		q = &callbackQueue[K, V]{}
+		q.cond = sync.NewCond(&q.mu)
+		c.queue = q
+		go c.consume(q)
+	}
+	c.closeMu.Unlock()
+
+	q.mu.Lock()
+	if !q.closed {
+		q.items = append(q.items, Entry[K, V]{Key: key, Value: value})
+	}
+	q.mu.Unlock()
+	q.cond.Signal()
+}
+
+// consume executes the queued callbacks until Close.
+func (c *unsafeCache[K, V]) consume(q *callbackQueue[K, V]) {
+	defer c.untrack()
+
+	for {
+		q.mu.Lock()
+		for len(q.items) == 0 && !q.closed {
+			q.cond.Wait()
+		}
+		if q.closed {
+			q.mu.Unlock()
+			return
+		}
+		items := q.items
+		q.items = nil
+		q.mu.Unlock()
+
+		for _, e := range items {
+			if c.closed() {
+				break
+			}
+			c.onEvicted(e.Key, e.Value)
+		}
+	}
+}
+
+// close stops the consumer, dropping the pending callbacks.
+func (q *callbackQueue[K, V]) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.items = nil
+	q.mu.Unlock()
+	q.cond.Broadcast()
+}
@@ -0,0 +1,71 @@
+package lru
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func Test_unsafeCache_WithOrderedCallbacks(t *testing.T) {
+	var (
+		n       = 1000
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		evicted []int
+		c       = NewUnsafeLru[int, int](1,
+			WithOnEvictedAsync(func(k, v int) {
+				mu.Lock()
+				evicted = append(evicted, k)
+				mu.Unlock()
+				wg.Done()
+			}),
+			WithOrderedCallbacks[int, int](),
+		)
+	)
+	wg.Add(n)
+	for i := 0; i <= n; i++ {
+		c.Add(i, i)
+	}
+	wg.Wait()
+
+	expected := make([]int, n)
+	for i := range expected {
+		expected[i] = i
+	}
+	if !reflect.DeepEqual(evicted, expected) {
+		t.Fatal("callbacks not executed in eviction order")
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCache_CloseWhileEvicting(t *testing.T) {
+	c := New[int, int](1,
+		WithOnEvictedAsync(func(k, v int) {}),
+		WithOrderedCallbacks[int, int](),
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				c.Add(i*1000+j, j)
+			}
+		}(i)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+
+	// The evictions after Close do not start a new consumer
+	c.Add(-1, 0)
+	c.Add(-2, 0)
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,164 @@
+// Package otelru instruments the caches of package lru with OpenTelemetry
+// tracing, so that cache behavior appears in the traces of slow requests.
+//
+// Loads and bulk operations get their own spans. Lookups are too frequent
+// for spans, a lookup slower than the threshold, which for these constant
+// time operations is time spent waiting for the lock, adds an event to the
+// span of the caller instead.
+package otelru
+
+import (
+	"context"
+	"time"
+
+	"github.com/electricbubble/lru"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// tracerName identifies the instrumentation.
+	tracerName = "github.com/electricbubble/lru/otelru"
+
+	// defaultSlowThreshold is the duration after which a lookup is reported.
+	defaultSlowThreshold = time.Millisecond
+)
+
+// Loader loads the value of a key missing from the cache.
+type Loader[K comparable, V any] func(ctx context.Context, key K) (V, error)
+
+// Option configures a Cache.
+type Option func(o *options)
+
+type options struct {
+	slowThreshold time.Duration
+}
+
+// WithSlowThreshold sets the duration after which a lookup adds
+// a "lru.slow" event to the span of the caller. It defaults to 1ms.
+func WithSlowThreshold(threshold time.Duration) Option {
+	return func(o *options) {
+		o.slowThreshold = threshold
+	}
+}
+
+// Wrap instruments the cache with the tracers of tp, which defaults to
+// the global tracer provider.
+func Wrap[K comparable, V any](cache lru.Lru[K, V], tp trace.TracerProvider, opts ...Option) *Cache[K, V] {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	o := options{slowThreshold: defaultSlowThreshold}
+	for _, fn := range opts {
+		if fn == nil {
+			continue
+		}
+		fn(&o)
+	}
+	return &Cache[K, V]{
+		Lru:           cache,
+		tracer:        tp.Tracer(tracerName),
+		slowThreshold: o.slowThreshold,
+	}
+}
+
+// Cache is an instrumented cache. The methods taking a context are traced,
+// the methods of the embedded Lru are not.
+type Cache[K comparable, V any] struct {
+	lru.Lru[K, V]
+
+	tracer        trace.Tracer
+	slowThreshold time.Duration
+}
+
+// GetContext is like Get, reporting a slow lookup to the span of ctx.
+func (c *Cache[K, V]) GetContext(ctx context.Context, key K) (value V, ok bool) {
+	start := time.Now()
+	value, ok = c.Lru.Get(key)
+	c.observe(ctx, "Get", start)
+	return value, ok
+}
+
+// AddContext is like Add, reporting a slow insert to the span of ctx.
+func (c *Cache[K, V]) AddContext(ctx context.Context, key K, value V) (evicted bool) {
+	start := time.Now()
+	evicted = c.Lru.Add(key, value)
+	c.observe(ctx, "Add", start)
+	return evicted
+}
+
+// Load returns the value of the key from the cache, or from the loader,
+// caching it, in a "lru.Load" span. Failed loads are reported as
+// a *lru.LoadError.
+func (c *Cache[K, V]) Load(ctx context.Context, key K, loader Loader[K, V]) (value V, err error) {
+	ctx, span := c.tracer.Start(ctx, "lru.Load")
+	defer span.End()
+
+	value, ok := c.GetContext(ctx, key)
+	span.SetAttributes(attribute.Bool("lru.hit", ok))
+	if ok {
+		return value, nil
+	}
+
+	if value, err = loader(ctx, key); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return value, &lru.LoadError[K]{Key: key, Err: err}
+	}
+	c.AddContext(ctx, key, value)
+	return value, nil
+}
+
+// ClearContext is like Clear, in a "lru.Clear" span.
+func (c *Cache[K, V]) ClearContext(ctx context.Context) {
+	_, span := c.tracer.Start(ctx, "lru.Clear")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("lru.len", c.Lru.Len()))
+	c.Lru.Clear()
+}
+
+// ResizeContext is like Resize, in a "lru.Resize" span.
+func (c *Cache[K, V]) ResizeContext(ctx context.Context, size int) (evicted int) {
+	_, span := c.tracer.Start(ctx, "lru.Resize")
+	defer span.End()
+
+	evicted = c.Lru.Resize(size)
+	span.SetAttributes(attribute.Int("lru.size", size), attribute.Int("lru.evicted", evicted))
+	return evicted
+}
+
+// RemoveExpiredContext is like RemoveExpired, in a "lru.RemoveExpired" span.
+func (c *Cache[K, V]) RemoveExpiredContext(ctx context.Context) (removed int) {
+	_, span := c.tracer.Start(ctx, "lru.RemoveExpired")
+	defer span.End()
+
+	removed = c.Lru.RemoveExpired()
+	span.SetAttributes(attribute.Int("lru.removed", removed))
+	return removed
+}
+
+// ItemsContext is like Items, in a "lru.Items" span.
+func (c *Cache[K, V]) ItemsContext(ctx context.Context) []lru.Entry[K, V] {
+	_, span := c.tracer.Start(ctx, "lru.Items")
+	defer span.End()
+
+	items := c.Lru.Items()
+	span.SetAttributes(attribute.Int("lru.len", len(items)))
+	return items
+}
+
+// observe adds a "lru.slow" event to the span of ctx if op took longer
+// than the threshold since start.
+func (c *Cache[K, V]) observe(ctx context.Context, op string, start time.Time) {
+	elapsed := time.Since(start)
+	if elapsed < c.slowThreshold {
+		return
+	}
+	trace.SpanFromContext(ctx).AddEvent("lru.slow", trace.WithAttributes(
+		attribute.String("lru.op", op),
+		attribute.Int64("lru.elapsed_us", elapsed.Microseconds()),
+	))
+}
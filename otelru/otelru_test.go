@@ -0,0 +1,104 @@
+package otelru
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/electricbubble/lru"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTracedCache(opts ...Option) (*Cache[string, int], *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	return Wrap[string, int](lru.New[string, int](128), tp, opts...), recorder
+}
+
+func TestCache_Load(t *testing.T) {
+	c, recorder := newTracedCache()
+	ctx := context.Background()
+	loader := func(ctx context.Context, key string) (int, error) {
+		if key == "missing" {
+			return 0, lru.ErrNotFound
+		}
+		return len(key), nil
+	}
+
+	for i := 0; i < 2; i++ {
+		if v, err := c.Load(ctx, "abc", loader); err != nil || v != 3 {
+			t.Fatalf("Expected %v, %v, got %v, %v", 3, nil, v, err)
+		}
+	}
+	if _, err := c.Load(ctx, "missing", loader); !errors.Is(err, lru.ErrNotFound) {
+		t.Fatalf("Expected %v, got %v", lru.ErrNotFound, err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("Expected %v spans, got %v", 3, len(spans))
+	}
+	for i, hit := range []bool{false, true, false} {
+		attrs := spans[i].Attributes()
+		if spans[i].Name() != "lru.Load" || len(attrs) != 1 || attrs[0].Value.AsBool() != hit {
+			t.Fatalf("bad span %v: %v %v", i, spans[i].Name(), attrs)
+		}
+	}
+	if len(spans[2].Events()) != 1 || spans[2].Events()[0].Name != "exception" {
+		t.Fatalf("error not recorded: %v", spans[2].Events())
+	}
+}
+
+func TestCache_BulkOperations(t *testing.T) {
+	c, recorder := newTracedCache()
+	ctx := context.Background()
+	for i, key := range []string{"a", "b", "c"} {
+		c.AddContext(ctx, key, i)
+	}
+
+	if items := c.ItemsContext(ctx); len(items) != 3 {
+		t.Fatalf("Expected %v, got %v", 3, len(items))
+	}
+	if evicted := c.ResizeContext(ctx, 2); evicted != 1 {
+		t.Fatalf("Expected %v, got %v", 1, evicted)
+	}
+	c.RemoveExpiredContext(ctx)
+	c.ClearContext(ctx)
+
+	var names []string
+	for _, span := range recorder.Ended() {
+		names = append(names, span.Name())
+	}
+	expected := []string{"lru.Items", "lru.Resize", "lru.RemoveExpired", "lru.Clear"}
+	if len(names) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, names)
+		}
+	}
+}
+
+func TestCache_SlowLookup(t *testing.T) {
+	c, recorder := newTracedCache(WithSlowThreshold(10 * time.Millisecond))
+	ctx, span := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test").Start(context.Background(), "request")
+
+	// Hold the lock of the cache to delay the lookup
+	cache := c.Lru.(*lru.Cache[string, int])
+	cache.Lock()
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cache.Unlock()
+	}()
+	c.GetContext(ctx, "a")
+	c.GetContext(ctx, "a")
+	span.End()
+
+	events := recorder.Ended()[0].Events()
+	if len(events) != 1 || events[0].Name != "lru.slow" {
+		t.Fatalf("Expected one slow event, got %v", events)
+	}
+}
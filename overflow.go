@@ -0,0 +1,67 @@
+package lru
+
+// OverflowPolicy decides what happens when a new entry is added to a full cache.
+type OverflowPolicy uint8
+
+const (
+	// EvictOldest makes room for the new entry by evicting the oldest ones.
+	EvictOldest OverflowPolicy = iota
+	// RejectNew drops the new entry, so that Adds never run evictions
+	// and their callbacks.
+	RejectNew
+)
+
+// AddResult tells what Put did.
+type AddResult uint8
+
+const (
+	// AddResultAdded is returned when a new entry was added.
+	AddResultAdded AddResult = iota + 1
+	// AddResultUpdated is returned when the value of an existing entry was replaced.
+	AddResultUpdated
+	// AddResultEvicted is returned when entries were evicted to make room.
+	AddResultEvicted
+	// AddResultRejected is returned when the cache was full and RejectNew,
+	// or PauseEvictions, dropped the new entry.
+	AddResultRejected
+	// AddResultTooLarge is returned when the entry exceeded the size
+	// limit of WithMaxValueCost or WithMaxKeyLength and was dropped.
+	AddResultTooLarge
+)
+
+// WithOverflowPolicy sets what happens when a new entry is added to
+// a full cache, the default is EvictOldest. The entry limit and the
+// cost limit of a weigher are both considered. Existing entries can
+// always be updated.
+func WithOverflowPolicy[K comparable, V any](policy OverflowPolicy) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		c.overflow = policy
+	}
+}
+
+func (c *unsafeCache[K, V]) Put(key K, value V) (result AddResult) {
+	return c.add(key, value, c.ttl)
+}
+
+// full reports whether adding a new entry of cost would exceed a limit.
+func (c *unsafeCache[K, V]) full(cost int64) bool {
+	if c.entries.Len() >= c.maxEntries {
+		return true
+	}
+	return c.weigher != nil && c.cost+cost > c.maxCost
+}
+
+// rejecting reports whether the new entries are dropped rather than
+// evicting others when the cache is full.
+func (c *unsafeCache[K, V]) rejecting() bool {
+	return c.overflow == RejectNew || c.paused
+}
+
+func (c *unsafeCache[K, V]) PauseEvictions() {
+	c.paused = true
+}
+
+func (c *unsafeCache[K, V]) ResumeEvictions() (evicted int) {
+	c.paused = false
+	return c.Trim()
+}
@@ -0,0 +1,82 @@
+package lru
+
+import "testing"
+
+func Test_unsafeCache_Put(t *testing.T) {
+	c := NewUnsafeLru[int, int](2)
+	if r := c.Put(1, 1); r != AddResultAdded {
+		t.Fatalf("Expected %v, got %v", AddResultAdded, r)
+	}
+	if r := c.Put(1, 10); r != AddResultUpdated {
+		t.Fatalf("Expected %v, got %v", AddResultUpdated, r)
+	}
+	c.Put(2, 2)
+	if r := c.Put(3, 3); r != AddResultEvicted || c.Contains(1) {
+		t.Fatalf("Expected %v, got %v", AddResultEvicted, r)
+	}
+}
+
+func Test_unsafeCache_WithOverflowPolicy(t *testing.T) {
+	evicted := 0
+	c := NewUnsafeLru[int, int](2,
+		WithOverflowPolicy[int, int](RejectNew),
+		WithOnEvicted(func(k, v int) { evicted++ }),
+	)
+	c.Add(1, 1)
+	c.Add(2, 2)
+	if r := c.Put(3, 3); r != AddResultRejected {
+		t.Fatalf("Expected %v, got %v", AddResultRejected, r)
+	}
+	if c.Add(3, 3) || c.Contains(3) || c.Len() != 2 || evicted != 0 {
+		t.Fatal("new entry should be rejected")
+	}
+	if r := c.Put(1, 10); r != AddResultUpdated {
+		t.Fatalf("Expected %v, got %v", AddResultUpdated, r)
+	}
+
+	c.Remove(2)
+	if r := c.Put(3, 3); r != AddResultAdded {
+		t.Fatalf("Expected %v, got %v", AddResultAdded, r)
+	}
+
+	// The cost limit is considered as well
+	w := NewUnsafeLru[int, int](10,
+		WithOverflowPolicy[int, int](RejectNew),
+		WithWeigher(func(k, v int) int64 { return int64(v) }, 10),
+	)
+	w.Add(1, 6)
+	if r := w.Put(2, 5); r != AddResultRejected {
+		t.Fatalf("Expected %v, got %v", AddResultRejected, r)
+	}
+	if r := w.Put(2, 4); r != AddResultAdded {
+		t.Fatalf("Expected %v, got %v", AddResultAdded, r)
+	}
+}
+
+func Test_unsafeCache_PauseEvictions(t *testing.T) {
+	c := NewUnsafeLru[string, int](2, WithWeigher[string, int](func(k string, v int) int64 { return int64(v) }, 10))
+	c.Add("a", 1)
+	c.Add("b", 1)
+
+	c.PauseEvictions()
+	if r := c.Put("c", 1); r != AddResultRejected {
+		t.Fatalf("Expected %v, got %v", AddResultRejected, r)
+	}
+	if c.AddNew("c", 1) {
+		t.Fatalf("Expected %v, got %v", false, true)
+	}
+	// Updates still happen, without evicting
+	if r := c.Put("a", 20); r != AddResultUpdated {
+		t.Fatalf("Expected %v, got %v", AddResultUpdated, r)
+	}
+	if c.Len() != 2 || c.Cost() != 21 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 2, 21, c.Len(), c.Cost())
+	}
+
+	if evicted := c.ResumeEvictions(); evicted != 2 {
+		t.Fatalf("Expected %v, got %v", 2, evicted)
+	}
+	if r := c.Put("c", 1); r != AddResultAdded {
+		t.Fatalf("Expected %v, got %v", AddResultAdded, r)
+	}
+}
@@ -0,0 +1,244 @@
+// Package peer lets a set of processes treat their LRU caches as one logical
+// cache, in the style of groupcache. Every key is owned by exactly one peer,
+// chosen by consistent hashing. The owner fills its cache with the Getter,
+// and the other peers fetch the value from it over HTTP.
+package peer
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/electricbubble/lru"
+	"github.com/electricbubble/lru/consistenthash"
+)
+
+const (
+	// DefaultBasePath is the URL path prefix served by a Group.
+	DefaultBasePath = "/_lru/"
+
+	// defaultReplicas is the number of virtual nodes per peer.
+	defaultReplicas = 50
+)
+
+// Getter loads the value of a key when it is missing from every cache.
+// It should return lru.ErrNotFound, or an error wrapping it, for keys
+// that do not exist.
+type Getter[K comparable, V any] func(ctx context.Context, key K) (V, error)
+
+// Group is a cache namespace shared by a set of peers.
+type Group[K comparable, V any] struct {
+	// Counters first, 64-bit aligned for atomic access
+	gets, cacheHits, peerLoads, peerErrors, loads, loadErrors int64
+	missPenalty                                               int64
+
+	name       string
+	cache      *lru.Cache[K, V]
+	getter     Getter[K, V]
+	keyCodec   Codec[K]
+	valueCodec Codec[V]
+
+	// Client is used to fetch values from other peers.
+	Client *http.Client
+
+	self  string
+	peers *consistenthash.Map[string]
+
+	flight flight[K, V]
+	closed int32
+
+	latencies latencies
+
+	mu sync.RWMutex
+}
+
+func NewGroup[K comparable, V any](name string, maxEntries int, getter Getter[K, V], keyCodec Codec[K], valueCodec Codec[V]) *Group[K, V] {
+	return &Group[K, V]{
+		name:       name,
+		cache:      lru.New[K, V](maxEntries),
+		getter:     getter,
+		keyCodec:   keyCodec,
+		valueCodec: valueCodec,
+		Client:     http.DefaultClient,
+		peers:      consistenthash.New[string](defaultReplicas, nil, nil),
+	}
+}
+
+// SetPeers updates the peer base URLs, e.g. "http://10.0.0.1:8080".
+// self is the base URL of this process and should be one of peers.
+func (g *Group[K, V]) SetPeers(self string, peers ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.self = self
+	g.peers = consistenthash.New[string](defaultReplicas, nil, nil)
+	g.peers.Add(peers...)
+}
+
+// SetMissPenalty sets the estimated cost of a miss, used by Stats to report
+// the time saved by the cache hits. By default, the median latency of the
+// recent Getter calls is used.
+func (g *Group[K, V]) SetMissPenalty(penalty time.Duration) {
+	atomic.StoreInt64(&g.missPenalty, int64(penalty))
+}
+
+// Get returns the value of the key, from the local cache, from the peer
+// owning the key, or from the Getter. Concurrent loads of the same key
+// are deduplicated. Failed loads are reported as a *lru.LoadError.
+func (g *Group[K, V]) Get(ctx context.Context, key K) (value V, err error) {
+	if atomic.LoadInt32(&g.closed) == 1 {
+		return value, lru.ErrClosed
+	}
+	atomic.AddInt64(&g.gets, 1)
+	if value, ok := g.cache.Get(key); ok {
+		atomic.AddInt64(&g.cacheHits, 1)
+		return value, nil
+	}
+
+	return g.flight.do(key, func() (V, error) {
+		keyData, err := g.keyCodec.Marshal(key)
+		if err != nil {
+			return value, err
+		}
+
+		g.mu.RLock()
+		owner, ok := g.peers.Get(string(keyData))
+		self := g.self
+		g.mu.RUnlock()
+
+		if ok && owner != self {
+			value, err := g.fetch(ctx, owner, keyData)
+			if err == nil {
+				atomic.AddInt64(&g.peerLoads, 1)
+				return value, nil
+			}
+			if errors.Is(err, lru.ErrNotFound) {
+				return value, &lru.LoadError[K]{Key: key, Err: err}
+			}
+			// The owner is unavailable, fall back to loading locally
+			atomic.AddInt64(&g.peerErrors, 1)
+		}
+		return g.load(ctx, key)
+	})
+}
+
+// ServeHTTP answers fetches from other peers for the keys owned by this process.
+func (g *Group[K, V]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if atomic.LoadInt32(&g.closed) == 1 {
+		http.Error(w, lru.ErrClosed.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	prefix := DefaultBasePath + g.name + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return
+	}
+	keyData, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(r.URL.Path, prefix))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	key, err := g.keyCodec.Unmarshal(keyData)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	atomic.AddInt64(&g.gets, 1)
+	value, err := g.flight.do(key, func() (V, error) {
+		if value, ok := g.cache.Get(key); ok {
+			atomic.AddInt64(&g.cacheHits, 1)
+			return value, nil
+		}
+		return g.load(r.Context(), key)
+	})
+	if errors.Is(err, lru.ErrNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	data, err := g.valueCodec.Marshal(value)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	_, _ = w.Write(data)
+}
+
+// Close stops the group, Get returns lru.ErrClosed and peers
+// fetching from it fall back to loading locally.
+func (g *Group[K, V]) Close() error {
+	atomic.StoreInt32(&g.closed, 1)
+	return nil
+}
+
+// Stats returns the counters of the group, the latency summary of the
+// recent Getter calls, and the time saved by the cache hits.
+func (g *Group[K, V]) Stats() Stats {
+	stats := Stats{
+		Gets:        atomic.LoadInt64(&g.gets),
+		CacheHits:   atomic.LoadInt64(&g.cacheHits),
+		PeerLoads:   atomic.LoadInt64(&g.peerLoads),
+		PeerErrors:  atomic.LoadInt64(&g.peerErrors),
+		Loads:       atomic.LoadInt64(&g.loads),
+		LoadErrors:  atomic.LoadInt64(&g.loadErrors),
+		LoadLatency: g.latencies.summary(),
+	}
+	penalty := time.Duration(atomic.LoadInt64(&g.missPenalty))
+	if penalty == 0 {
+		penalty = stats.LoadLatency.P50
+	}
+	stats.TimeSaved = time.Duration(stats.CacheHits) * penalty
+	return stats
+}
+
+// load fills the local cache with the Getter.
+func (g *Group[K, V]) load(ctx context.Context, key K) (value V, err error) {
+	atomic.AddInt64(&g.loads, 1)
+	start := time.Now()
+	value, err = g.getter(ctx, key)
+	g.latencies.record(time.Since(start))
+	if err != nil {
+		atomic.AddInt64(&g.loadErrors, 1)
+		return value, &lru.LoadError[K]{Key: key, Err: err}
+	}
+	g.cache.Add(key, value)
+	return value, nil
+}
+
+// fetch asks the owner peer for the value of the key.
+func (g *Group[K, V]) fetch(ctx context.Context, owner string, keyData []byte) (value V, err error) {
+	u := strings.TrimSuffix(owner, "/") + DefaultBasePath + g.name + "/" + base64.RawURLEncoding.EncodeToString(keyData)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return value, err
+	}
+	resp, err := g.Client.Do(req)
+	if err != nil {
+		return value, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return value, fmt.Errorf("peer %s: %w", owner, lru.ErrNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return value, fmt.Errorf("peer %s returned %s", owner, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return value, err
+	}
+	return g.valueCodec.Unmarshal(data)
+}
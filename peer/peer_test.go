@@ -0,0 +1,121 @@
+package peer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/electricbubble/lru"
+)
+
+func TestGroup_Get(t *testing.T) {
+	var (
+		loads  [2]int64
+		groups [2]*Group[string, int]
+		urls   []string
+	)
+	for i := range groups {
+		i := i
+		groups[i] = NewGroup[string, int]("test", 128, func(ctx context.Context, key string) (int, error) {
+			atomic.AddInt64(&loads[i], 1)
+			return len(key), nil
+		}, StringCodec{}, JSONCodec[int]{})
+		srv := httptest.NewServer(groups[i])
+		defer srv.Close()
+		urls = append(urls, srv.URL)
+	}
+	for i, g := range groups {
+		g.SetPeers(urls[i], urls...)
+	}
+
+	ctx := context.Background()
+	for n := 0; n < 2; n++ {
+		for _, g := range groups {
+			for i := 0; i < 20; i++ {
+				key := fmt.Sprintf("key-%0*d", i, i)
+				v, err := g.Get(ctx, key)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if v != len(key) {
+					t.Fatalf("Expected %v, got %v", len(key), v)
+				}
+			}
+		}
+	}
+
+	// Every key is loaded once, by its owner
+	if total := loads[0] + loads[1]; total != 20 {
+		t.Fatalf("Expected %v, got %v", 20, total)
+	}
+	if loads[0] == 0 || loads[1] == 0 {
+		t.Fatalf("keys not spread across peers: %v", loads)
+	}
+}
+
+func TestGroup_Errors(t *testing.T) {
+	var (
+		groups [2]*Group[string, int]
+		urls   []string
+	)
+	for i := range groups {
+		groups[i] = NewGroup[string, int]("test", 128, func(ctx context.Context, key string) (int, error) {
+			return 0, fmt.Errorf("db: %w", lru.ErrNotFound)
+		}, StringCodec{}, JSONCodec[int]{})
+		srv := httptest.NewServer(groups[i])
+		defer srv.Close()
+		urls = append(urls, srv.URL)
+	}
+	for i, g := range groups {
+		g.SetPeers(urls[i], urls...)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		_, err := groups[0].Get(ctx, fmt.Sprint(i))
+		if !errors.Is(err, lru.ErrNotFound) {
+			t.Fatalf("Expected %v, got %v", lru.ErrNotFound, err)
+		}
+		var loadErr *lru.LoadError[string]
+		if !errors.As(err, &loadErr) || loadErr.Key != fmt.Sprint(i) {
+			t.Fatalf("Expected *lru.LoadError, got %v", err)
+		}
+	}
+
+	_ = groups[0].Close()
+	if _, err := groups[0].Get(ctx, "k"); !errors.Is(err, lru.ErrClosed) {
+		t.Fatalf("Expected %v, got %v", lru.ErrClosed, err)
+	}
+}
+
+func TestFlight_Panic(t *testing.T) {
+	var f flight[string, int]
+	started, release := make(chan struct{}), make(chan struct{})
+	errs, waited := make(chan error, 1), make(chan error, 1)
+	go func() {
+		_, err := f.do("k", func() (int, error) {
+			close(started)
+			<-release
+			panic("boom")
+		})
+		errs <- err
+	}()
+	<-started
+	go func() {
+		_, err := f.do("k", func() (int, error) { return 1, nil })
+		waited <- err
+	}()
+	close(release)
+
+	// The waiters are released, the leader gets the panic as an error
+	if err := <-errs; err == nil {
+		t.Fatal("should fail")
+	}
+	<-waited
+	if v, err := f.do("k", func() (int, error) { return 2, nil }); err != nil || v != 2 {
+		t.Fatalf("Expected %v, got %v, %v", 2, v, err)
+	}
+}
@@ -0,0 +1,57 @@
+package peer
+
+import (
+	"fmt"
+	"sync"
+)
+
+// call is an in-flight or completed flight.do call
+type call[V any] struct {
+	wg    sync.WaitGroup
+	value V
+	err   error
+}
+
+// flight suppresses duplicate loads of the same key.
+type flight[K comparable, V any] struct {
+	mu    sync.Mutex
+	calls map[K]*call[V]
+}
+
+// do executes fn once for concurrent callers with the same key,
+// every caller receives the same result. A panic of fn is returned
+// to every caller as an error.
+func (f *flight[K, V]) do(key K, fn func() (V, error)) (V, error) {
+	f.mu.Lock()
+	if f.calls == nil {
+		f.calls = make(map[K]*call[V])
+	}
+	if c, ok := f.calls[key]; ok {
+		f.mu.Unlock()
+		c.wg.Wait()
+		return c.value, c.err
+	}
+	c := new(call[V])
+	c.wg.Add(1)
+	f.calls[key] = c
+	f.mu.Unlock()
+
+	f.call(c, key, fn)
+	return c.value, c.err
+}
+
+// call executes fn for c, releasing its waiters even if fn panics.
+func (f *flight[K, V]) call(c *call[V], key K, fn func() (V, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.err = fmt.Errorf("peer: loader panicked: %v", r)
+		}
+		c.wg.Done()
+
+		f.mu.Lock()
+		delete(f.calls, key)
+		f.mu.Unlock()
+	}()
+
+	c.value, c.err = fn()
+}
@@ -0,0 +1,63 @@
+package peer
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencySamples is the number of recent loads the latency summary is computed from.
+const latencySamples = 1024
+
+// Stats are the counters of a Group.
+type Stats struct {
+	Gets        int64 // Any Get request, including from peers
+	CacheHits   int64 // Either cache was good
+	PeerLoads   int64 // Either remote load or remote cache hit
+	PeerErrors  int64 // Failed fetches from the owner peer
+	Loads       int64 // Calls of the Getter
+	LoadErrors  int64 // Failed calls of the Getter
+	LoadLatency LatencySummary
+	TimeSaved   time.Duration // Estimated, CacheHits times the miss penalty
+}
+
+// LatencySummary are percentiles of the latency of the recent Getter calls.
+type LatencySummary struct {
+	P50, P95, P99 time.Duration
+}
+
+// latencies keeps the latency of the last latencySamples loads.
+type latencies struct {
+	samples []time.Duration
+	next    int
+
+	mu sync.Mutex
+}
+
+func (l *latencies) record(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.samples) < latencySamples {
+		l.samples = append(l.samples, d)
+		return
+	}
+	l.samples[l.next] = d
+	l.next = (l.next + 1) % latencySamples
+}
+
+func (l *latencies) summary() (s LatencySummary) {
+	l.mu.Lock()
+	sorted := append([]time.Duration(nil), l.samples...)
+	l.mu.Unlock()
+
+	if len(sorted) == 0 {
+		return s
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	// Nearest-rank percentile
+	percentile := func(p int) time.Duration {
+		return sorted[(len(sorted)*p+99)/100-1]
+	}
+	return LatencySummary{P50: percentile(50), P95: percentile(95), P99: percentile(99)}
+}
@@ -0,0 +1,76 @@
+package peer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/electricbubble/lru"
+)
+
+func TestGroup_Stats(t *testing.T) {
+	g := NewGroup[string, int]("stats", 128, func(ctx context.Context, key string) (int, error) {
+		if key == "missing" {
+			return 0, lru.ErrNotFound
+		}
+		time.Sleep(time.Millisecond)
+		return len(key), nil
+	}, StringCodec{}, JSONCodec[int]{})
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if _, err := g.Get(ctx, "a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := g.Get(ctx, "missing"); !errors.Is(err, lru.ErrNotFound) {
+		t.Fatalf("Expected %v, got %v", lru.ErrNotFound, err)
+	}
+
+	stats := g.Stats()
+	if stats.Gets != 4 || stats.CacheHits != 2 || stats.Loads != 2 || stats.LoadErrors != 1 {
+		t.Fatalf("bad stats: %+v", stats)
+	}
+	if stats.LoadLatency.P99 < time.Millisecond || stats.LoadLatency.P50 > stats.LoadLatency.P99 {
+		t.Fatalf("bad latency: %+v", stats.LoadLatency)
+	}
+}
+
+func Test_latencies(t *testing.T) {
+	var l latencies
+	if s := l.summary(); s != (LatencySummary{}) {
+		t.Fatalf("Expected zero summary, got %+v", s)
+	}
+	for i := 1; i <= latencySamples+100; i++ {
+		l.record(time.Duration(i))
+	}
+	// Only the last latencySamples are kept
+	s := l.summary()
+	if s.P50 != 100+latencySamples/2 || s.P99 < s.P95 || s.P95 < s.P50 {
+		t.Fatalf("bad summary: %+v", s)
+	}
+}
+
+func TestGroup_MissPenalty(t *testing.T) {
+	g := NewGroup[string, int]("penalty", 128, func(ctx context.Context, key string) (int, error) {
+		return len(key), nil
+	}, StringCodec{}, JSONCodec[int]{})
+	g.SetMissPenalty(10 * time.Millisecond)
+
+	ctx := context.Background()
+	for i := 0; i < 4; i++ {
+		if _, err := g.Get(ctx, "a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if stats := g.Stats(); stats.TimeSaved != 30*time.Millisecond {
+		t.Fatalf("Expected %v, got %v", 30*time.Millisecond, stats.TimeSaved)
+	}
+
+	// Defaults to the median load latency
+	g.SetMissPenalty(0)
+	if stats := g.Stats(); stats.TimeSaved != 3*stats.LoadLatency.P50 {
+		t.Fatalf("Expected %v, got %v", 3*stats.LoadLatency.P50, stats.TimeSaved)
+	}
+}
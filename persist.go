@@ -0,0 +1,128 @@
+package lru
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// WriteSnapshot writes the entries of the cache to w using encoding/gob,
+// from oldest to newest, so K and V must be encodable by gob.
+func WriteSnapshot[K comparable, V any](w io.Writer, c Lru[K, V]) error {
+	if err := gob.NewEncoder(w).Encode(c.Items()); err != nil {
+		return fmt.Errorf("lru: write snapshot: %w", err)
+	}
+	return nil
+}
+
+// ReadSnapshot adds the entries written by WriteSnapshot to the cache,
+// keeping their recency order and expirations, and returns the number of
+// entries added. The entries expired since the snapshot are skipped.
+// An idle deadline of WithTTI is restored as a fixed time to live.
+func ReadSnapshot[K comparable, V any](r io.Reader, c Lru[K, V]) (n int, err error) {
+	var items []Entry[K, V]
+	if err = gob.NewDecoder(r).Decode(&items); err != nil {
+		return 0, fmt.Errorf("lru: read snapshot: %w", err)
+	}
+	now := time.Now()
+	for _, e := range items {
+		if restore(c, e, now) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// PersistConfig sets when a Persister takes snapshots.
+type PersistConfig struct {
+	// Interval takes a snapshot periodically, if the cache changed.
+	// Zero disables it.
+	Interval time.Duration
+
+	// Changes takes a snapshot after this many adds, updates and removals,
+	// so that a rapidly churning cache does not lose much of its warm state
+	// between intervals. Zero disables it.
+	Changes int
+}
+
+// NewPersister creates a Persister writing the snapshots of the cache to
+// the writers returned by open, which are closed after each snapshot.
+func NewPersister[K comparable, V any](cache Lru[K, V], cfg PersistConfig, open func() (io.WriteCloser, error)) *Persister[K, V] {
+	return &Persister[K, V]{cache: cache, cfg: cfg, open: open}
+}
+
+// Persister snapshots a cache in the background, see PersistConfig.
+type Persister[K comparable, V any] struct {
+	cache Lru[K, V]
+	cfg   PersistConfig
+	open  func() (io.WriteCloser, error)
+}
+
+// Snapshot writes a snapshot of the cache now.
+func (p *Persister[K, V]) Snapshot() (err error) {
+	w, err := p.open()
+	if err != nil {
+		return fmt.Errorf("lru: persist: %w", err)
+	}
+	err = WriteSnapshot(w, p.cache)
+	if cerr := w.Close(); err == nil && cerr != nil {
+		err = fmt.Errorf("lru: persist: %w", cerr)
+	}
+	return err
+}
+
+// Run takes snapshots until the context is done, and then a last one if
+// the cache changed. The changes are counted from the events of the cache,
+// including the dropped ones. It returns the first error of a snapshot.
+func (p *Persister[K, V]) Run(ctx context.Context) error {
+	events, cancel := p.cache.Subscribe()
+	defer cancel()
+
+	var tick <-chan time.Time
+	if p.cfg.Interval > 0 {
+		ticker := time.NewTicker(p.cfg.Interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	changes := 0
+	dropped := p.cache.DroppedEvents()
+	snapshot := func() error {
+		changes = 0
+		dropped = p.cache.DroppedEvents()
+		return p.Snapshot()
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			if changes == 0 && p.cache.DroppedEvents() == dropped {
+				return nil
+			}
+			return snapshot()
+		case <-tick:
+			if changes == 0 && p.cache.DroppedEvents() == dropped {
+				continue
+			}
+			if err := snapshot(); err != nil {
+				return err
+			}
+		case ev, ok := <-events:
+			if !ok {
+				return errors.New("lru: persist: subscription cancelled")
+			}
+			switch ev.Kind {
+			case EventHit, EventMiss:
+				continue
+			}
+			changes++
+			if p.cfg.Changes > 0 && changes+int(p.cache.DroppedEvents()-dropped) >= p.cfg.Changes {
+				if err := snapshot(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package lru
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSnapshot(t *testing.T) {
+	l := New[string, int](128)
+	l.Add("a", 1)
+	l.AddWithTTL("b", 2, time.Hour)
+	l.AddWithTTL("c", 3, time.Nanosecond)
+	l.Add("d", 4)
+	time.Sleep(time.Millisecond)
+
+	var buf bytes.Buffer
+	if err := WriteSnapshot[string, int](&buf, l); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := New[string, int](128)
+	n, err := ReadSnapshot[string, int](&buf, restored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 || !reflect.DeepEqual(restored.Keys(), []string{"a", "b", "d"}) {
+		t.Fatalf("Expected %v, got %v", []string{"a", "b", "d"}, restored.Keys())
+	}
+	if e, _ := restored.PeekEntry("b"); e.ExpiresAt.IsZero() {
+		t.Fatal("expiry not restored")
+	}
+
+	if _, err = ReadSnapshot[string, int](bytes.NewBufferString("garbage"), restored); err == nil {
+		t.Fatal("should fail")
+	}
+}
+
+// snapshotSink collects the snapshots of a Persister.
+type snapshotSink struct {
+	mu        sync.Mutex
+	snapshots []*bytes.Buffer
+}
+
+func (s *snapshotSink) open() (io.WriteCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	buf := new(bytes.Buffer)
+	s.snapshots = append(s.snapshots, buf)
+	return nopCloser{buf}, nil
+}
+
+func (s *snapshotSink) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.snapshots)
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+func TestPersister_Changes(t *testing.T) {
+	l := New[int, int](128)
+	sink := new(snapshotSink)
+	p := NewPersister[int, int](l, PersistConfig{Changes: 10}, sink.open)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- p.Run(ctx) }()
+	time.Sleep(10 * time.Millisecond)
+
+	for i := 0; i < 25; i++ {
+		l.Add(i, i)
+		l.Get(i) // Hits are not changes
+	}
+	time.Sleep(10 * time.Millisecond)
+	if sink.len() != 2 {
+		t.Fatalf("Expected %v, got %v", 2, sink.len())
+	}
+
+	// The remaining changes are persisted on shutdown
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if sink.len() != 3 {
+		t.Fatalf("Expected %v, got %v", 3, sink.len())
+	}
+	restored := New[int, int](128)
+	if n, _ := ReadSnapshot[int, int](sink.snapshots[2], restored); n != 25 {
+		t.Fatalf("Expected %v, got %v", 25, n)
+	}
+}
+
+func TestPersister_Interval(t *testing.T) {
+	l := New[int, int](128)
+	sink := new(snapshotSink)
+	p := NewPersister[int, int](l, PersistConfig{Interval: 5 * time.Millisecond}, sink.open)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		l.Add(1, 1)
+	}()
+	if err := p.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// One snapshot for the single change, none while unchanged
+	if sink.len() != 1 {
+		t.Fatalf("Expected %v, got %v", 1, sink.len())
+	}
+}
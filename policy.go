@@ -0,0 +1,145 @@
+package lru
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrUnknownPolicy is returned by Rebuild for an unknown PolicyKind.
+var ErrUnknownPolicy = errors.New("lru: unknown policy")
+
+// Policy chooses the entries a cache evicts, e.g. to take business priorities
+// into account. The cache informs its policy of the keys it inserts, accesses
+// and removes, and asks it for a victim when it has to evict. Whatever the
+// policy, Keys and Items remain in recency order.
+type Policy[K comparable] interface {
+	// Insert is called when a new key is added.
+	Insert(key K)
+
+	// Access is called when a key is hit by Get or updated by Add.
+	Access(key K)
+
+	// Remove is called when a key leaves the cache, including when it
+	// is evicted.
+	Remove(key K)
+
+	// Victim returns the key to evict next, without removing it. The
+	// cache evicts its least recently used entry when ok is false.
+	Victim() (key K, ok bool)
+
+	// Clear is called when the cache is cleared.
+	Clear()
+}
+
+// VictimsPolicy is implemented by the policies able to rank the keys after
+// their victim. WithEvictionFilter asks them for the next candidate when
+// the filter vetoes one, instead of walking the recency order.
+type VictimsPolicy[K comparable] interface {
+	Policy[K]
+
+	// NextVictim is like Victim, skipping the keys for which skip
+	// returns true.
+	NextVictim(skip func(key K) bool) (key K, ok bool)
+}
+
+// WithPolicy replaces the LRU eviction with the policy returned by newPolicy,
+// which is called once per cache so that caches do not share a policy.
+// RemoveOldest and GetOldest return the victim of the policy.
+func WithPolicy[K comparable, V any](newPolicy func() Policy[K]) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		c.policy = newPolicy()
+	}
+}
+
+// NewLRUPolicy returns a policy evicting the least recently used key,
+// the default of the caches. The caches keep their entries in recency
+// order already, so the policy holds no keys and defers to them.
+func NewLRUPolicy[K comparable]() Policy[K] {
+	return lruPolicy[K]{}
+}
+
+// lruPolicy lets the cache evict the back of its recency list.
+type lruPolicy[K comparable] struct{}
+
+func (lruPolicy[K]) Insert(K) {}
+func (lruPolicy[K]) Access(K) {}
+func (lruPolicy[K]) Remove(K) {}
+func (lruPolicy[K]) Clear()   {}
+
+func (lruPolicy[K]) Victim() (key K, ok bool) {
+	return key, false
+}
+
+// NewFIFOPolicy returns a policy evicting the first inserted key,
+// regardless of the accesses.
+func NewFIFOPolicy[K comparable]() Policy[K] {
+	return &setPolicy[K]{keys: newKeySet[K](math.MaxInt)}
+}
+
+// setPolicy orders the keys in a keySet by insertion.
+type setPolicy[K comparable] struct {
+	keys *keySet[K]
+}
+
+func (p *setPolicy[K]) Insert(key K) {
+	p.keys.Add(key)
+}
+
+func (p *setPolicy[K]) Access(K) {}
+
+func (p *setPolicy[K]) Remove(key K) {
+	p.keys.Remove(key)
+}
+
+func (p *setPolicy[K]) Victim() (key K, ok bool) {
+	return p.keys.Oldest()
+}
+
+func (p *setPolicy[K]) NextVictim(skip func(key K) bool) (key K, ok bool) {
+	return p.keys.oldestExcept(skip)
+}
+
+func (p *setPolicy[K]) Clear() {
+	p.keys.Clear()
+}
+
+// PolicyKind names the built-in eviction policies, for Rebuild.
+type PolicyKind uint8
+
+const (
+	// PolicyLRU evicts the least recently used key, like NewLRUPolicy.
+	PolicyLRU PolicyKind = iota
+	// PolicyFIFO evicts the first inserted key, like NewFIFOPolicy.
+	PolicyFIFO
+	// PolicyARC evicts like an ARCCache of the size of the cache,
+	// like NewARCPolicy.
+	PolicyARC
+	// Policy2Q evicts like a TwoQueueCache of the size of the cache,
+	// like New2QPolicy.
+	Policy2Q
+)
+
+func (c *unsafeCache[K, V]) Rebuild(kind PolicyKind) error {
+	var policy Policy[K]
+	switch kind {
+	case PolicyLRU:
+		policy = NewLRUPolicy[K]()
+	case PolicyFIFO:
+		policy = NewFIFOPolicy[K]()
+	case PolicyARC:
+		policy = NewARCPolicy[K](c.maxEntries)
+	case Policy2Q:
+		policy = New2QPolicy[K](c.maxEntries)
+	default:
+		return fmt.Errorf("lru: rebuild %d: %w", kind, ErrUnknownPolicy)
+	}
+	// The insertion order is unknown, the recency order stands for it
+	for elem := c.entries.Back(); elem != nil; elem = elem.Prev() {
+		policy.Insert(elem.Value.key)
+	}
+	c.policy = policy
+	// The built-in policies replace the decayed eviction
+	c.decayHalfLife, c.decayCandidates = 0, 0
+	return nil
+}
@@ -0,0 +1,194 @@
+package lru
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLru_WithPolicy(t *testing.T) {
+	l := New[int, int](2, WithPolicy[int, int](NewFIFOPolicy[int]))
+	l.Add(1, 1)
+	l.Add(2, 2)
+	l.Get(1)
+	l.Add(3, 3)
+
+	// FIFO evicts 1 despite the access
+	if l.Contains(1) || !l.Contains(2) {
+		t.Fatalf("Expected %v, got %v", []int{2, 3}, l.Keys())
+	}
+	if k, _, ok := l.GetOldest(); !ok || k != 2 {
+		t.Fatalf("Expected %v, got %v", 2, k)
+	}
+
+	l.Clear()
+	l.Add(4, 4)
+	if k, _, ok := l.RemoveOldest(); !ok || k != 4 {
+		t.Fatalf("Expected %v, got %v", 4, k)
+	}
+}
+
+func TestLru_WithLRUPolicy(t *testing.T) {
+	l := New[int, int](2, WithPolicy[int, int](NewLRUPolicy[int]))
+	l.Add(1, 1)
+	l.Add(2, 2)
+	l.Get(1)
+	l.Add(3, 3)
+	if !reflect.DeepEqual(l.Keys(), []int{1, 3}) {
+		t.Fatalf("Expected %v, got %v", []int{1, 3}, l.Keys())
+	}
+	l.Remove(1)
+	l.Add(4, 4)
+	l.Add(5, 5)
+	if !reflect.DeepEqual(l.Keys(), []int{4, 5}) {
+		t.Fatalf("Expected %v, got %v", []int{4, 5}, l.Keys())
+	}
+}
+
+// priorityPolicy evicts the keys with the lowest priority first,
+// the most recently inserted among them.
+type priorityPolicy struct {
+	keys map[string]struct{}
+}
+
+func (p *priorityPolicy) Insert(key string) { p.keys[key] = struct{}{} }
+func (p *priorityPolicy) Access(string)     {}
+func (p *priorityPolicy) Remove(key string) { delete(p.keys, key) }
+func (p *priorityPolicy) Clear()            { p.keys = make(map[string]struct{}) }
+
+func (p *priorityPolicy) Victim() (key string, ok bool) {
+	for k := range p.keys {
+		if !ok || k[0] < key[0] {
+			key, ok = k, true
+		}
+	}
+	return key, ok
+}
+
+func TestLru_CustomPolicy(t *testing.T) {
+	l := NewUnsafeLru[string, int](2, WithPolicy[string, int](func() Policy[string] {
+		return &priorityPolicy{keys: make(map[string]struct{})}
+	}))
+	l.Add("9-vip", 1)
+	l.Add("1-bulk", 2)
+	l.Add("5-user", 3)
+	if !l.Contains("9-vip") || l.Contains("1-bulk") {
+		t.Fatalf("bad keys: %v", l.Keys())
+	}
+	l.Add("7-user", 4)
+	if !l.Contains("9-vip") || l.Contains("5-user") {
+		t.Fatalf("bad keys: %v", l.Keys())
+	}
+}
+
+func TestLru_Rebuild(t *testing.T) {
+	l := New[int, int](3)
+	l.Add(1, 1)
+	l.Add(2, 2)
+	l.Add(3, 3)
+	l.Get(1)
+
+	if err := l.Rebuild(PolicyFIFO); err != nil {
+		t.Fatalf("Expected %v, got %v", nil, err)
+	}
+	if !reflect.DeepEqual(l.Keys(), []int{2, 3, 1}) {
+		t.Fatalf("Expected %v, got %v", []int{2, 3, 1}, l.Keys())
+	}
+
+	// The entries are migrated in recency order, then the accesses are ignored
+	l.Get(2)
+	l.Add(4, 4)
+	if l.Contains(2) || !l.Contains(1) || !l.Contains(3) {
+		t.Fatalf("Expected %v, got %v", []int{3, 1, 4}, l.Keys())
+	}
+
+	if err := l.Rebuild(PolicyLRU); err != nil {
+		t.Fatalf("Expected %v, got %v", nil, err)
+	}
+	l.Get(3)
+	l.Add(5, 5)
+	if l.Contains(1) || !l.Contains(3) {
+		t.Fatalf("Expected %v, got %v", []int{4, 3, 5}, l.Keys())
+	}
+
+	if err := l.Rebuild(PolicyKind(42)); !errors.Is(err, ErrUnknownPolicy) {
+		t.Fatalf("Expected %v, got %v", ErrUnknownPolicy, err)
+	}
+	if l.Len() != 3 {
+		t.Fatalf("Expected %v, got %v", 3, l.Len())
+	}
+}
+
+func TestLru_RebuildARC(t *testing.T) {
+	l := New[int, int](4, WithDecayedEviction[int, int](time.Minute, 4))
+	for i := 1; i <= 4; i++ {
+		l.Add(i, i)
+	}
+	l.Get(1)
+
+	if err := l.Rebuild(PolicyARC); err != nil {
+		t.Fatalf("Expected %v, got %v", nil, err)
+	}
+	if l.Len() != 4 {
+		t.Fatalf("Expected %v, got %v", 4, l.Len())
+	}
+	if u := l.lru.(*unsafeCache[int, int]); u.decayHalfLife != 0 {
+		t.Fatalf("Expected %v, got %v", 0, u.decayHalfLife)
+	}
+
+	// The keys accessed several times survive a scan
+	l.Get(3)
+	l.Get(4)
+	for i := 10; i < 20; i++ {
+		l.Add(i, i)
+	}
+	if !l.Contains(3) || !l.Contains(4) || l.Contains(1) {
+		t.Fatalf("Expected 3 and 4 to survive, got %v", l.Keys())
+	}
+
+	// A ghost hit grows the target of the recent keys
+	arc := l.lru.(*unsafeCache[int, int]).policy.(*arcPolicy[int])
+	if !arc.b1.Contains(17) || arc.p != 0 {
+		t.Fatalf("Expected 17 in B1, got %v, %v", arc.b1.Keys(), arc.p)
+	}
+	l.Add(17, 17)
+	if arc.p != 1 || !arc.pending.frequent {
+		t.Fatalf("Expected %v, got %v", 1, arc.p)
+	}
+
+	if err := l.Rebuild(PolicyLRU); err != nil {
+		t.Fatalf("Expected %v, got %v", nil, err)
+	}
+	keys := l.Keys()
+	l.Add(30, 30)
+	if l.Contains(keys[0]) {
+		t.Fatalf("Expected %v evicted, got %v", keys[0], l.Keys())
+	}
+}
+
+func TestLru_Rebuild2Q(t *testing.T) {
+	l := New[int, int](4)
+	for i := 1; i <= 4; i++ {
+		l.Add(i, i)
+	}
+	if err := l.Rebuild(Policy2Q); err != nil {
+		t.Fatalf("Expected %v, got %v", nil, err)
+	}
+	l.Get(1)
+	for i := 10; i < 20; i++ {
+		l.Add(i, i)
+	}
+	if !l.Contains(1) || l.Len() != 4 {
+		t.Fatalf("Expected 1 to survive, got %v", l.Keys())
+	}
+}
+
+func TestNewLRUPolicy(t *testing.T) {
+	// The policy defers to the recency list of the cache
+	p := NewLRUPolicy[int]()
+	p.Insert(1)
+	if _, ok := p.Victim(); ok {
+		t.Fatal("the LRU policy should not track keys")
+	}
+}
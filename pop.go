@@ -0,0 +1,35 @@
+package lru
+
+import "github.com/electricbubble/lru/list"
+
+func (c *unsafeCache[K, V]) PopOldestIf(fn func(key K, value V) bool) (key K, value V, ok bool) {
+	for elem := c.entries.Back(); elem != nil; elem = elem.Prev() {
+		if key, value, ok = c.popIf(elem, fn); ok {
+			return key, value, true
+		}
+	}
+	return key, value, false
+}
+
+func (c *unsafeCache[K, V]) PopNewestIf(fn func(key K, value V) bool) (key K, value V, ok bool) {
+	for elem := c.entries.Front(); elem != nil; elem = elem.Next() {
+		if key, value, ok = c.popIf(elem, fn); ok {
+			return key, value, true
+		}
+	}
+	return key, value, false
+}
+
+// popIf removes the entry if it is not expired and matches fn.
+func (c *unsafeCache[K, V]) popIf(elem *list.Element[*entry[K, V]], fn func(key K, value V) bool) (key K, value V, ok bool) {
+	ent := elem.Value
+	if c.expired(ent) {
+		return key, value, false
+	}
+	loaded := c.loadValue(ent.value)
+	if fn != nil && !fn(ent.key, loaded) {
+		return key, value, false
+	}
+	c.removeElement(elem, EventRemove)
+	return ent.key, loaded, true
+}
@@ -0,0 +1,71 @@
+package lru
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_unsafeCache_PopOldestIf(t *testing.T) {
+	c, clock := newTTLCache(time.Minute)
+	c.AddWithTTL("a", 1, time.Second)
+	c.Add("b", 2)
+	c.Add("c", 3)
+	c.Add("d", 4)
+	clock.advance(time.Second)
+
+	even := func(k string, v int) bool { return v%2 == 0 }
+	if k, v, ok := c.PopOldestIf(even); !ok || k != "b" || v != 2 {
+		t.Fatalf("Expected %v, %v, got %v, %v", "b", 2, k, v)
+	}
+	if k, v, ok := c.PopNewestIf(even); !ok || k != "d" || v != 4 {
+		t.Fatalf("Expected %v, %v, got %v, %v", "d", 4, k, v)
+	}
+	if _, _, ok := c.PopOldestIf(even); ok {
+		t.Fatal("no entry should match")
+	}
+
+	// The expired entry is skipped
+	if k, v, ok := c.PopOldestIf(nil); !ok || k != "c" || v != 3 {
+		t.Fatalf("Expected %v, %v, got %v, %v", "c", 3, k, v)
+	}
+	if _, _, ok := c.PopNewestIf(nil); ok {
+		t.Fatal("no entry should be left")
+	}
+}
+
+func TestLru_PopOldestIf(t *testing.T) {
+	l := New[int, int](1000)
+	for i := 0; i < 1000; i++ {
+		l.Add(i, i)
+	}
+
+	// Every entry is claimed exactly once
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		claimed = make(map[int]bool)
+	)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				k, _, ok := l.PopOldestIf(nil)
+				if !ok {
+					return
+				}
+				mu.Lock()
+				if claimed[k] {
+					t.Errorf("%v claimed twice", k)
+				}
+				claimed[k] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(claimed) != 1000 || l.Len() != 0 {
+		t.Fatalf("Expected %v, got %v", 1000, len(claimed))
+	}
+}
@@ -0,0 +1,161 @@
+package lru
+
+import "time"
+
+// PromotionTTL decides what happens to the time to live of the entries
+// moving between the internal lists of the ARC and 2Q caches.
+type PromotionTTL uint8
+
+const (
+	// ResetTTL restarts the time to live of the entries promoted to the
+	// frequent list by a Get, and of the keys re-admitted from a ghost list.
+	ResetTTL PromotionTTL = iota
+	// PreserveTTL keeps the deadline of the entries promoted by a Get, and
+	// of the keys re-admitted from a ghost list before their former
+	// deadline, so that frequently used entries do not outlive their
+	// original time to live. Re-admissions after the deadline are fresh.
+	PreserveTTL
+)
+
+// WithPromotionTTL sets the time to live of the entries moving between the
+// internal lists of the ARC and 2Q caches, ResetTTL by default. Updating an
+// entry with Add always restarts its time to live. It has no effect on the
+// other caches.
+func WithPromotionTTL[K comparable, V any](mode PromotionTTL) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		c.promotionTTL = mode
+	}
+}
+
+// ghostDeadlinesSlack is the number of stale deadlines tolerated per
+// ghost key before they are swept.
+const ghostDeadlinesSlack = 2
+
+// promoter moves the entries between the lists of an ARC or 2Q cache,
+// remembering the deadlines of the ghost keys WithPromotionTTL(PreserveTTL).
+type promoter[K comparable, V any] struct {
+	// clock is an internal list, providing the mode and the clock
+	clock     *unsafeCache[K, V]
+	deadlines map[K]time.Time
+}
+
+func newPromoter[K comparable, V any](clock Lru[K, V]) promoter[K, V] {
+	return promoter[K, V]{
+		clock:     clock.(*unsafeCache[K, V]),
+		deadlines: make(map[K]time.Time),
+	}
+}
+
+func (p *promoter[K, V]) preserve() bool {
+	return p.clock.promotionTTL == PreserveTTL
+}
+
+// promote moves an entry from a list to another after a Get, in its
+// stored form. Being read, it restarts its time to idle, and keeps the
+// deadline of its time to live WithPromotionTTL(PreserveTTL).
+func (p *promoter[K, V]) promote(from, to Lru[K, V], key K) (value V, ok bool) {
+	src, dst := from.(*unsafeCache[K, V]), to.(*unsafeCache[K, V])
+	stored, deadline, ok := src.take(key)
+	if !ok {
+		return value, false
+	}
+	ttl := dst.ttl
+	if p.preserve() {
+		ttl = NoExpiration
+		if !deadline.IsZero() {
+			// The entry was fresh, it expires at the next lookup at worst
+			if ttl = deadline.Sub(p.clock.now()); ttl <= 0 {
+				ttl = time.Nanosecond
+			}
+		}
+	}
+	dst.addStored(key, stored, ttl)
+	return src.loadValue(stored), true
+}
+
+// take removes a fresh entry, returning its stored value and the deadline
+// of its time to live, excluding the time to idle.
+func (c *unsafeCache[K, V]) take(key K) (value V, deadline time.Time, ok bool) {
+	key = c.normalize(key)
+	elem, ok := c.bucket.get(key)
+	if !ok || c.expired(elem.Value) {
+		return value, deadline, false
+	}
+	value, deadline = elem.Value.value, elem.Value.writeExpiresAt
+	c.removeElement(elem, eventMove)
+	return value, deadline, true
+}
+
+// move removes the entry of a key updated into another list.
+func (p *promoter[K, V]) move(from Lru[K, V], key K) {
+	src := from.(*unsafeCache[K, V])
+	if elem, ok := src.bucket.get(src.normalize(key)); ok {
+		src.removeElement(elem, eventMove)
+	}
+}
+
+// retire evicts the oldest entry of a list to a ghost list.
+func (p *promoter[K, V]) retire(from Lru[K, V], ghosts ...*keySet[K]) (key K, ok bool) {
+	var deadline time.Time
+	if p.preserve() {
+		src := from.(*unsafeCache[K, V])
+		if elem := src.victim(); elem != nil && !src.expired(elem.Value) {
+			deadline = elem.Value.writeExpiresAt
+		}
+	}
+	if key, _, ok = from.RemoveOldest(); !ok {
+		return key, false
+	}
+	ghosts[0].Add(key)
+	if deadline.IsZero() {
+		return key, true
+	}
+
+	p.deadlines[key] = deadline
+	// Forget the deadlines of the keys dropped by the ghost lists
+	limit := 0
+	for _, g := range ghosts {
+		limit += g.maxEntries
+	}
+	if len(p.deadlines) > ghostDeadlinesSlack*limit {
+		for k := range p.deadlines {
+			if !containsAny(ghosts, k) {
+				delete(p.deadlines, k)
+			}
+		}
+	}
+	return key, true
+}
+
+// readmit adds a key recently evicted to a list, it must have been
+// removed from its ghost list.
+func (p *promoter[K, V]) readmit(to Lru[K, V], key K, value V) {
+	deadline, ok := p.deadlines[key]
+	delete(p.deadlines, key)
+	if ok && p.preserve() {
+		if ttl := deadline.Sub(p.clock.now()); ttl > 0 {
+			to.AddWithTTL(key, value, ttl)
+			return
+		}
+	}
+	to.Add(key, value)
+}
+
+// forget drops the deadline of a key removed from the ghost lists.
+func (p *promoter[K, V]) forget(key K) {
+	delete(p.deadlines, key)
+}
+
+// clear drops every deadline.
+func (p *promoter[K, V]) clear() {
+	p.deadlines = make(map[K]time.Time)
+}
+
+func containsAny[K comparable](sets []*keySet[K], key K) bool {
+	for _, s := range sets {
+		if s.Contains(key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,215 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func setPromotionClock(clock *fakeClock, lists ...Lru[string, int]) {
+	for _, l := range lists {
+		l.(*unsafeCache[string, int]).now = clock.now
+	}
+}
+
+func TestARC_WithPromotionTTL(t *testing.T) {
+	for _, tt := range []struct {
+		mode PromotionTTL
+		want bool
+	}{
+		{ResetTTL, true},
+		{PreserveTTL, false},
+	} {
+		clock := &fakeClock{t: time.Unix(0, 0)}
+		c := NewARC[string, int](4, WithTTL[string, int](time.Minute), WithPromotionTTL[string, int](tt.mode))
+		setPromotionClock(clock, c.t1, c.t2)
+
+		c.Add("a", 1)
+		clock.advance(40 * time.Second)
+		if _, ok := c.Get("a"); !ok {
+			t.Fatalf("Expected %v, got %v", true, ok)
+		}
+		if !c.t2.Contains("a") {
+			t.Fatalf("Expected %v, got %v", true, false)
+		}
+		clock.advance(40 * time.Second)
+		if _, ok := c.Peek("a"); ok != tt.want {
+			t.Fatalf("mode %d: Expected %v, got %v", tt.mode, tt.want, ok)
+		}
+	}
+}
+
+func TestARC_WithPromotionTTL_Ghost(t *testing.T) {
+	for _, tt := range []struct {
+		mode PromotionTTL
+		want bool
+	}{
+		{ResetTTL, true},
+		{PreserveTTL, false},
+	} {
+		clock := &fakeClock{t: time.Unix(0, 0)}
+		c := NewARC[string, int](2, WithTTL[string, int](time.Minute), WithPromotionTTL[string, int](tt.mode))
+		setPromotionClock(clock, c.t1, c.t2)
+
+		c.Add("a", 1)
+		c.Add("b", 2)
+		c.Add("c", 3)
+		if !c.b1.Contains("a") {
+			t.Fatalf("Expected %v, got %v", true, false)
+		}
+
+		clock.advance(30 * time.Second)
+		c.Add("a", 1)
+		if !c.t2.Contains("a") {
+			t.Fatalf("Expected %v, got %v", true, false)
+		}
+		clock.advance(40 * time.Second)
+		if _, ok := c.Peek("a"); ok != tt.want {
+			t.Fatalf("mode %d: Expected %v, got %v", tt.mode, tt.want, ok)
+		}
+	}
+}
+
+func TestARC_WithPromotionTTL_GhostAfterDeadline(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	c := NewARC[string, int](2, WithTTL[string, int](time.Minute), WithPromotionTTL[string, int](PreserveTTL))
+	setPromotionClock(clock, c.t1, c.t2)
+
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Add("c", 3)
+
+	// The former deadline has passed, the re-admission is fresh
+	clock.advance(90 * time.Second)
+	c.Add("a", 1)
+	clock.advance(30 * time.Second)
+	if _, ok := c.Peek("a"); !ok {
+		t.Fatalf("Expected %v, got %v", true, ok)
+	}
+	if len(c.promoter.deadlines) != 0 {
+		t.Fatalf("Expected %v, got %v", 0, len(c.promoter.deadlines))
+	}
+}
+
+func TestARC_WithPromotionTTL_Forget(t *testing.T) {
+	c := NewARC[string, int](2, WithTTL[string, int](time.Minute), WithPromotionTTL[string, int](PreserveTTL))
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Add("c", 3)
+	if len(c.promoter.deadlines) != 1 {
+		t.Fatalf("Expected %v, got %v", 1, len(c.promoter.deadlines))
+	}
+	c.Remove("a")
+	if len(c.promoter.deadlines) != 0 {
+		t.Fatalf("Expected %v, got %v", 0, len(c.promoter.deadlines))
+	}
+
+	for i := 0; i < 100; i++ {
+		c.Add(string(rune('d'+i)), i)
+	}
+	if n := len(c.promoter.deadlines); n > ghostDeadlinesSlack*4 {
+		t.Fatalf("Expected at most %v, got %v", ghostDeadlinesSlack*4, n)
+	}
+	c.Clear()
+	if len(c.promoter.deadlines) != 0 {
+		t.Fatalf("Expected %v, got %v", 0, len(c.promoter.deadlines))
+	}
+}
+
+func Test2Q_WithPromotionTTL(t *testing.T) {
+	for _, tt := range []struct {
+		mode PromotionTTL
+		want bool
+	}{
+		{ResetTTL, true},
+		{PreserveTTL, false},
+	} {
+		clock := &fakeClock{t: time.Unix(0, 0)}
+		c := New2Q[string, int](4, WithTTL[string, int](time.Minute), WithPromotionTTL[string, int](tt.mode))
+		setPromotionClock(clock, c.recent, c.frequent)
+
+		c.Add("a", 1)
+		clock.advance(40 * time.Second)
+		if _, ok := c.Get("a"); !ok {
+			t.Fatalf("Expected %v, got %v", true, ok)
+		}
+		clock.advance(40 * time.Second)
+		if _, ok := c.Peek("a"); ok != tt.want {
+			t.Fatalf("mode %d: Expected %v, got %v", tt.mode, tt.want, ok)
+		}
+	}
+}
+
+func Test2Q_WithPromotionTTL_Ghost(t *testing.T) {
+	for _, tt := range []struct {
+		mode PromotionTTL
+		want bool
+	}{
+		{ResetTTL, true},
+		{PreserveTTL, false},
+	} {
+		clock := &fakeClock{t: time.Unix(0, 0)}
+		c := New2Q[string, int](4, WithTTL[string, int](time.Minute), WithPromotionTTL[string, int](tt.mode))
+		setPromotionClock(clock, c.recent, c.frequent)
+
+		for _, k := range []string{"a", "b", "c", "d", "e"} {
+			c.Add(k, 0)
+		}
+		if !c.recentEvict.Contains("a") {
+			t.Fatalf("Expected %v, got %v", true, false)
+		}
+
+		clock.advance(30 * time.Second)
+		c.Add("a", 1)
+		if !c.frequent.Contains("a") {
+			t.Fatalf("Expected %v, got %v", true, false)
+		}
+		clock.advance(40 * time.Second)
+		if _, ok := c.Peek("a"); ok != tt.want {
+			t.Fatalf("mode %d: Expected %v, got %v", tt.mode, tt.want, ok)
+		}
+	}
+}
+
+func TestARC_PromotionStoredValue(t *testing.T) {
+	var stores, loads int
+	c := NewARC[string, int](4, WithValueTransform[string, int](
+		func(v int) int { stores++; return v * 10 },
+		func(v int) int { loads++; return v / 10 },
+	))
+	c.Add("a", 1)
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Expected %v, got %v", 1, v)
+	}
+	if !c.t2.Contains("a") {
+		t.Fatalf("Expected %v, got %v", true, false)
+	}
+	if v, _ := c.Get("a"); v != 1 || stores != 1 || loads != 2 {
+		t.Fatalf("Expected %v, %v, %v, got %v, %v, %v", 1, 1, 2, v, stores, loads)
+	}
+}
+
+func TestARC_WithPromotionTTL_TTI(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	c := NewARC[string, int](4,
+		WithTTL[string, int](time.Minute),
+		WithTTI[string, int](10*time.Second),
+		WithPromotionTTL[string, int](PreserveTTL),
+	)
+	setPromotionClock(clock, c.t1, c.t2)
+
+	c.Add("a", 1)
+	clock.advance(5 * time.Second)
+	c.Get("a")
+
+	// The deadline of the time to idle is not kept as a time to live
+	for i := 0; i < 6; i++ {
+		clock.advance(8 * time.Second)
+		if _, ok := c.Get("a"); !ok {
+			t.Fatalf("Expected %v at %v, got %v", true, clock.t, ok)
+		}
+	}
+	clock.advance(8 * time.Second)
+	if _, ok := c.Get("a"); ok {
+		t.Fatalf("Expected %v, got %v", false, ok)
+	}
+}
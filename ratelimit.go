@@ -0,0 +1,111 @@
+package lru
+
+import "time"
+
+// SourceRateLimit bounds the rate at which each source of AddWithSource
+// inserts new keys, so that a misbehaving writer cannot flood a shared
+// cache. Updates of existing keys and the Adds without a source are not
+// limited.
+type SourceRateLimit struct {
+	// Rate is the number of insertions per second allowed to each source.
+	Rate float64
+
+	// Burst is the number of insertions a source can make at once, at
+	// least one.
+	Burst int
+
+	// Reject drops the insertions over the rate, which Put reports as
+	// AddResultRejected. Otherwise they are only reported.
+	Reject bool
+
+	// OnExceeded is optionally called with the source of each insertion
+	// over the rate, with the lock held, so it must not use the cache.
+	OnExceeded func(source string)
+}
+
+// WithSourceRateLimit limits the rate of the insertions of each source of
+// AddWithSource. A Rate of zero or less disables the limit.
+func WithSourceRateLimit[K comparable, V any](limit SourceRateLimit) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		if limit.Rate <= 0 {
+			c.sourceLimit = nil
+			return
+		}
+		if limit.Burst < 1 {
+			limit.Burst = 1
+		}
+		c.sourceLimit = &sourceLimiter{
+			SourceRateLimit: limit,
+			buckets:         make(map[string]*tokenBucket),
+			sweepAt:         minBucketSweep,
+		}
+	}
+}
+
+// minBucketSweep is the number of sources from which the idle token
+// buckets are swept.
+const minBucketSweep = 64
+
+// sourceLimiter is a token bucket per source. The full buckets behave
+// like missing ones, so they are swept once the number of buckets
+// doubled since the previous sweep.
+type sourceLimiter struct {
+	SourceRateLimit
+	buckets map[string]*tokenBucket
+	sweepAt int
+}
+
+type tokenBucket struct {
+	tokens float64
+	at     time.Time
+}
+
+// allow takes a token of the source, reporting whether the insertion
+// can proceed.
+func (l *sourceLimiter) allow(source string, now time.Time) bool {
+	b, ok := l.buckets[source]
+	if !ok {
+		if len(l.buckets) >= l.sweepAt {
+			l.sweep(now)
+		}
+		b = &tokenBucket{tokens: float64(l.Burst), at: now}
+		l.buckets[source] = b
+	}
+	l.refill(b, now)
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true
+	}
+	if l.OnExceeded != nil {
+		l.OnExceeded(source)
+	}
+	return !l.Reject
+}
+
+func (l *sourceLimiter) refill(b *tokenBucket, now time.Time) {
+	b.tokens += now.Sub(b.at).Seconds() * l.Rate
+	if b.tokens > float64(l.Burst) {
+		b.tokens = float64(l.Burst)
+	}
+	b.at = now
+}
+
+// sweep drops the buckets refilled since their last use.
+func (l *sourceLimiter) sweep(now time.Time) {
+	for source, b := range l.buckets {
+		if l.refill(b, now); b.tokens >= float64(l.Burst) {
+			delete(l.buckets, source)
+		}
+	}
+	l.sweepAt = 2 * len(l.buckets)
+	if l.sweepAt < minBucketSweep {
+		l.sweepAt = minBucketSweep
+	}
+}
+
+// overRate reports whether the insertion of the current source is
+// to be dropped for exceeding its rate.
+func (c *unsafeCache[K, V]) overRate() bool {
+	return c.sourceLimit != nil && c.source != "" && !c.sourceLimit.allow(c.source, c.now())
+}
@@ -0,0 +1,72 @@
+package lru
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func Test_unsafeCache_WithSourceRateLimit(t *testing.T) {
+	var exceeded []string
+	c, clock := newTTLCache(0, WithSourceRateLimit[string, int](SourceRateLimit{
+		Rate:   1,
+		Burst:  2,
+		Reject: true,
+		OnExceeded: func(source string) {
+			exceeded = append(exceeded, source)
+		},
+	}))
+
+	c.AddWithSource("a", 1, "batch")
+	c.AddWithSource("b", 2, "batch")
+	c.AddWithSource("c", 3, "batch")
+	if c.Contains("c") || len(exceeded) != 1 || exceeded[0] != "batch" {
+		t.Fatalf("Expected %v, got %v", []string{"batch"}, exceeded)
+	}
+
+	// Other sources, updates and untagged Adds are not limited
+	c.AddWithSource("d", 4, "web")
+	c.AddWithSource("a", 10, "batch")
+	c.Add("e", 5)
+	if !c.Contains("d") || !c.Contains("e") {
+		t.Fatalf("Expected %v, got %v", []string{"a", "b", "d", "e"}, c.Keys())
+	}
+	if v, _ := c.Peek("a"); v != 10 {
+		t.Fatalf("Expected %v, got %v", 10, v)
+	}
+
+	clock.advance(time.Second)
+	c.AddWithSource("c", 3, "batch")
+	if !c.Contains("c") {
+		t.Fatalf("Expected %v, got %v", true, false)
+	}
+}
+
+func Test_unsafeCache_WithSourceRateLimitReport(t *testing.T) {
+	var exceeded int
+	c, _ := newTTLCache(0, WithSourceRateLimit[string, int](SourceRateLimit{
+		Rate:       1,
+		OnExceeded: func(string) { exceeded++ },
+	}))
+	c.AddWithSource("a", 1, "batch")
+	c.AddWithSource("b", 2, "batch")
+	if !c.Contains("b") || exceeded != 1 {
+		t.Fatalf("Expected %v, %v, got %v, %v", true, 1, c.Contains("b"), exceeded)
+	}
+}
+
+func Test_unsafeCache_WithSourceRateLimitSweep(t *testing.T) {
+	c, clock := newTTLCache(0, WithSourceRateLimit[string, int](SourceRateLimit{
+		Rate:  1,
+		Burst: 2,
+	}))
+	for i := 0; i < 1000; i++ {
+		clock.advance(100 * time.Millisecond)
+		c.AddWithSource(fmt.Sprint(i), i, fmt.Sprint("client-", i))
+	}
+
+	// The buckets idle for 2s are full again, and swept
+	if n := len(c.sourceLimit.buckets); n == 0 || n > 2*minBucketSweep {
+		t.Fatalf("Expected at most %v, got %v", 2*minBucketSweep, n)
+	}
+}
@@ -0,0 +1,112 @@
+package lru
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// NewReadMostly creates a ReadMostlyCache of the given size, rebuilding its
+// snapshot every interval. The options apply to the LRU holding the writes.
+func NewReadMostly[K comparable, V any](maxEntries int, interval time.Duration, opts ...Option[K, V]) *ReadMostlyCache[K, V] {
+	c := &ReadMostlyCache[K, V]{
+		lru:  New[K, V](maxEntries, opts...),
+		done: make(chan struct{}),
+	}
+	c.snapshot.Store(&map[K]V{})
+	if interval > 0 {
+		c.wg.Add(1)
+		go c.rebuildEvery(interval)
+	}
+	return c
+}
+
+// ReadMostlyCache is a cache for read-heavy, write-light workloads. Get reads
+// an immutable snapshot of the entries without locking nor tracking recency,
+// while the writes go to an LRU cache which is copied to a new snapshot on
+// schedule, or by Rebuild. The writes are therefore visible to Get after
+// the next rebuild only, and the LRU evicts in the order of the writes.
+// It is safe for concurrent access.
+type ReadMostlyCache[K comparable, V any] struct {
+	snapshot atomic.Pointer[map[K]V]
+	dirty    int32
+
+	lru *Cache[K, V]
+
+	done chan struct{}
+	once sync.Once
+	wg   sync.WaitGroup
+}
+
+// Get looks up a key's value from the snapshot.
+func (c *ReadMostlyCache[K, V]) Get(key K) (value V, ok bool) {
+	value, ok = (*c.snapshot.Load())[key]
+	return value, ok
+}
+
+// Add a value to the cache, visible after the next rebuild. Returns true
+// if an eviction occurred.
+func (c *ReadMostlyCache[K, V]) Add(key K, value V) (evicted bool) {
+	evicted = c.lru.Add(key, value)
+	atomic.StoreInt32(&c.dirty, 1)
+	return evicted
+}
+
+// Remove removes the provided key from the cache after the next rebuild,
+// returning if the key was contained.
+func (c *ReadMostlyCache[K, V]) Remove(key K) (ok bool) {
+	if ok = c.lru.Remove(key); ok {
+		atomic.StoreInt32(&c.dirty, 1)
+	}
+	return ok
+}
+
+// Clear empties the cache after the next rebuild.
+func (c *ReadMostlyCache[K, V]) Clear() {
+	c.lru.Clear()
+	atomic.StoreInt32(&c.dirty, 1)
+}
+
+// Len returns the number of items in the snapshot.
+func (c *ReadMostlyCache[K, V]) Len() int {
+	return len(*c.snapshot.Load())
+}
+
+// Rebuild replaces the snapshot with the current entries, if they changed
+// since the last rebuild. The expired entries are removed beforehand.
+func (c *ReadMostlyCache[K, V]) Rebuild() {
+	if c.lru.RemoveExpired() == 0 && !atomic.CompareAndSwapInt32(&c.dirty, 1, 0) {
+		return
+	}
+	atomic.StoreInt32(&c.dirty, 0)
+
+	items := c.lru.Items()
+	snapshot := make(map[K]V, len(items))
+	for _, e := range items {
+		snapshot[e.Key] = e.Value
+	}
+	c.snapshot.Store(&snapshot)
+}
+
+// Close stops rebuilding the snapshot and closes the LRU.
+func (c *ReadMostlyCache[K, V]) Close() error {
+	c.once.Do(func() { close(c.done) })
+	c.wg.Wait()
+	return c.lru.Close()
+}
+
+func (c *ReadMostlyCache[K, V]) rebuildEvery(interval time.Duration) {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			c.Rebuild()
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package lru
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReadMostlyCache(t *testing.T) {
+	c := NewReadMostly[int, int](2, 0)
+	defer c.Close()
+
+	c.Add(1, 1)
+	if _, ok := c.Get(1); ok {
+		t.Fatal("should not be visible before the rebuild")
+	}
+	c.Rebuild()
+	if v, ok := c.Get(1); !ok || v != 1 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, true, v, ok)
+	}
+
+	c.Add(2, 2)
+	c.Add(3, 3)
+	c.Remove(2)
+	c.Rebuild()
+	if _, ok := c.Get(1); ok || c.Len() != 1 {
+		t.Fatal("1 should be evicted, 2 removed")
+	}
+
+	c.Clear()
+	c.Rebuild()
+	if c.Len() != 0 {
+		t.Fatalf("Expected %v, got %v", 0, c.Len())
+	}
+}
+
+func TestReadMostlyCache_Schedule(t *testing.T) {
+	c := NewReadMostly[int, int](128, time.Millisecond)
+	c.Add(1, 1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !func() bool { _, ok := c.Get(1); return ok }() {
+				time.Sleep(time.Millisecond)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
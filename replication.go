@@ -0,0 +1,81 @@
+package lru
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// EventEncoder writes cache events to a stream using encoding/gob,
+// so K and V must be encodable by gob.
+type EventEncoder[K comparable, V any] struct {
+	enc *gob.Encoder
+}
+
+func NewEventEncoder[K comparable, V any](w io.Writer) *EventEncoder[K, V] {
+	return &EventEncoder[K, V]{enc: gob.NewEncoder(w)}
+}
+
+// Encode writes a single event to the stream.
+func (e *EventEncoder[K, V]) Encode(ev Event[K, V]) error {
+	return e.enc.Encode(ev)
+}
+
+// EventDecoder reads cache events written by an EventEncoder.
+type EventDecoder[K comparable, V any] struct {
+	dec *gob.Decoder
+}
+
+func NewEventDecoder[K comparable, V any](r io.Reader) *EventDecoder[K, V] {
+	return &EventDecoder[K, V]{dec: gob.NewDecoder(r)}
+}
+
+// Decode reads the next event from the stream.
+func (d *EventDecoder[K, V]) Decode() (ev Event[K, V], err error) {
+	err = d.dec.Decode(&ev)
+	return ev, err
+}
+
+// Replicate writes the events that change the contents or the recency of
+// a cache to w until the events channel is closed, typically by cancelling
+// the subscription. Misses are not replicated.
+func Replicate[K comparable, V any](w io.Writer, events <-chan Event[K, V]) error {
+	enc := NewEventEncoder[K, V](w)
+	for ev := range events {
+		if ev.Kind == EventMiss {
+			continue
+		}
+		if err := enc.Encode(ev); err != nil {
+			return fmt.Errorf("lru: replicate: %w", err)
+		}
+	}
+	return nil
+}
+
+// Follow applies the events read from r to the follower cache until
+// the stream ends, so that it fails over warm. A cleanly closed stream
+// is not an error.
+func Follow[K comparable, V any](r io.Reader, follower Lru[K, V]) error {
+	dec := NewEventDecoder[K, V](r)
+	for {
+		ev, err := dec.Decode()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("lru: follow: %w", err)
+		}
+
+		switch ev.Kind {
+		case EventAdd, EventUpdate:
+			follower.Add(ev.Key, ev.Value)
+		case EventHit:
+			follower.Get(ev.Key)
+		case EventEvict, EventExpire, EventRemove:
+			follower.Remove(ev.Key)
+		case EventClear:
+			follower.Clear()
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package lru
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestReplicate(t *testing.T) {
+	var (
+		leader   = New[string, int](3)
+		follower = New[string, int](3)
+		r, w     = io.Pipe()
+		errCh    = make(chan error, 1)
+	)
+	events, cancel := leader.Subscribe()
+	go func() {
+		errCh <- Replicate[string, int](w, events)
+		_ = w.Close()
+	}()
+
+	leader.Add("a", 1)
+	leader.Add("b", 2)
+	leader.Add("a", 10)
+	leader.Get("b")
+	leader.Add("c", 3)
+	leader.Add("d", 4)
+	leader.Remove("c")
+	cancel()
+
+	if err := Follow[string, int](r, follower); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errCh; err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(leader.Keys(), follower.Keys()) {
+		t.Fatalf("keys not equal: (%v != %v)", leader.Keys(), follower.Keys())
+	}
+	if v, ok := follower.Peek("b"); !ok || v != 2 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 2, true, v, ok)
+	}
+}
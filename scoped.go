@@ -0,0 +1,139 @@
+package lru
+
+import "sync"
+
+// defaultScopedSize is the default size of a ScopedCache,
+// a single request writes few entries.
+const defaultScopedSize = 16
+
+// NewScoped creates a small cache for the lifetime of a single request.
+// Reads fall through to the shared parent, but writes and removals stay
+// local and are thrown away by Discard, so speculative per-request data
+// never pollutes the parent. The options apply to the local cache, which
+// holds 16 entries unless WithMaxEntries.
+func NewScoped[K comparable, V any](parent Cacher[K, V], opts ...Option[K, V]) *ScopedCache[K, V] {
+	return &ScopedCache[K, V]{
+		parent:  parent,
+		local:   NewUnsafeLru[K, V](defaultScopedSize, opts...),
+		removed: make(map[K]struct{}),
+	}
+}
+
+var _ Cacher[int, any] = (*ScopedCache[int, any])(nil)
+
+// ScopedCache is a request-scoped overlay of a parent cache.
+// It is safe for concurrent access.
+type ScopedCache[K comparable, V any] struct {
+	parent Cacher[K, V]
+	local  Lru[K, V]
+
+	// removed hides the parent's entries removed in this scope,
+	// cleared all of them
+	removed map[K]struct{}
+	cleared bool
+
+	sync.Mutex
+}
+
+// Add a value to the local cache. Returns true if a local eviction occurred.
+func (c *ScopedCache[K, V]) Add(key K, value V) (evicted bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	delete(c.removed, key)
+	return c.local.Add(key, value)
+}
+
+// Get looks up a key's value from the local cache, then from the parent.
+func (c *ScopedCache[K, V]) Get(key K) (value V, ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	if value, ok = c.local.Get(key); ok {
+		return
+	}
+	if c.hidden(key) {
+		return value, false
+	}
+	return c.parent.Get(key)
+}
+
+// Peek is like Get, without updating the "recently used"-ness of the key.
+func (c *ScopedCache[K, V]) Peek(key K) (value V, ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	if value, ok = c.local.Peek(key); ok {
+		return
+	}
+	if c.hidden(key) {
+		return value, false
+	}
+	return c.parent.Peek(key)
+}
+
+// Contains checks if a key is in the local cache or in the parent.
+func (c *ScopedCache[K, V]) Contains(key K) (ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.local.Contains(key) {
+		return true
+	}
+	if c.hidden(key) {
+		return false
+	}
+	return c.parent.Contains(key)
+}
+
+// Remove hides the key in this scope, the parent is left untouched.
+// Returns if the key was visible.
+func (c *ScopedCache[K, V]) Remove(key K) (ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	ok = c.local.Remove(key)
+	if !c.hidden(key) && c.parent.Contains(key) {
+		c.removed[key] = struct{}{}
+		ok = true
+	}
+	return ok
+}
+
+// Len returns the number of items written in this scope.
+func (c *ScopedCache[K, V]) Len() int {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.local.Len()
+}
+
+// Discard drops everything written or removed in this scope,
+// typically at the end of the request.
+func (c *ScopedCache[K, V]) Discard() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.local.Clear()
+	c.removed = make(map[K]struct{})
+	c.cleared = false
+}
+
+// Clear hides every entry in this scope, the parent is left untouched.
+func (c *ScopedCache[K, V]) Clear() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.local.Clear()
+	c.removed = make(map[K]struct{})
+	c.cleared = true
+}
+
+// hidden reports whether the parent's entry of the key is hidden.
+func (c *ScopedCache[K, V]) hidden(key K) bool {
+	if c.cleared {
+		return true
+	}
+	_, ok := c.removed[key]
+	return ok
+}
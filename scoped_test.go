@@ -0,0 +1,68 @@
+package lru
+
+import "testing"
+
+func TestScopedCache(t *testing.T) {
+	parent := New[string, int](10)
+	parent.Add("shared", 1)
+
+	c := NewScoped[string, int](parent, WithMaxEntries[string, int](10))
+	if v, ok := c.Get("shared"); !ok || v != 1 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, true, v, ok)
+	}
+
+	c.Add("local", 2)
+	c.Add("shared", 3)
+	if v, ok := c.Get("shared"); !ok || v != 3 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 3, true, v, ok)
+	}
+	if parent.Contains("local") {
+		t.Fatal("local write leaked into parent")
+	}
+	if v, _ := parent.Peek("shared"); v != 1 {
+		t.Fatalf("Expected %v, got %v", 1, v)
+	}
+
+	if !c.Remove("shared") {
+		t.Fatal("should be removed")
+	}
+	if c.Contains("shared") || !parent.Contains("shared") {
+		t.Fatal("remove should only hide the parent entry")
+	}
+	if c.Remove("shared") {
+		t.Fatal("should already be removed")
+	}
+
+	c.Discard()
+	if c.Len() != 0 || c.Contains("local") {
+		t.Fatal("local writes should be discarded")
+	}
+	if v, ok := c.Peek("shared"); !ok || v != 1 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, true, v, ok)
+	}
+}
+
+func TestScopedCache_Clear(t *testing.T) {
+	parent := New[string, int](10)
+	parent.Add("shared", 1)
+
+	c := NewScoped[string, int](parent)
+	if n := c.local.(*unsafeCache[string, int]).maxEntries; n != defaultScopedSize {
+		t.Fatalf("Expected %v, got %v", defaultScopedSize, n)
+	}
+
+	c.Add("local", 2)
+	c.Clear()
+	if c.Contains("shared") || c.Contains("local") || !parent.Contains("shared") {
+		t.Fatal("clear should only hide the parent entries")
+	}
+	c.Add("shared", 3)
+	if v, ok := c.Get("shared"); !ok || v != 3 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 3, true, v, ok)
+	}
+
+	c.Discard()
+	if v, ok := c.Get("shared"); !ok || v != 1 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, true, v, ok)
+	}
+}
@@ -0,0 +1,133 @@
+package lru
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrSlabLimit is reported when the slabs of WithSlabLimit are exhausted.
+	ErrSlabLimit = errors.New("lru: slab limit reached")
+
+	// ErrSlabAllocation is reported when a new slab cannot be allocated.
+	ErrSlabAllocation = errors.New("lru: slab allocation failed")
+)
+
+// WithSlabAllocation is an experimental option storing the entries in
+// preallocated slabs of slabSize entries instead of allocating them one by
+// one. The slabs are dropped wholesale by Clear, so caches that are cleared
+// periodically, such as per-epoch caches, produce few objects for the GC
+// to track in between. Memory of removed entries is reused by later Adds,
+// but only returned to the runtime by Clear.
+func WithSlabAllocation[K comparable, V any](slabSize int) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		if slabSize <= 0 {
+			c.slab = nil
+			return
+		}
+		c.slab = &entrySlab[K, V]{size: slabSize}
+	}
+}
+
+// WithSlabLimit bounds the number of slabs of WithSlabAllocation. Once
+// the limit is reached, or when a slab cannot be allocated, the entries
+// are allocated one by one instead of panicking, failed allocations halve
+// the size of the next slabs, and the error is reported to the hook of
+// WithOnError once until the next Clear.
+func WithSlabLimit[K comparable, V any](maxSlabs int) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		c.slabLimit = maxSlabs
+	}
+}
+
+// WithOnError sets a hook for the errors the cache recovers from
+// and cannot return, such as ErrSlabLimit and ErrSlabAllocation.
+func WithOnError[K comparable, V any](onError func(err error)) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		c.onError = onError
+	}
+}
+
+// entrySlab allocates entries from slabs.
+type entrySlab[K comparable, V any] struct {
+	size  int
+	slabs [][]entry[K, V]
+	// next is the index of the next unused entry of the last slab
+	next int
+	// free holds the released entries for reuse
+	free []*entry[K, V]
+	// degraded is set once an allocation failure was reported
+	degraded bool
+}
+
+// alloc returns an entry of the slabs, or an error if no slab is available
+// within the limit of max slabs, unlimited if 0.
+func (s *entrySlab[K, V]) alloc(max int) (*entry[K, V], error) {
+	if n := len(s.free); n > 0 {
+		ent := s.free[n-1]
+		s.free = s.free[:n-1]
+		return ent, nil
+	}
+	if len(s.slabs) == 0 || s.next == len(s.slabs[len(s.slabs)-1]) {
+		if max > 0 && len(s.slabs) >= max {
+			return nil, ErrSlabLimit
+		}
+		slab, err := s.grow()
+		if err != nil {
+			// Smaller slabs are more likely to fit
+			if s.size > 1 {
+				s.size /= 2
+			}
+			return nil, err
+		}
+		s.slabs = append(s.slabs, slab)
+		s.next = 0
+	}
+	ent := &s.slabs[len(s.slabs)-1][s.next]
+	s.next++
+	return ent, nil
+}
+
+// grow allocates a new slab, recovering from the runtime panic
+// of an allocation too large for the address space.
+func (s *entrySlab[K, V]) grow() (slab []entry[K, V], err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrSlabAllocation, r)
+		}
+	}()
+	return make([]entry[K, V], s.size), nil
+}
+
+func (s *entrySlab[K, V]) release(ent *entry[K, V]) {
+	*ent = entry[K, V]{}
+	s.free = append(s.free, ent)
+}
+
+func (s *entrySlab[K, V]) reset() {
+	s.slabs = nil
+	s.free = nil
+	s.next = 0
+	s.degraded = false
+}
+
+// newEntry returns a new entry, from the slabs if enabled, holding
+// the shared copy of the value WithDedup.
+func (c *unsafeCache[K, V]) newEntry(key K, value V) *entry[K, V] {
+	if c.dedup != nil {
+		value = c.dedup.intern(value)
+	}
+	if c.slab == nil {
+		return &entry[K, V]{key: key, value: value, source: c.source}
+	}
+	ent, err := c.slab.alloc(c.slabLimit)
+	if err != nil {
+		if !c.slab.degraded && c.onError != nil {
+			c.onError(err)
+		}
+		c.slab.degraded = true
+		return &entry[K, V]{key: key, value: value, source: c.source}
+	}
+	ent.key, ent.value, ent.source = key, value, c.source
+	return ent
+}
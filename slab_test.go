@@ -0,0 +1,101 @@
+package lru
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func Test_unsafeCache_WithSlabAllocation(t *testing.T) {
+	c := NewUnsafeLru[int, int](10, WithSlabAllocation[int, int](4)).(*unsafeCache[int, int])
+	for i := 0; i < 30; i++ {
+		c.Add(i, i)
+	}
+	for i := 20; i < 30; i++ {
+		if v, ok := c.Get(i); !ok || v != i {
+			t.Fatalf("Expected %v, %v, got %v, %v", i, true, v, ok)
+		}
+	}
+
+	// Evicted entries are reused, so no more than the needed slabs exist
+	if n := len(c.slab.slabs); n != 3 {
+		t.Fatalf("Expected %v, got %v", 3, n)
+	}
+
+	k, v, ok := c.RemoveOldest()
+	if !ok || k != 20 || v != 20 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 20, 20, k, v)
+	}
+
+	c.Clear()
+	if len(c.slab.slabs) != 0 || c.Len() != 0 {
+		t.Fatalf("slabs should be dropped")
+	}
+	c.Add(1, 1)
+	if v, ok := c.Get(1); !ok || v != 1 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, true, v, ok)
+	}
+}
+
+func BenchmarkUnsafeLru_AddSlab(b *testing.B) {
+	c := NewUnsafeLru[int, int](defaultSize, WithSlabAllocation[int, int](defaultSize))
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c.Add(i, i)
+		if i%(defaultSize*8) == 0 {
+			c.Clear()
+		}
+	}
+}
+
+func Test_unsafeCache_WithSlabLimit(t *testing.T) {
+	var errs []error
+	c := NewUnsafeLru[int, int](10,
+		WithSlabLimit[int, int](2),
+		WithSlabAllocation[int, int](4),
+		WithOnError[int, int](func(err error) { errs = append(errs, err) }),
+	).(*unsafeCache[int, int])
+	for i := 0; i < 10; i++ {
+		c.Add(i, i)
+	}
+	if len(c.slab.slabs) != 2 || c.Len() != 10 {
+		t.Fatalf("Expected %v slabs and %v entries, got %v, %v", 2, 10, len(c.slab.slabs), c.Len())
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], ErrSlabLimit) {
+		t.Fatalf("Expected %v, got %v", ErrSlabLimit, errs)
+	}
+	for i := 0; i < 10; i++ {
+		if v, ok := c.Get(i); !ok || v != i {
+			t.Fatalf("Expected %v, %v, got %v, %v", i, true, v, ok)
+		}
+	}
+
+	// Reported again after a Clear
+	c.Clear()
+	for i := 0; i < 10; i++ {
+		c.Add(i, i)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("Expected %v, got %v", 2, len(errs))
+	}
+}
+
+func Test_unsafeCache_SlabAllocationFailure(t *testing.T) {
+	var errs []error
+	c := NewUnsafeLru[int, int](10,
+		WithSlabAllocation[int, int](math.MaxInt/2),
+		WithOnError[int, int](func(err error) { errs = append(errs, err) }),
+	).(*unsafeCache[int, int])
+	c.Add(1, 1)
+	if v, ok := c.Get(1); !ok || v != 1 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, true, v, ok)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], ErrSlabAllocation) {
+		t.Fatalf("Expected %v, got %v", ErrSlabAllocation, errs)
+	}
+	if c.slab.size != math.MaxInt/4 {
+		t.Fatalf("Expected %v, got %v", math.MaxInt/4, c.slab.size)
+	}
+}
@@ -0,0 +1,79 @@
+package lru
+
+import (
+	"reflect"
+	"time"
+)
+
+// Snapshot is a frozen copy of the entries of a cache, unaffected by the
+// later changes of the cache.
+type Snapshot[K comparable, V any] struct {
+	// At is when the snapshot was taken.
+	At time.Time
+
+	items []Entry[K, V]
+	index map[K]int
+}
+
+// NewSnapshot copies the entries of the cache, which can be any cache of
+// this package with Items, e.g. a Cache or the Lru of NewUnsafeLru.
+func NewSnapshot[K comparable, V any](c interface{ Items() []Entry[K, V] }) *Snapshot[K, V] {
+	items := c.Items()
+	s := &Snapshot[K, V]{
+		At:    time.Now(),
+		items: items,
+		index: make(map[K]int, len(items)),
+	}
+	for i, e := range items {
+		s.index[e.Key] = i
+	}
+	return s
+}
+
+// Len returns the number of entries in the snapshot.
+func (s *Snapshot[K, V]) Len() int {
+	return len(s.items)
+}
+
+// Get returns the entry of the key in the snapshot.
+func (s *Snapshot[K, V]) Get(key K) (e Entry[K, V], ok bool) {
+	i, ok := s.index[key]
+	if !ok {
+		return e, false
+	}
+	return s.items[i], true
+}
+
+// Items returns the entries of the snapshot, from oldest to newest.
+func (s *Snapshot[K, V]) Items() []Entry[K, V] {
+	return append(make([]Entry[K, V], 0, len(s.items)), s.items...)
+}
+
+// Diff compares the snapshots a and b, taken before and after, returning
+// the keys only in b, the keys only in a, and the keys whose value differs
+// according to reflect.DeepEqual. added follows the order of b, removed and
+// changed the order of a, from oldest to newest.
+func Diff[K comparable, V any](a, b *Snapshot[K, V]) (added, removed, changed []K) {
+	return DiffFunc(a, b, func(x, y V) bool {
+		return reflect.DeepEqual(x, y)
+	})
+}
+
+// DiffFunc is like Diff, comparing the values with equal.
+func DiffFunc[K comparable, V any](a, b *Snapshot[K, V], equal func(x, y V) bool) (added, removed, changed []K) {
+	for _, e := range a.items {
+		f, ok := b.Get(e.Key)
+		switch {
+		case !ok:
+			removed = append(removed, e.Key)
+		case !equal(e.Value, f.Value):
+			changed = append(changed, e.Key)
+		}
+	}
+	for _, e := range b.items {
+		if _, ok := a.index[e.Key]; !ok {
+			added = append(added, e.Key)
+		}
+	}
+	return added, removed, changed
+}
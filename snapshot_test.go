@@ -0,0 +1,63 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	c := New[string, []int](10)
+	c.Add("a", []int{1})
+	c.Add("b", []int{2})
+	c.Add("c", []int{3})
+	a := NewSnapshot[string, []int](c)
+
+	c.Remove("a")
+	c.Add("b", []int{2})
+	c.Add("c", []int{30})
+	c.Add("d", []int{4})
+	b := NewSnapshot[string, []int](c)
+
+	// The snapshots are frozen
+	if a.Len() != 3 || b.Len() != 3 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 3, 3, a.Len(), b.Len())
+	}
+	if e, ok := a.Get("c"); !ok || e.Value[0] != 3 {
+		t.Fatalf("Expected %v, got %v", 3, e.Value)
+	}
+
+	added, removed, changed := Diff(a, b)
+	if !reflect.DeepEqual(added, []string{"d"}) {
+		t.Fatalf("Expected %v, got %v", []string{"d"}, added)
+	}
+	if !reflect.DeepEqual(removed, []string{"a"}) {
+		t.Fatalf("Expected %v, got %v", []string{"a"}, removed)
+	}
+	if !reflect.DeepEqual(changed, []string{"c"}) {
+		t.Fatalf("Expected %v, got %v", []string{"c"}, changed)
+	}
+
+	added, removed, changed = Diff(a, a)
+	if added != nil || removed != nil || changed != nil {
+		t.Fatalf("Expected no difference, got %v, %v, %v", added, removed, changed)
+	}
+}
+
+func TestDiffFunc(t *testing.T) {
+	c := NewUnsafeLru[int, int](10)
+	c.Add(1, 1)
+	a := NewSnapshot[int, int](c)
+	c.Add(1, -1)
+	b := NewSnapshot[int, int](c)
+
+	abs := func(x int) int {
+		if x < 0 {
+			return -x
+		}
+		return x
+	}
+	_, _, changed := DiffFunc(a, b, func(x, y int) bool { return abs(x) == abs(y) })
+	if len(changed) != 0 {
+		t.Fatalf("Expected %v, got %v", 0, len(changed))
+	}
+}
@@ -0,0 +1,86 @@
+// Package snapshotpb writes and reads cache snapshots in the protobuf schema
+// of snapshot.proto, so that tools in other languages can read the state
+// exported by a cache. snapshot.pb.go is generated from it by protoc-gen-go.
+package snapshotpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative snapshot.proto
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/electricbubble/lru"
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec converts keys or values to and from the bytes of the snapshot.
+// The codecs of package peer implement it.
+type Codec[T any] interface {
+	Marshal(v T) ([]byte, error)
+	Unmarshal(data []byte) (T, error)
+}
+
+// Write writes a Snapshot message of the entries of the cache to w.
+func Write[K comparable, V any](w io.Writer, c lru.Lru[K, V], keyCodec Codec[K], valueCodec Codec[V]) error {
+	items := c.Items()
+	snapshot := &Snapshot{Entries: make([]*Entry, len(items))}
+	for i, e := range items {
+		key, err := keyCodec.Marshal(e.Key)
+		if err != nil {
+			return fmt.Errorf("lru: write snapshot: %w", err)
+		}
+		value, err := valueCodec.Marshal(e.Value)
+		if err != nil {
+			return fmt.Errorf("lru: write snapshot: %w", err)
+		}
+		snapshot.Entries[i] = &Entry{Key: key, Value: value, Hits: e.Hits, Cost: e.Cost}
+		if !e.ExpiresAt.IsZero() {
+			snapshot.Entries[i].ExpiresAtUnixNano = e.ExpiresAt.UnixNano()
+		}
+	}
+
+	data, err := proto.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("lru: write snapshot: %w", err)
+	}
+	if _, err = w.Write(data); err != nil {
+		return fmt.Errorf("lru: write snapshot: %w", err)
+	}
+	return nil
+}
+
+// Read adds the entries of a Snapshot message read from r to the cache,
+// keeping their recency order and expirations, and returns the number of
+// entries added. The entries expired since the snapshot are skipped.
+func Read[K comparable, V any](r io.Reader, c lru.Lru[K, V], keyCodec Codec[K], valueCodec Codec[V]) (n int, err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, fmt.Errorf("lru: read snapshot: %w", err)
+	}
+	var snapshot Snapshot
+	if err = proto.Unmarshal(data, &snapshot); err != nil {
+		return 0, fmt.Errorf("lru: read snapshot: %w", err)
+	}
+
+	now := time.Now()
+	for _, e := range snapshot.Entries {
+		key, err := keyCodec.Unmarshal(e.Key)
+		if err != nil {
+			return n, fmt.Errorf("lru: read snapshot: %w", err)
+		}
+		value, err := valueCodec.Unmarshal(e.Value)
+		if err != nil {
+			return n, fmt.Errorf("lru: read snapshot: %w", err)
+		}
+		if e.ExpiresAtUnixNano == 0 {
+			c.Add(key, value)
+		} else if ttl := time.Unix(0, e.ExpiresAtUnixNano).Sub(now); ttl > 0 {
+			c.AddWithTTL(key, value, ttl)
+		} else {
+			continue
+		}
+		n++
+	}
+	return n, nil
+}
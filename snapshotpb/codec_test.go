@@ -0,0 +1,50 @@
+package snapshotpb
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/electricbubble/lru"
+	"github.com/electricbubble/lru/peer"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSnapshot(t *testing.T) {
+	l := lru.New[string, int](128)
+	l.Add("a", 1)
+	l.AddWithTTL("b", 2, time.Hour)
+	l.AddWithTTL("c", 3, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	var buf bytes.Buffer
+	if err := Write[string, int](&buf, l, peer.StringCodec{}, peer.JSONCodec[int]{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Readable without the cache types
+	var snapshot Snapshot
+	if err := proto.Unmarshal(buf.Bytes(), &snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshot.Entries) != 3 || string(snapshot.Entries[1].Key) != "b" || snapshot.Entries[1].ExpiresAtUnixNano == 0 {
+		t.Fatalf("bad snapshot: %v", snapshot.Entries)
+	}
+
+	restored := lru.New[string, int](128)
+	n, err := Read[string, int](&buf, restored, peer.StringCodec{}, peer.JSONCodec[int]{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || !reflect.DeepEqual(restored.Keys(), []string{"a", "b"}) {
+		t.Fatalf("Expected %v, got %v", []string{"a", "b"}, restored.Keys())
+	}
+	if v, _ := restored.Peek("b"); v != 2 {
+		t.Fatalf("Expected %v, got %v", 2, v)
+	}
+
+	if _, err = Read[string, int](bytes.NewBufferString("\xff"), restored, peer.StringCodec{}, peer.JSONCodec[int]{}); err == nil {
+		t.Fatal("should fail")
+	}
+}
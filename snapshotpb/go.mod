@@ -0,0 +1,10 @@
+module github.com/electricbubble/lru/snapshotpb
+
+go 1.19
+
+require (
+	github.com/electricbubble/lru v0.0.0
+	google.golang.org/protobuf v1.31.0
+)
+
+replace github.com/electricbubble/lru => ../
@@ -0,0 +1,28 @@
+package lru
+
+func (c *unsafeCache[K, V]) AddWithSource(key K, value V, source string) (evicted bool) {
+	c.source = source
+	defer func() { c.source = "" }()
+
+	return c.Add(key, value)
+}
+
+// Sources returns the number of entries, and their total cost WithWeigher,
+// per source of AddWithSource. The entries added without a source are
+// counted under "".
+func (c *unsafeCache[K, V]) Sources() map[string]SourceUsage {
+	usage := make(map[string]SourceUsage)
+	for elem := c.entries.Front(); elem != nil; elem = elem.Next() {
+		u := usage[elem.Value.source]
+		u.Len++
+		u.Cost += elem.Value.cost
+		usage[elem.Value.source] = u
+	}
+	return usage
+}
+
+// SourceUsage is the occupancy of the cache by the entries of a source.
+type SourceUsage struct {
+	Len  int
+	Cost int64
+}
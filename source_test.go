@@ -0,0 +1,48 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLru_AddWithSource(t *testing.T) {
+	var evicted []Entry[string, int]
+	l := New[string, int](2, WithEvictionHistory[string, int](4, false))
+	events, cancel := l.Subscribe()
+	defer cancel()
+
+	l.AddWithSource("a", 1, "sessions")
+	l.AddWithSource("b", 2, "users")
+	if e, _ := l.PeekEntry("a"); e.Source != "sessions" {
+		t.Fatalf("Expected %v, got %v", "sessions", e.Source)
+	}
+
+	l.AddWithSource("c", 3, "users")
+	for len(events) > 0 {
+		if ev := <-events; ev.Kind == EventEvict {
+			evicted = append(evicted, ev.Entry)
+		}
+	}
+	if len(evicted) != 1 || evicted[0].Key != "a" || evicted[0].Source != "sessions" {
+		t.Fatalf("Expected %v, got %v", "sessions", evicted)
+	}
+	if rec := l.RecentEvictions(1); rec[0].Source != "sessions" {
+		t.Fatalf("Expected %v, got %v", "sessions", rec[0].Source)
+	}
+
+	want := map[string]SourceUsage{"users": {Len: 2}}
+	if usage := l.Sources(); !reflect.DeepEqual(usage, want) {
+		t.Fatalf("Expected %v, got %v", want, usage)
+	}
+
+	// An update without a source clears it
+	l.Add("b", 20)
+	want = map[string]SourceUsage{"users": {Len: 1}, "": {Len: 1}}
+	if usage := l.Sources(); !reflect.DeepEqual(usage, want) {
+		t.Fatalf("Expected %v, got %v", want, usage)
+	}
+	l.Add("d", 4)
+	if e, _ := l.PeekEntry("d"); e.Source != "" {
+		t.Fatalf("Expected %v, got %v", "", e.Source)
+	}
+}
@@ -0,0 +1,143 @@
+package lru
+
+import (
+	"os"
+	"sync"
+)
+
+// NewSpill creates a SpillCache of the given size, spilling the values
+// larger than threshold bytes to temporary files in dir, which defaults
+// to os.TempDir.
+func NewSpill[K comparable](maxEntries, threshold int, dir string) *SpillCache[K] {
+	c := &SpillCache[K]{threshold: threshold, dir: dir}
+	c.lru = NewUnsafeLru[K, spillValue](maxEntries, WithOnEvicted(func(key K, value spillValue) {
+		c.release(value)
+	}))
+	return c
+}
+
+// SpillCache is a thread-safe LRU cache of byte slices which keeps the large
+// values on disk, so that huge blobs do not dominate the heap. A spilled
+// value is read back by Get, and its file is deleted when the entry leaves
+// the cache.
+type SpillCache[K comparable] struct {
+	threshold int
+	dir       string
+
+	lru Lru[K, spillValue]
+
+	sync.Mutex
+}
+
+// spillValue is a value held in memory, or the path of the file holding it.
+type spillValue struct {
+	data []byte
+	path string
+}
+
+// Add a value to the cache, spilling it to disk if it is larger than the
+// threshold. Returns true if an eviction occurred, or the error of writing
+// the file, in which case the cache is unchanged.
+func (c *SpillCache[K]) Add(key K, value []byte) (evicted bool, err error) {
+	v := spillValue{data: value}
+	if len(value) > c.threshold {
+		if v.path, err = c.spill(value); err != nil {
+			return false, err
+		}
+		v.data = nil
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	// The callbacks are not executed for updated values
+	if old, ok := c.lru.Peek(key); ok {
+		defer c.release(old)
+	}
+	return c.lru.Add(key, v), nil
+}
+
+// Get looks up a key's value from the cache, reading it back from disk if
+// it was spilled. A spilled value whose file cannot be read is a miss.
+func (c *SpillCache[K]) Get(key K) (value []byte, ok bool) {
+	c.Lock()
+	v, ok := c.lru.Get(key)
+	c.Unlock()
+
+	if !ok || v.path == "" {
+		return v.data, ok
+	}
+	// Read outside of the lock, the entry may be evicted meanwhile
+	value, err := os.ReadFile(v.path)
+	if err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// Contains checks if a key is in the cache, without updating the recent-ness.
+func (c *SpillCache[K]) Contains(key K) (ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.lru.Contains(key)
+}
+
+// Spilled checks if the value of the key is on disk.
+func (c *SpillCache[K]) Spilled(key K) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	v, ok := c.lru.Peek(key)
+	return ok && v.path != ""
+}
+
+// Remove removes the provided key from the cache, deleting its file,
+// returning if the key was contained.
+func (c *SpillCache[K]) Remove(key K) (ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.lru.Remove(key)
+}
+
+// Len returns the number of items in the cache.
+func (c *SpillCache[K]) Len() int {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.lru.Len()
+}
+
+// Clear is used to completely clear the cache, deleting the files.
+func (c *SpillCache[K]) Clear() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.lru.Clear()
+}
+
+// spill writes the value to a new temporary file, returning its path.
+func (c *SpillCache[K]) spill(value []byte) (path string, err error) {
+	f, err := os.CreateTemp(c.dir, "lru-spill-*")
+	if err != nil {
+		return "", err
+	}
+	if _, err = f.Write(value); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	if err = f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// release deletes the file of a spilled value.
+func (c *SpillCache[K]) release(v spillValue) {
+	if v.path != "" {
+		_ = os.Remove(v.path)
+	}
+}
@@ -0,0 +1,77 @@
+package lru
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestSpillCache(t *testing.T) {
+	dir := t.TempDir()
+	files := func() int {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(entries)
+	}
+
+	c := NewSpill[string](2, 4, dir)
+	small, large := []byte("abc"), bytes.Repeat([]byte("x"), 1024)
+	if _, err := c.Add("small", small); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Add("large", large); err != nil {
+		t.Fatal(err)
+	}
+	if c.Spilled("small") || !c.Spilled("large") || files() != 1 {
+		t.Fatalf("bad spill: %v files", files())
+	}
+
+	if v, ok := c.Get("large"); !ok || !bytes.Equal(v, large) {
+		t.Fatal("large value not read back")
+	}
+	if v, ok := c.Get("small"); !ok || !bytes.Equal(v, small) {
+		t.Fatal("small value not read back")
+	}
+
+	// Updating deletes the file of the previous value
+	if _, err := c.Add("large", append(large, 'y')); err != nil {
+		t.Fatal(err)
+	}
+	if files() != 1 {
+		t.Fatalf("Expected %v, got %v", 1, files())
+	}
+
+	// Evicting deletes the file
+	c.Get("small")
+	if evicted, _ := c.Add("other", small); !evicted || c.Contains("large") || files() != 0 {
+		t.Fatalf("bad eviction: %v files", files())
+	}
+
+	c.Add("large", large)
+	if !c.Remove("large") || files() != 0 {
+		t.Fatalf("Expected %v, got %v", 0, files())
+	}
+	c.Add("large", large)
+	c.Clear()
+	if c.Len() != 0 || files() != 0 {
+		t.Fatalf("Expected %v, got %v", 0, files())
+	}
+}
+
+func TestSpillCache_MissingFile(t *testing.T) {
+	c := NewSpill[string](2, 0, t.TempDir())
+	c.Add("a", []byte("abc"))
+	c.Lock()
+	v, _ := c.lru.Peek("a")
+	c.Unlock()
+	os.Remove(v.path)
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("should miss")
+	}
+	if _, err := NewSpill[string](2, 0, "/nonexistent/dir").Add("a", []byte("abc")); err == nil {
+		t.Fatal("should fail")
+	}
+}
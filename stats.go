@@ -0,0 +1,91 @@
+package lru
+
+// Stats are the counters of a cache. Lookups which do not update the
+// "recently used"-ness, like Peek and Contains, are not counted.
+type Stats struct {
+	Hits        uint64 // Lookups of a fresh entry
+	Misses      uint64 // Lookups of a missing or expired entry
+	Evictions   uint64 // Entries evicted to respect the limits
+	Expirations uint64 // Entries removed for being stale
+
+	// High-water marks of the number of entries, and of their total
+	// cost WithWeigher, observed after the Adds. Those of StatsDelta
+	// restart from the current values on every call.
+	MaxLen  int
+	MaxCost int64
+
+	// LockWait are the sampled waits for the lock of the Cache
+	// WithLockWaitSampling.
+	LockWait LockWaitHistogram
+}
+
+// Requests returns the number of lookups.
+func (s Stats) Requests() uint64 {
+	return s.Hits + s.Misses
+}
+
+// HitRatio returns the fraction of the lookups which were hits,
+// zero when there were none.
+func (s Stats) HitRatio() float64 {
+	if s.Requests() == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Requests())
+}
+
+// Sub returns the counters accumulated since the older snapshot s0.
+// The high-water marks are the ones of s.
+func (s Stats) Sub(s0 Stats) Stats {
+	return Stats{
+		Hits:        s.Hits - s0.Hits,
+		Misses:      s.Misses - s0.Misses,
+		Evictions:   s.Evictions - s0.Evictions,
+		Expirations: s.Expirations - s0.Expirations,
+		MaxLen:      s.MaxLen,
+		MaxCost:     s.MaxCost,
+		LockWait:    s.LockWait.Sub(s0.LockWait),
+	}
+}
+
+func (c *unsafeCache[K, V]) Stats() Stats {
+	return c.stats
+}
+
+func (c *unsafeCache[K, V]) StatsDelta() Stats {
+	delta := c.stats.Sub(c.deltaBase)
+	delta.MaxLen, delta.MaxCost = c.deltaBase.MaxLen, c.deltaBase.MaxCost
+
+	c.deltaBase = c.stats
+	c.deltaBase.MaxLen, c.deltaBase.MaxCost = 0, 0
+	if !c.noStats {
+		c.peak()
+	}
+	return delta
+}
+
+// WithNoStats disables the Stats counters, which then stay at zero,
+// and the observers of WithObserver, for the hot paths which do not
+// want to pay for any bookkeeping. The events of Subscribe are still
+// published once subscribed to.
+func WithNoStats[K comparable, V any]() Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		c.noStats = true
+		c.events.observers = nil
+	}
+}
+
+// peak updates the high-water marks after an Add.
+func (c *unsafeCache[K, V]) peak() {
+	if n := c.entries.Len(); n > c.stats.MaxLen {
+		c.stats.MaxLen = n
+	}
+	if n := c.entries.Len(); n > c.deltaBase.MaxLen {
+		c.deltaBase.MaxLen = n
+	}
+	if c.cost > c.deltaBase.MaxCost {
+		c.deltaBase.MaxCost = c.cost
+	}
+	if c.cost > c.stats.MaxCost {
+		c.stats.MaxCost = c.cost
+	}
+}
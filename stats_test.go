@@ -0,0 +1,114 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLru_Stats(t *testing.T) {
+	l, clock := newTTLCache(time.Minute)
+	l.Resize(2)
+	l.Add("a", 1)
+	l.Add("b", 2)
+	l.Get("a")
+	l.Get("x")
+	l.Add("c", 3)
+	l.Peek("a")
+	l.Contains("x")
+
+	clock.advance(2 * time.Minute)
+	l.Get("c")
+
+	stats := l.Stats()
+	expected := Stats{Hits: 1, Misses: 2, Evictions: 1, Expirations: 1, MaxLen: 2}
+	if stats != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, stats)
+	}
+	if stats.Requests() != 3 || stats.HitRatio() != 1.0/3 {
+		t.Fatalf("bad ratio: %v", stats.HitRatio())
+	}
+
+	delta := stats.Sub(Stats{Hits: 1, Misses: 1})
+	if delta != (Stats{Misses: 1, Evictions: 1, Expirations: 1, MaxLen: 2}) {
+		t.Fatalf("bad delta: %+v", delta)
+	}
+	if (Stats{}).HitRatio() != 0 {
+		t.Fatal("empty ratio should be zero")
+	}
+}
+
+func TestLru_Stats_HighWaterMarks(t *testing.T) {
+	l := New[int, int](10, WithWeigher(func(k, v int) int64 { return int64(v) }, 100))
+	for i := 0; i < 5; i++ {
+		l.Add(i, 10)
+	}
+	l.Add(0, 50)
+	l.RemoveAll([]int{0, 1, 2, 3})
+	l.Add(5, 10)
+
+	stats := l.Stats()
+	if stats.MaxLen != 5 || stats.MaxCost != 90 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 5, 90, stats.MaxLen, stats.MaxCost)
+	}
+
+	// Evictions happen before the marks are taken
+	l.Add(6, 100)
+	if stats := l.Stats(); stats.MaxCost != 100 || stats.MaxLen != 5 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 100, 5, stats.MaxCost, stats.MaxLen)
+	}
+}
+
+func TestLru_StatsDelta(t *testing.T) {
+	l := New[int, int](10)
+	for i := 0; i < 5; i++ {
+		l.Add(i, i)
+	}
+	l.Get(1)
+	l.Get(10)
+
+	delta := l.StatsDelta()
+	if delta != (Stats{Hits: 1, Misses: 1, MaxLen: 5}) {
+		t.Fatalf("bad delta: %+v", delta)
+	}
+
+	l.RemoveAll([]int{0, 1, 2})
+	l.Get(3)
+	if stats := l.Stats(); stats != (Stats{Hits: 2, Misses: 1, MaxLen: 5}) {
+		t.Fatalf("bad stats: %+v", stats)
+	}
+	l.StatsDelta()
+	if stats := l.StatsDelta(); stats != (Stats{MaxLen: 2}) {
+		t.Fatalf("bad stats: %+v", stats)
+	}
+}
+
+func TestLru_WithNoStats(t *testing.T) {
+	var log []string
+	l := New[string, int](1,
+		WithObserver[string, int](recordingObserver{name: "a", log: &log}),
+		WithNoStats[string, int](),
+	)
+	l.Add("a", 1)
+	l.Get("a")
+	l.Get("b")
+	l.Add("b", 2)
+	if stats := l.Stats(); stats != (Stats{}) {
+		t.Fatalf("Expected no stats, got %+v", stats)
+	}
+	if stats := l.StatsDelta(); stats != (Stats{}) {
+		t.Fatalf("Expected no stats, got %+v", stats)
+	}
+	if len(log) != 0 {
+		t.Fatalf("Expected no notifications, got %v", log)
+	}
+	if l.lru.(*unsafeCache[string, int]).events.listened() {
+		t.Fatal("nothing should be published before subscribing")
+	}
+
+	events, cancel := l.Subscribe()
+	defer cancel()
+	l.Add("c", 3)
+	if ev := <-events; ev.Kind != EventAdd || ev.Key != "c" {
+		t.Fatalf("Expected %v, got %v", EventAdd, ev)
+	}
+}
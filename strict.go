@@ -0,0 +1,28 @@
+package lru
+
+import "github.com/electricbubble/lru/list"
+
+// WithStrictLRU makes Contains and Peek count as accesses, like Get: the
+// entries they find become the most recently used ones and have their
+// time to idle restarted, without counting as hits. The Cache, ARCCache
+// and TwoQueueCache then take their write lock for them.
+func WithStrictLRU[K comparable, V any]() Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		c.strict = true
+	}
+}
+
+// observe updates the recency of an entry found by Contains or Peek
+// with WithStrictLRU.
+func (c *unsafeCache[K, V]) observe(elem *list.Element[*entry[K, V]]) {
+	c.entries.MoveToFront(elem)
+	if c.policy != nil {
+		c.policy.Access(elem.Value.key)
+	}
+	if c.decayHalfLife > 0 {
+		c.recordAccess(elem.Value)
+	}
+	if c.tti > 0 {
+		c.access(elem.Value, c.now())
+	}
+}
@@ -0,0 +1,60 @@
+package lru
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestLru_WithStrictLRU(t *testing.T) {
+	l := New[int, int](2, WithStrictLRU[int, int]())
+	l.Add(1, 1)
+	l.Add(2, 2)
+	l.Contains(1)
+	l.Add(3, 3)
+	if !l.Contains(1) || l.Contains(2) {
+		t.Fatal("Contains should keep the entry warm")
+	}
+	l.Peek(1)
+	l.Add(4, 4)
+	if _, ok := l.Peek(1); !ok || l.Contains(3) {
+		t.Fatal("Peek should keep the entry warm")
+	}
+	if s := l.Stats(); s.Hits != 0 {
+		t.Fatalf("Expected %v, got %v", 0, s.Hits)
+	}
+
+	// The default keeps Contains and Peek read-only
+	d := New[int, int](2)
+	d.Add(1, 1)
+	d.Add(2, 2)
+	d.Contains(1)
+	d.Peek(1)
+	d.Add(3, 3)
+	if d.Contains(1) {
+		t.Fatal("entry should be evicted")
+	}
+}
+
+func TestWithStrictLRU_ConcurrentPeek(t *testing.T) {
+	arc := NewARC[int, int](64, WithStrictLRU[int, int]())
+	twoQ := New2Q[int, int](64, WithStrictLRU[int, int]())
+	for i := 0; i < 64; i++ {
+		arc.Add(i, i)
+		twoQ.Add(i, i)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				arc.Peek(i % 64)
+				arc.Contains(i % 64)
+				twoQ.Peek(i % 64)
+				twoQ.Contains(i % 64)
+			}
+		}()
+	}
+	wg.Wait()
+}
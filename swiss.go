@@ -0,0 +1,297 @@
+package lru
+
+import (
+	"fmt"
+	"hash/maphash"
+	"math/bits"
+
+	"github.com/electricbubble/lru/list"
+)
+
+// MapBackend selects the map indexing the entries of a cache by key.
+type MapBackend uint8
+
+const (
+	// BuiltinMap indexes the entries with a Go map.
+	BuiltinMap MapBackend = iota
+	// SwissMap indexes the entries with an open-addressing table in the
+	// style of the swiss tables, storing the keys inline with a byte of
+	// metadata each. It uses less memory per entry and fewer cache misses
+	// than a Go map on large caches.
+	SwissMap
+)
+
+// WithMapBackend sets the map indexing the entries, BuiltinMap by default.
+// hash is used by SwissMap, if nil the keys are hashed with hash/maphash,
+// which is fast for strings and integers only.
+func WithMapBackend[K comparable, V any](backend MapBackend, hash func(key K) uint64) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		switch backend {
+		case SwissMap:
+			if hash == nil {
+				hash = defaultHash[K]()
+			}
+			c.bucket = newSwissMap[K, *list.Element[*entry[K, V]]](hash)
+		default:
+			c.bucket = make(builtinMap[K, *list.Element[*entry[K, V]]])
+		}
+	}
+}
+
+// bucketMap indexes the list elements of a cache by key.
+type bucketMap[K comparable, E any] interface {
+	get(key K) (e E, ok bool)
+	set(key K, e E)
+	// insert sets the key only if it is missing.
+	insert(key K, e E) (ok bool)
+	delete(key K)
+	// empty returns an empty map of the same kind.
+	empty() bucketMap[K, E]
+}
+
+// builtinMap is a bucketMap backed by a Go map.
+type builtinMap[K comparable, E any] map[K]E
+
+func (m builtinMap[K, E]) get(key K) (e E, ok bool) {
+	e, ok = m[key]
+	return e, ok
+}
+
+func (m builtinMap[K, E]) set(key K, e E) {
+	m[key] = e
+}
+
+func (m builtinMap[K, E]) insert(key K, e E) (ok bool) {
+	if _, ok = m[key]; ok {
+		return false
+	}
+	m[key] = e
+	return true
+}
+
+func (m builtinMap[K, E]) delete(key K) {
+	delete(m, key)
+}
+
+func (m builtinMap[K, E]) empty() bucketMap[K, E] {
+	return make(builtinMap[K, E])
+}
+
+const (
+	// swissGroupSize is the number of slots of a group, matching the
+	// number of control bytes of an uint64.
+	swissGroupSize = 8
+	// swissMaxLoad is the maximum number of used slots per group.
+	swissMaxLoad = 7
+
+	// Control bytes, a full slot stores the low 7 bits of its hash
+	swissEmpty   = 0x80
+	swissDeleted = 0xFE
+
+	swissLSB = 0x0101010101010101
+	swissMSB = 0x8080808080808080
+)
+
+// swissGroup holds a group of slots and their control bytes.
+type swissGroup[K comparable, E any] struct {
+	ctrl  uint64
+	keys  [swissGroupSize]K
+	elems [swissGroupSize]E
+}
+
+// swissMap is an open-addressing bucketMap. Slots are probed a group at
+// a time, comparing the 7 low bits of the hash of the 8 slots at once
+// before comparing any key.
+type swissMap[K comparable, E any] struct {
+	hash   func(key K) uint64
+	groups []swissGroup[K, E]
+	// used counts the full and deleted slots
+	used, deleted int
+}
+
+func newSwissMap[K comparable, E any](hash func(key K) uint64) *swissMap[K, E] {
+	m := &swissMap[K, E]{hash: hash}
+	m.alloc(1)
+	return m
+}
+
+func (m *swissMap[K, E]) alloc(groups int) {
+	m.groups = make([]swissGroup[K, E], groups)
+	for i := range m.groups {
+		m.groups[i].ctrl = swissLSB * swissEmpty
+	}
+	m.used, m.deleted = 0, 0
+}
+
+// find returns the group and slot of the key.
+func (m *swissMap[K, E]) find(key K, h uint64) (g *swissGroup[K, E], slot int, ok bool) {
+	mask := uint64(len(m.groups) - 1)
+	i := (h >> 7) & mask
+	for step := uint64(1); step <= uint64(len(m.groups)); step++ {
+		g = &m.groups[i]
+		for match := swissMatch(g.ctrl, uint8(h&0x7f)); match != 0; match &= match - 1 {
+			slot = bits.TrailingZeros64(match) / 8
+			if g.keys[slot] == key {
+				return g, slot, true
+			}
+		}
+		if swissMatchEmpty(g.ctrl) != 0 {
+			return nil, 0, false
+		}
+		// Triangular probing visits every group of a power of two table
+		i = (i + step) & mask
+	}
+	return nil, 0, false
+}
+
+func (m *swissMap[K, E]) get(key K) (e E, ok bool) {
+	g, slot, ok := m.find(key, m.hash(key))
+	if !ok {
+		return e, false
+	}
+	return g.elems[slot], true
+}
+
+func (m *swissMap[K, E]) set(key K, e E) {
+	h := m.hash(key)
+	if g, slot, ok := m.find(key, h); ok {
+		g.elems[slot] = e
+		return
+	}
+	m.place(key, e, h)
+}
+
+func (m *swissMap[K, E]) insert(key K, e E) (ok bool) {
+	h := m.hash(key)
+	if _, _, found := m.find(key, h); found {
+		return false
+	}
+	m.place(key, e, h)
+	return true
+}
+
+// place stores a missing key in the first free slot of its probe sequence.
+func (m *swissMap[K, E]) place(key K, e E, h uint64) {
+	if m.used >= len(m.groups)*swissMaxLoad {
+		m.rehash()
+	}
+
+	mask := uint64(len(m.groups) - 1)
+	i := (h >> 7) & mask
+	for step := uint64(1); ; step++ {
+		g := &m.groups[i]
+		if match := g.ctrl & swissMSB; match != 0 {
+			slot := bits.TrailingZeros64(match) / 8
+			if swissCtrl(g.ctrl, slot) == swissDeleted {
+				m.deleted--
+			} else {
+				m.used++
+			}
+			g.ctrl = swissSetCtrl(g.ctrl, slot, uint8(h&0x7f))
+			g.keys[slot], g.elems[slot] = key, e
+			return
+		}
+		i = (i + step) & mask
+	}
+}
+
+func (m *swissMap[K, E]) delete(key K) {
+	g, slot, ok := m.find(key, m.hash(key))
+	if !ok {
+		return
+	}
+	var (
+		zeroK K
+		zeroE E
+	)
+	g.keys[slot], g.elems[slot] = zeroK, zeroE
+	// A group with an empty slot ends the probes, so the slot can be
+	// emptied, otherwise it is marked deleted to keep probing past it
+	if swissMatchEmpty(g.ctrl) != 0 {
+		g.ctrl = swissSetCtrl(g.ctrl, slot, swissEmpty)
+		m.used--
+		return
+	}
+	g.ctrl = swissSetCtrl(g.ctrl, slot, swissDeleted)
+	m.deleted++
+}
+
+func (m *swissMap[K, E]) empty() bucketMap[K, E] {
+	return newSwissMap[K, E](m.hash)
+}
+
+// rehash grows the table, or only drops the deleted slots if
+// they make up most of the used ones.
+func (m *swissMap[K, E]) rehash() {
+	groups := len(m.groups)
+	if m.deleted < m.used/2 {
+		groups *= 2
+	}
+	old := m.groups
+	m.alloc(groups)
+	for gi := range old {
+		g := &old[gi]
+		for slot := 0; slot < swissGroupSize; slot++ {
+			if swissCtrl(g.ctrl, slot)&swissEmpty == 0 {
+				m.place(g.keys[slot], g.elems[slot], m.hash(g.keys[slot]))
+			}
+		}
+	}
+}
+
+// swissMatch returns the mask of the control bytes equal to h2, with some
+// rare false positives which are ruled out by comparing the keys.
+func swissMatch(ctrl uint64, h2 uint8) uint64 {
+	x := ctrl ^ (swissLSB * uint64(h2))
+	return (x - swissLSB) &^ x & swissMSB
+}
+
+// swissMatchEmpty returns the mask of the empty control bytes.
+func swissMatchEmpty(ctrl uint64) uint64 {
+	return ctrl &^ (ctrl << 6) & swissMSB
+}
+
+func swissCtrl(ctrl uint64, slot int) uint8 {
+	return uint8(ctrl >> (slot * 8))
+}
+
+func swissSetCtrl(ctrl uint64, slot int, b uint8) uint64 {
+	shift := slot * 8
+	return ctrl&^(0xff<<shift) | uint64(b)<<shift
+}
+
+// defaultHash returns a seeded hash function, specialized
+// for the strings and integers.
+func defaultHash[K comparable]() func(key K) uint64 {
+	seed := maphash.MakeSeed()
+	salt := maphash.String(seed, "")
+	return func(key K) uint64 {
+		switch k := any(key).(type) {
+		case string:
+			return maphash.String(seed, k)
+		case int:
+			return mix64(uint64(k) ^ salt)
+		case int64:
+			return mix64(uint64(k) ^ salt)
+		case int32:
+			return mix64(uint64(k) ^ salt)
+		case uint:
+			return mix64(uint64(k) ^ salt)
+		case uint64:
+			return mix64(k ^ salt)
+		case uint32:
+			return mix64(uint64(k) ^ salt)
+		}
+		return maphash.String(seed, fmt.Sprint(key))
+	}
+}
+
+// mix64 is the finalizer of splitmix64.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,81 @@
+package lru
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func Test_swissMap(t *testing.T) {
+	// A weak hash makes the probes collide
+	m := newSwissMap[int, int](func(k int) uint64 { return uint64(k % 64) })
+	ref := make(map[int]int)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		k := r.Intn(2000)
+		switch r.Intn(3) {
+		case 0, 1:
+			m.set(k, i)
+			ref[k] = i
+		case 2:
+			m.delete(k)
+			delete(ref, k)
+		}
+	}
+	for k := 0; k < 2000; k++ {
+		v, ok := m.get(k)
+		if rv, rok := ref[k]; v != rv || ok != rok {
+			t.Fatalf("key %v: expected %v, %v, got %v, %v", k, rv, rok, v, ok)
+		}
+	}
+	if live := m.used - m.deleted; live != len(ref) {
+		t.Fatalf("Expected %v, got %v", len(ref), live)
+	}
+
+	// The zero key is not confused with the empty slots
+	e := newSwissMap[int, int](defaultHash[int]())
+	if _, ok := e.get(0); ok {
+		t.Fatal("zero key should be missing")
+	}
+}
+
+func TestLru_WithMapBackend(t *testing.T) {
+	l := New[string, int](1000, WithMapBackend[string, int](SwissMap, nil))
+	for i := 0; i < 5000; i++ {
+		l.Add(strconv.Itoa(i), i)
+	}
+	if l.Len() != 1000 {
+		t.Fatalf("Expected %v, got %v", 1000, l.Len())
+	}
+	for i := 0; i < 5000; i++ {
+		v, ok := l.Get(strconv.Itoa(i))
+		if ok != (i >= 4000) || (ok && v != i) {
+			t.Fatalf("key %v: got %v, %v", i, v, ok)
+		}
+	}
+	l.Remove("4500")
+	l.Clear()
+	l.Add("a", 1)
+	if v, ok := l.Get("a"); !ok || v != 1 || l.Contains("4001") {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, true, v, ok)
+	}
+
+	type point struct{ x, y int }
+	p := New[point, int](10, WithMapBackend[point, int](SwissMap, nil))
+	p.Add(point{1, 2}, 3)
+	if v, ok := p.Get(point{1, 2}); !ok || v != 3 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 3, true, v, ok)
+	}
+}
+
+func BenchmarkUnsafeLru_GetSwissMap(b *testing.B) {
+	c := NewUnsafeLru[int, int](1<<16, WithMapBackend[int, int](SwissMap, nil))
+	for i := 0; i < 1<<16; i++ {
+		c.Add(i, i)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Get(i & (1<<16 - 1))
+	}
+}
@@ -0,0 +1,95 @@
+// Package testsupport helps testing code using the caches of package lru
+// without sleeping, with a fake clock and assertions on the entries:
+//
+//	clock := testsupport.NewClock(time.Unix(0, 0))
+//	c := lru.New[string, int](10, lru.WithTTL[string, int](time.Minute), testsupport.WithClock[string, int](clock))
+//	clock.Track(c)
+//	c.Add("a", 1)
+//	clock.Advance(time.Minute)
+//	testsupport.RequireExpired[string](t, c, "a")
+package testsupport
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/electricbubble/lru"
+)
+
+// Sweeper is a cache whose expired entries can be removed.
+type Sweeper interface {
+	RemoveExpired() (removed int)
+}
+
+// Clock is a fake clock, only moved by Advance. It is safe for
+// concurrent access.
+type Clock struct {
+	mu       sync.Mutex
+	t        time.Time
+	sweepers []Sweeper
+}
+
+// NewClock creates a clock set to start.
+func NewClock(start time.Time) *Clock {
+	return &Clock{t: start}
+}
+
+// Now returns the time of the clock.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.t
+}
+
+// Track makes Advance remove the expired entries of the caches, standing
+// for their janitor deterministically. The caches should use the clock,
+// and no WithJanitor of their own.
+func (c *Clock) Track(caches ...Sweeper) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sweepers = append(c.sweepers, caches...)
+}
+
+// Advance moves the clock forward by d, then removes the expired entries
+// of the tracked caches, returning how many were removed.
+func (c *Clock) Advance(d time.Duration) (removed int) {
+	c.mu.Lock()
+	c.t = c.t.Add(d)
+	sweepers := c.sweepers
+	c.mu.Unlock()
+
+	for _, s := range sweepers {
+		removed += s.RemoveExpired()
+	}
+	return removed
+}
+
+// WithClock makes a cache use the clock for its expirations.
+func WithClock[K comparable, V any](clock *Clock) lru.Option[K, V] {
+	return lru.WithClock[K, V](clock.Now)
+}
+
+// Container is a cache whose keys can be checked.
+type Container[K comparable] interface {
+	Contains(key K) (ok bool)
+}
+
+// RequireResident fails the test unless the key is in the cache and fresh.
+func RequireResident[K comparable](t testing.TB, c Container[K], key K) {
+	t.Helper()
+	if !c.Contains(key) {
+		t.Fatalf("lru: %v should be resident", key)
+	}
+}
+
+// RequireExpired fails the test if the key is in the cache and fresh,
+// whether it expired, was evicted or was never added.
+func RequireExpired[K comparable](t testing.TB, c Container[K], key K) {
+	t.Helper()
+	if c.Contains(key) {
+		t.Fatalf("lru: %v should be expired", key)
+	}
+}
@@ -0,0 +1,62 @@
+package testsupport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/electricbubble/lru"
+)
+
+func TestClock(t *testing.T) {
+	clock := NewClock(time.Unix(0, 0))
+	c := lru.New[string, int](10, lru.WithTTL[string, int](time.Minute), WithClock[string, int](clock))
+	defer c.Close()
+	clock.Track(c)
+
+	c.Add("a", 1)
+	clock.Advance(30 * time.Second)
+	c.Add("b", 2)
+	RequireResident[string](t, c, "a")
+
+	if removed := clock.Advance(30 * time.Second); removed != 1 {
+		t.Fatalf("Expected %v, got %v", 1, removed)
+	}
+	RequireExpired[string](t, c, "a")
+	RequireResident[string](t, c, "b")
+	// The janitor removed the entry, not the lookup
+	if c.Len() != 1 {
+		t.Fatalf("Expected %v, got %v", 1, c.Len())
+	}
+
+	if now := clock.Now(); !now.Equal(time.Unix(60, 0)) {
+		t.Fatalf("Expected %v, got %v", time.Unix(60, 0), now)
+	}
+}
+
+type recordingTB struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Fatalf(format string, args ...any) {
+	r.failed = true
+}
+
+func TestRequire(t *testing.T) {
+	c := lru.New[string, int](10)
+	c.Add("a", 1)
+
+	tb := &recordingTB{TB: t}
+	RequireExpired[string](tb, c, "a")
+	if !tb.failed {
+		t.Fatal("RequireExpired should fail for a resident key")
+	}
+
+	tb = &recordingTB{TB: t}
+	RequireResident[string](tb, c, "b")
+	if !tb.failed {
+		t.Fatal("RequireResident should fail for a missing key")
+	}
+}
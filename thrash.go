@@ -0,0 +1,117 @@
+package lru
+
+import "math"
+
+// ThrashReport describes a window of insertions in which too many of the
+// evicted keys were added back, the sign of a cache too small for its
+// working set.
+type ThrashReport struct {
+	// Evictions is the number of entries evicted during the window.
+	Evictions int
+	// Readmissions is the number of keys added back within the window
+	// of insertions following their eviction.
+	Readmissions int
+	// Capacity is the current entry limit of the cache.
+	Capacity int
+	// SuggestedCapacity is the entry limit which would have kept the
+	// readmitted keys, at most math.MaxInt for the caches without one.
+	SuggestedCapacity int
+}
+
+// Ratio returns the fraction of the evictions which were readmitted.
+func (r ThrashReport) Ratio() float64 {
+	if r.Evictions == 0 {
+		return 0
+	}
+	return float64(r.Readmissions) / float64(r.Evictions)
+}
+
+// WithThrashDetector reports, after every window insertions, whether the
+// fraction of the evicted keys added back within window insertions reached
+// threshold, which usually means that the cache is too small. onThrash is
+// called synchronously with the lock held, so it must not use the cache.
+func WithThrashDetector[K comparable, V any](window int, threshold float64, onThrash func(r ThrashReport)) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		if window <= 0 || onThrash == nil {
+			c.thrash = nil
+			return
+		}
+		c.thrash = &thrashDetector[K]{
+			window:    uint64(window),
+			threshold: threshold,
+			onThrash:  onThrash,
+			evictedAt: make(map[K]uint64),
+		}
+	}
+}
+
+// thrashDetector remembers the keys evicted during the last window
+// insertions.
+type thrashDetector[K comparable] struct {
+	window    uint64
+	threshold float64
+	onThrash  func(r ThrashReport)
+
+	// inserts counts the insertions, which date the evictions
+	inserts   uint64
+	evictedAt map[K]uint64
+	evicted   []evictedKey[K]
+
+	evictions, readmissions int
+}
+
+type evictedKey[K comparable] struct {
+	key K
+	at  uint64
+}
+
+// evict records the eviction of a key.
+func (d *thrashDetector[K]) evict(key K) {
+	d.evictedAt[key] = d.inserts
+	d.evicted = append(d.evicted, evictedKey[K]{key, d.inserts})
+	d.evictions++
+}
+
+// insert records the insertion of a key, and reports the window
+// when it is over.
+func (d *thrashDetector[K]) insert(key K, capacity int) {
+	d.inserts++
+	if _, ok := d.evictedAt[key]; ok {
+		delete(d.evictedAt, key)
+		d.readmissions++
+	}
+
+	// Forget the evictions older than the window
+	i := 0
+	for ; i < len(d.evicted) && d.evicted[i].at+d.window < d.inserts; i++ {
+		if at, ok := d.evictedAt[d.evicted[i].key]; ok && at == d.evicted[i].at {
+			delete(d.evictedAt, d.evicted[i].key)
+		}
+	}
+	if i > 0 {
+		d.evicted = append(d.evicted[:0], d.evicted[i:]...)
+	}
+
+	if d.inserts%d.window != 0 {
+		return
+	}
+	r := ThrashReport{
+		Evictions:         d.evictions,
+		Readmissions:      d.readmissions,
+		Capacity:          capacity,
+		SuggestedCapacity: math.MaxInt,
+	}
+	if capacity <= math.MaxInt-d.readmissions {
+		r.SuggestedCapacity = capacity + d.readmissions
+	}
+	d.evictions, d.readmissions = 0, 0
+	if r.Evictions > 0 && r.Ratio() >= d.threshold {
+		d.onThrash(r)
+	}
+}
+
+// clear forgets the evictions.
+func (d *thrashDetector[K]) clear() {
+	d.evictedAt = make(map[K]uint64)
+	d.evicted = nil
+}
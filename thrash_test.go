@@ -0,0 +1,66 @@
+package lru
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLru_WithThrashDetector(t *testing.T) {
+	var reports []ThrashReport
+	l := New[int, int](2, WithThrashDetector[int, int](10, 0.5, func(r ThrashReport) {
+		reports = append(reports, r)
+	}))
+
+	// Unique keys are evicted, but never added back
+	for i := 100; i < 120; i++ {
+		l.Add(i, i)
+	}
+	if len(reports) != 0 {
+		t.Fatalf("Expected %v, got %v", 0, reports)
+	}
+
+	// Cycling over 3 keys in 2 entries evicts each key before its next Add
+	for i := 0; i < 10; i++ {
+		l.Add(i%3, i)
+	}
+	if len(reports) != 1 {
+		t.Fatalf("Expected %v, got %v", 1, len(reports))
+	}
+	r := reports[0]
+	if r.Evictions != 10 || r.Readmissions != 7 || r.Capacity != 2 || r.SuggestedCapacity != 9 {
+		t.Fatalf("Expected %v, got %v", ThrashReport{10, 7, 2, 9}, r)
+	}
+	if r.Ratio() != 0.7 {
+		t.Fatalf("Expected %v, got %v", 0.7, r.Ratio())
+	}
+
+	// The evictions older than the window are forgotten
+	l.Clear()
+	for i := 0; i < 20; i++ {
+		l.Add(200+i, i)
+	}
+	l.Add(200, 0)
+	if d := l.lru.(*unsafeCache[int, int]).thrash; d.readmissions != 0 || len(d.evictedAt) > 11 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 0, 11, d.readmissions, len(d.evictedAt))
+	}
+}
+
+func TestLru_WithThrashDetectorUnlimited(t *testing.T) {
+	var reports []ThrashReport
+	l := New[int, int](0,
+		WithUnlimited[int, int](),
+		WithWeigher(func(k, v int) int64 { return 1 }, 2),
+		WithThrashDetector[int, int](10, 0.5, func(r ThrashReport) {
+			reports = append(reports, r)
+		}),
+	)
+	for i := 0; i < 10; i++ {
+		l.Add(i%3, i)
+	}
+	if len(reports) != 1 {
+		t.Fatalf("Expected %v, got %v", 1, len(reports))
+	}
+	if r := reports[0]; r.Capacity != math.MaxInt || r.SuggestedCapacity != math.MaxInt {
+		t.Fatalf("Expected %v, got %v", math.MaxInt, r.SuggestedCapacity)
+	}
+}
@@ -0,0 +1,115 @@
+package lru
+
+import "time"
+
+// Tombstone records the removal of a key.
+type Tombstone[K comparable] struct {
+	Key K
+	At  time.Time
+}
+
+// WithTombstones makes the removals of the Remove methods leave a
+// tombstone for ttl, queryable with Tombstones, so that replication
+// consumers can tell a deleted key apart from one that never existed.
+// At most max tombstones are kept, the oldest are dropped first. Adding
+// the key again drops its tombstone. Evictions and expirations leave
+// none.
+func WithTombstones[K comparable, V any](ttl time.Duration, max int) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		if ttl <= 0 || max <= 0 {
+			c.tombstones = nil
+			return
+		}
+		c.tombstones = &tombstones[K]{
+			ttl: ttl,
+			max: max,
+			seq: make(map[K]uint64),
+		}
+	}
+}
+
+// tombstoneQueueSlack is the number of queued tombstones tolerated per
+// live one before the superseded and forgotten ones are compacted.
+const tombstoneQueueSlack = 2
+
+// tombstones are the recent removals, oldest first in the queue.
+type tombstones[K comparable] struct {
+	ttl time.Duration
+	max int
+
+	// seq maps the keys to the sequence number of their live tombstone,
+	// telling it apart from the superseded ones even in the same tick.
+	seq   map[K]uint64
+	next  uint64
+	queue []queuedTombstone[K]
+}
+
+// queuedTombstone is a tombstone with its sequence number.
+type queuedTombstone[K comparable] struct {
+	Tombstone[K]
+	seq uint64
+}
+
+func (t *tombstones[K]) add(key K, now time.Time) {
+	t.next++
+	t.seq[key] = t.next
+	t.queue = append(t.queue, queuedTombstone[K]{Tombstone[K]{key, now}, t.next})
+	t.prune(now)
+}
+
+// forget drops the tombstone of a key added again.
+func (t *tombstones[K]) forget(key K) {
+	delete(t.seq, key)
+}
+
+// prune drops the expired tombstones, and the oldest beyond max.
+func (t *tombstones[K]) prune(now time.Time) {
+	i := 0
+	for ; i < len(t.queue); i++ {
+		ts := t.queue[i]
+		if !t.live(ts) {
+			// Superseded by a later removal, or forgotten
+			continue
+		}
+		if len(t.seq) <= t.max && now.Sub(ts.At) < t.ttl {
+			break
+		}
+		delete(t.seq, ts.Key)
+	}
+	if i > 0 {
+		t.queue = append(t.queue[:0], t.queue[i:]...)
+	}
+
+	// The keys removed and added again behind a live tombstone
+	// are dropped in bulk
+	if len(t.queue) > tombstoneQueueSlack*len(t.seq) {
+		live := t.queue[:0]
+		for _, ts := range t.queue {
+			if t.live(ts) {
+				live = append(live, ts)
+			}
+		}
+		t.queue = live
+	}
+}
+
+// live reports whether a queued tombstone is the current one of its key.
+func (t *tombstones[K]) live(ts queuedTombstone[K]) bool {
+	seq, ok := t.seq[ts.Key]
+	return ok && seq == ts.seq
+}
+
+func (c *unsafeCache[K, V]) Tombstones() []Tombstone[K] {
+	if c.tombstones == nil {
+		return nil
+	}
+	t := c.tombstones
+	t.prune(c.now())
+	live := make([]Tombstone[K], 0, len(t.seq))
+	for _, ts := range t.queue {
+		if t.live(ts) {
+			live = append(live, ts.Tombstone)
+		}
+	}
+	return live
+}
@@ -0,0 +1,113 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func tombstoneKeys(ts []Tombstone[string]) []string {
+	keys := make([]string, len(ts))
+	for i, t := range ts {
+		keys[i] = t.Key
+	}
+	return keys
+}
+
+func Test_unsafeCache_WithTombstones(t *testing.T) {
+	c, clock := newTTLCache(0, WithTombstones[string, int](time.Minute, 3))
+	for _, k := range []string{"a", "b", "c", "d", "e"} {
+		c.Add(k, 0)
+	}
+
+	c.Remove("a")
+	clock.advance(30 * time.Second)
+	c.RemoveGet("b")
+	c.Pop("missing")
+	if ts := c.Tombstones(); !reflect.DeepEqual(tombstoneKeys(ts), []string{"a", "b"}) || !ts[0].At.Equal(time.Unix(0, 0)) {
+		t.Fatalf("Expected %v, got %v", []string{"a", "b"}, ts)
+	}
+
+	// Adding the key again drops its tombstone
+	c.Add("a", 1)
+	if keys := tombstoneKeys(c.Tombstones()); !reflect.DeepEqual(keys, []string{"b"}) {
+		t.Fatalf("Expected %v, got %v", []string{"b"}, keys)
+	}
+
+	// The oldest tombstones beyond max are dropped
+	c.RemoveAll([]string{"c", "d", "e"})
+	if keys := tombstoneKeys(c.Tombstones()); !reflect.DeepEqual(keys, []string{"c", "d", "e"}) {
+		t.Fatalf("Expected %v, got %v", []string{"c", "d", "e"}, keys)
+	}
+
+	// So are the ones older than ttl
+	clock.advance(30 * time.Second)
+	c.Remove("a")
+	clock.advance(30 * time.Second)
+	if keys := tombstoneKeys(c.Tombstones()); !reflect.DeepEqual(keys, []string{"a"}) {
+		t.Fatalf("Expected %v, got %v", []string{"a"}, keys)
+	}
+}
+
+func Test_unsafeCache_WithTombstonesEvictions(t *testing.T) {
+	c := NewUnsafeLru[string, int](1, WithTombstones[string, int](time.Minute, 10))
+	c.Add("a", 1)
+	c.Add("b", 2)
+	if ts := c.Tombstones(); len(ts) != 0 {
+		t.Fatalf("Expected %v, got %v", 0, ts)
+	}
+
+	c = NewUnsafeLru[string, int](1)
+	c.Add("a", 1)
+	c.Remove("a")
+	if ts := c.Tombstones(); ts != nil {
+		t.Fatalf("Expected %v, got %v", nil, ts)
+	}
+}
+
+func Test_unsafeCache_WithTombstonesChurn(t *testing.T) {
+	c, clock := newTTLCache(0, WithTombstones[string, int](time.Hour, 100))
+	c.Add("pinned", 0)
+	c.Remove("pinned")
+
+	// The live tombstone at the head does not retain the churn behind it
+	for i := 0; i < 10000; i++ {
+		clock.advance(time.Millisecond)
+		c.Add("a", i)
+		c.Remove("a")
+		c.Add("b", i)
+		c.Remove("b")
+		c.Add("b", i)
+	}
+	if n := len(c.tombstones.queue); n > 10 {
+		t.Fatalf("Expected at most %v, got %v", 10, n)
+	}
+	if ts := c.Tombstones(); !reflect.DeepEqual(tombstoneKeys(ts), []string{"pinned", "a"}) {
+		t.Fatalf("Expected %v, got %v", []string{"pinned", "a"}, tombstoneKeys(ts))
+	}
+}
+
+func Test_unsafeCache_WithTombstonesSameTick(t *testing.T) {
+	c, _ := newTTLCache(0, WithTombstones[string, int](time.Hour, 10))
+	c.Add("a", 1)
+	c.Remove("a")
+	c.Add("a", 2)
+	c.Remove("a")
+	if ts := c.Tombstones(); !reflect.DeepEqual(tombstoneKeys(ts), []string{"a"}) {
+		t.Fatalf("Expected %v, got %v", []string{"a"}, tombstoneKeys(ts))
+	}
+}
+
+func TestARC_TombstonesPromotion(t *testing.T) {
+	c := NewARC[string, int](4, WithTombstones[string, int](time.Hour, 10), WithEvictionHistory[string, int](4, false))
+	c.Add("a", 1)
+	c.Get("a")
+	c.Add("b", 2)
+	c.Add("b", 3)
+	if ts := c.t1.Tombstones(); len(ts) != 0 {
+		t.Fatalf("Expected %v, got %v", 0, ts)
+	}
+	if evictions := c.t1.RecentEvictions(4); len(evictions) != 0 {
+		t.Fatalf("Expected %v, got %v", 0, evictions)
+	}
+}
@@ -0,0 +1,108 @@
+package lru
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"time"
+)
+
+// traceRecordSize is the size of an encoded TraceRecord.
+const traceRecordSize = 18
+
+// TraceOp is the operation of a TraceRecord.
+type TraceOp uint8
+
+const (
+	TraceGet TraceOp = iota
+	TraceAdd
+	TraceRemove
+)
+
+func (op TraceOp) String() string {
+	switch op {
+	case TraceGet:
+		return "get"
+	case TraceAdd:
+		return "add"
+	case TraceRemove:
+		return "remove"
+	}
+	return fmt.Sprintf("TraceOp(%d)", uint8(op))
+}
+
+// TraceRecord is an operation recorded WithTraceRecorder.
+type TraceRecord struct {
+	Op      TraceOp
+	Hit     bool // The key was cached
+	KeyHash uint64
+	At      time.Time
+}
+
+// WithTraceRecorder appends a compact record of every Get, Add and Remove
+// to w, for offline analysis such as capacity planning. Records are sampled
+// by key, keeping one key in sampleEvery with all its operations, so that
+// the trace keeps the reuse patterns. The records are written under the
+// lock of the cache, so w should be buffered. Recording stops at the first
+// write error. Read the records with ReadTrace.
+func WithTraceRecorder[K comparable, V any](w io.Writer, sampleEvery int) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		if sampleEvery <= 0 {
+			sampleEvery = 1
+		}
+		c.trace = &traceRecorder{w: w, sampleEvery: uint64(sampleEvery)}
+	}
+}
+
+// traceRecorder encodes the trace records.
+type traceRecorder struct {
+	w           io.Writer
+	sampleEvery uint64
+	buf         [traceRecordSize]byte
+	err         error
+}
+
+func (c *unsafeCache[K, V]) record(op TraceOp, key K, hit bool) {
+	t := c.trace
+	if t.err != nil {
+		return
+	}
+	h := fnv.New64a()
+	_, _ = fmt.Fprint(h, key)
+	hash := h.Sum64()
+	if hash%t.sampleEvery != 0 {
+		return
+	}
+
+	t.buf[0] = byte(op)
+	t.buf[1] = 0
+	if hit {
+		t.buf[1] = 1
+	}
+	binary.LittleEndian.PutUint64(t.buf[2:], hash)
+	binary.LittleEndian.PutUint64(t.buf[10:], uint64(c.now().UnixNano()))
+	_, t.err = t.w.Write(t.buf[:])
+}
+
+// ReadTrace reads the records written WithTraceRecorder, calling fn for each
+// of them until the end of r.
+func ReadTrace(r io.Reader, fn func(record TraceRecord)) error {
+	var buf [traceRecordSize]byte
+	for {
+		_, err := io.ReadFull(r, buf[:])
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("lru: read trace: %w", err)
+		}
+		fn(TraceRecord{
+			Op:      TraceOp(buf[0]),
+			Hit:     buf[1] == 1,
+			KeyHash: binary.LittleEndian.Uint64(buf[2:]),
+			At:      time.Unix(0, int64(binary.LittleEndian.Uint64(buf[10:]))),
+		})
+	}
+}
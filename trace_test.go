@@ -0,0 +1,72 @@
+package lru
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestLru_WithTraceRecorder(t *testing.T) {
+	var buf bytes.Buffer
+	l := New[string, int](128, WithTraceRecorder[string, int](&buf, 1))
+	l.Get("a")
+	l.Add("a", 1)
+	l.Add("a", 2)
+	l.Get("a")
+	l.Remove("a")
+
+	var records []TraceRecord
+	if err := ReadTrace(&buf, func(r TraceRecord) { records = append(records, r) }); err != nil {
+		t.Fatal(err)
+	}
+	expected := []struct {
+		op  TraceOp
+		hit bool
+	}{{TraceGet, false}, {TraceAdd, false}, {TraceAdd, true}, {TraceGet, true}, {TraceRemove, true}}
+	if len(records) != len(expected) {
+		t.Fatalf("Expected %v records, got %v", len(expected), len(records))
+	}
+	for i, e := range expected {
+		r := records[i]
+		if r.Op != e.op || r.Hit != e.hit || r.KeyHash != records[0].KeyHash || r.At.IsZero() {
+			t.Fatalf("bad record %v: %+v", i, r)
+		}
+	}
+	if TraceRemove.String() != "remove" {
+		t.Fatalf("bad op: %v", TraceRemove)
+	}
+
+	if err := ReadTrace(bytes.NewReader(make([]byte, traceRecordSize+1)), func(TraceRecord) {}); err == nil {
+		t.Fatal("truncated trace should fail")
+	}
+}
+
+func TestLru_WithTraceRecorder_Sampling(t *testing.T) {
+	var buf bytes.Buffer
+	l := New[int, int](128, WithTraceRecorder[int, int](&buf, 4))
+	for i := 0; i < 1000; i++ {
+		l.Add(i, i)
+		l.Get(i)
+	}
+
+	keys := make(map[uint64]int)
+	ReadTrace(&buf, func(r TraceRecord) { keys[r.KeyHash]++ })
+	// Around 250 keys, each with all its operations
+	if len(keys) < 150 || len(keys) > 350 {
+		t.Fatalf("bad sampling: %v keys", len(keys))
+	}
+	for _, n := range keys {
+		if n != 2 {
+			t.Fatalf("Expected %v, got %v", 2, n)
+		}
+	}
+}
+
+func TestLru_WithTraceRecorder_WriteError(t *testing.T) {
+	l := NewUnsafeLru[int, int](128, WithTraceRecorder[int, int](failingWriter{}, 1)).(*unsafeCache[int, int])
+	l.Add(1, 1)
+	l.Add(2, 2)
+	if !errors.Is(l.trace.err, errWrite) {
+		t.Fatalf("Expected %v, got %v", errWrite, l.trace.err)
+	}
+}
@@ -0,0 +1,30 @@
+package lru
+
+// WithValueTransform transforms the values written by the Add methods, Put and
+// Handle.SetValue with store, and every value returned to the callers,
+// passed to the callbacks or published in the events with load, e.g. to
+// normalize, compress or defensively copy them in one place. Either can be
+// nil. Only the weigher, the validator and the eviction filter see the
+// stored values.
+func WithValueTransform[K comparable, V any](store, load func(value V) V) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		c.storeTransform = store
+		c.loadTransform = load
+	}
+}
+
+// storeValue returns the form of a value held by the cache.
+func (c *unsafeCache[K, V]) storeValue(value V) V {
+	if c.storeTransform == nil {
+		return value
+	}
+	return c.storeTransform(value)
+}
+
+// loadValue returns the form of a value returned to the callers.
+func (c *unsafeCache[K, V]) loadValue(value V) V {
+	if c.loadTransform == nil {
+		return value
+	}
+	return c.loadTransform(value)
+}
@@ -0,0 +1,52 @@
+package lru
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLru_WithValueTransform(t *testing.T) {
+	var loads int
+	l := New[string, string](10, WithValueTransform[string, string](strings.TrimSpace, func(v string) string {
+		loads++
+		return strings.ToUpper(v)
+	}))
+	l.Add("a", "  hello ")
+	l.AddNew("b", " world")
+
+	if items := l.Items(); items[0].Value != "HELLO" || items[1].Value != "WORLD" {
+		t.Fatalf("Expected %v, got %v", []string{"HELLO", "WORLD"}, items)
+	}
+	if v, ok := l.Get("a"); !ok || v != "HELLO" {
+		t.Fatalf("Expected %v, got %v", "HELLO", v)
+	}
+	if v, ok := l.Peek("b"); !ok || v != "WORLD" {
+		t.Fatalf("Expected %v, got %v", "WORLD", v)
+	}
+	if e, ok := l.PeekEntry("b"); !ok || e.Value != "WORLD" {
+		t.Fatalf("Expected %v, got %v", "WORLD", e.Value)
+	}
+	if loads != 5 {
+		t.Fatalf("Expected %v, got %v", 5, loads)
+	}
+
+	h, _ := l.GetHandle("a")
+	h.SetValue(" bye ")
+	if v, _ := h.Value(); v != "BYE" {
+		t.Fatalf("Expected %v, got %v", "BYE", v)
+	}
+	if v, ok := l.RemoveGet("a"); !ok || v != "BYE" {
+		t.Fatalf("Expected %v, got %v", "BYE", v)
+	}
+
+	if _, v, ok := l.RemoveOldest(); !ok || v != "WORLD" {
+		t.Fatalf("Expected %v, got %v", "WORLD", v)
+	}
+
+	// Either transform is optional
+	l2 := New[string, string](10, WithValueTransform[string, string](nil, strings.ToUpper))
+	l2.Add("a", " x ")
+	if v, _ := l2.Get("a"); v != " X " {
+		t.Fatalf("Expected %v, got %v", " X ", v)
+	}
+}
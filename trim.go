@@ -0,0 +1,52 @@
+package lru
+
+// WithBackgroundTrim takes evictions, and their callbacks, off the path of
+// Add: an Add going over the limits only flags the cache, and the oldest
+// entries are evicted later by Trim. The Cache returned by New runs Trim on
+// a background goroutine until Close, while the caller of NewUnsafeLru has
+// to call Trim itself. The cache can temporarily exceed its limits.
+func WithBackgroundTrim[K comparable, V any]() Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		c.trimSignal = make(chan struct{}, 1)
+	}
+}
+
+func (c *unsafeCache[K, V]) Trim() (evicted int) {
+	for c.entries.Len() > c.maxEntries {
+		c.removeOldest()
+		evicted++
+	}
+	if c.weigher != nil {
+		evicted += c.evictOverCost()
+	}
+	return evicted
+}
+
+// requestTrim notifies the trimmer if the cache is over its limits.
+func (c *unsafeCache[K, V]) requestTrim() {
+	if c.paused {
+		return
+	}
+	if c.entries.Len() <= c.maxEntries && (c.weigher == nil || c.cost <= c.maxCost) {
+		return
+	}
+	select {
+	case c.trimSignal <- struct{}{}:
+	default:
+		// A trim is already pending
+	}
+}
+
+// backgroundTrim trims the cache when notified, until Close.
+func (c *Cache[K, V]) backgroundTrim(u *unsafeCache[K, V]) {
+	defer u.untrack()
+
+	for {
+		select {
+		case <-u.ctx.Done():
+			return
+		case <-u.trimSignal:
+			c.Trim()
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_unsafeCache_WithBackgroundTrim(t *testing.T) {
+	c := NewUnsafeLru[int, int](2, WithBackgroundTrim[int, int]())
+	for i := 0; i < 5; i++ {
+		if c.Add(i, i) {
+			t.Fatal("Add should not evict")
+		}
+	}
+	if c.Len() != 5 {
+		t.Fatalf("Expected %v, got %v", 5, c.Len())
+	}
+
+	if evicted := c.Trim(); evicted != 3 || c.Len() != 2 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 3, 2, evicted, c.Len())
+	}
+	if c.Contains(0) || !c.Contains(4) {
+		t.Fatal("should evict the oldest entries")
+	}
+}
+
+func TestCache_WithBackgroundTrim(t *testing.T) {
+	evicted := make(chan int, 10)
+	c := New[int, int](2, WithBackgroundTrim[int, int](), WithOnEvicted(func(k, v int) {
+		evicted <- k
+	}))
+	defer c.Close()
+
+	for i := 0; i < 3; i++ {
+		c.Add(i, i)
+	}
+
+	select {
+	case k := <-evicted:
+		if k != 0 {
+			t.Fatalf("Expected %v, got %v", 0, k)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("background trim did not run")
+	}
+	if c.Len() != 2 {
+		t.Fatalf("Expected %v, got %v", 2, c.Len())
+	}
+}
@@ -0,0 +1,106 @@
+package lru
+
+import "time"
+
+// NoExpiration is the time to live of AddWithTTL for the entries which do
+// not expire after being written, whatever the default of the cache. They
+// still expire after being idle WithTTI.
+const NoExpiration time.Duration = -1
+
+// WithTTL expires entries ttl after they were last added or updated.
+// Expired entries are no longer returned, and are removed lazily by the
+// lookups that find them.
+func WithTTL[K comparable, V any](ttl time.Duration) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		if ttl > 0 {
+			c.ttl = ttl
+		}
+	}
+}
+
+// WithTTI expires entries tti after they were last read with Get or
+// written, i.e. when they have been idle for too long. It can be combined
+// with WithTTL, the entry expires as soon as either of them fires.
+func WithTTI[K comparable, V any](tti time.Duration) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		if tti > 0 {
+			c.tti = tti
+		}
+	}
+}
+
+// WithOnExpired sets a callback executed for the entries removed because
+// they expired, instead of the WithOnEvicted callback which is then only
+// executed for the other removals.
+func WithOnExpired[K comparable, V any](onExpired func(key K, value V)) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		c.onExpired = onExpired
+	}
+}
+
+// WithOnExpiredBatch replaces the expiration callbacks by a single
+// invocation of onExpired per RemoveExpired sweep, with all the entries
+// it removed, so that they can be processed efficiently downstream.
+// The entries expired lazily by the lookups are delivered one per
+// invocation. It is executed synchronously, and not at all for the
+// sweeps which did not remove anything.
+func WithOnExpiredBatch[K comparable, V any](onExpired func(entries []Entry[K, V])) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		c.onExpiredBatch = onExpired
+	}
+}
+
+func (c *unsafeCache[K, V]) RemoveExpired() (removed int) {
+	var batch []Entry[K, V]
+	if c.onExpiredBatch != nil {
+		c.batch = &batch
+	}
+	for {
+		ent, ok := c.expiries.peek()
+		if !ok || !c.expired(ent) {
+			break
+		}
+		elem, _ := c.bucket.get(ent.key)
+		c.removeElement(elem, EventExpire)
+		removed++
+	}
+	c.batch = nil
+	if len(batch) > 0 {
+		c.onExpiredBatch(batch)
+	}
+	return removed
+}
+
+// touch restarts the time to live and to idle of an entry that was just written.
+func (c *unsafeCache[K, V]) touch(ent *entry[K, V], ttl time.Duration) {
+	ent.expiresAt, ent.writeExpiresAt = time.Time{}, time.Time{}
+	if c.softTTL > 0 {
+		ent.staleAt, ent.refreshing = c.now().Add(c.softTTL), false
+	}
+	if ttl > 0 || c.tti > 0 {
+		now := c.now()
+		if ttl > 0 {
+			ent.writeExpiresAt = now.Add(ttl)
+		}
+		ent.expiresAt = ent.writeExpiresAt
+		c.access(ent, now)
+	}
+	c.expiries.update(ent)
+}
+
+// access restarts the time to idle of an entry that was just read.
+func (c *unsafeCache[K, V]) access(ent *entry[K, V], now time.Time) {
+	if c.tti <= 0 {
+		return
+	}
+	ent.expiresAt = now.Add(c.tti)
+	if !ent.writeExpiresAt.IsZero() && ent.writeExpiresAt.Before(ent.expiresAt) {
+		ent.expiresAt = ent.writeExpiresAt
+	}
+	c.expiries.update(ent)
+}
+
+// expired reports whether the entry is stale.
+func (c *unsafeCache[K, V]) expired(ent *entry[K, V]) bool {
+	return !ent.expiresAt.IsZero() && !c.now().Before(ent.expiresAt)
+}
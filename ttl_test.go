@@ -0,0 +1,214 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock for the unsafeCache
+type fakeClock struct {
+	t time.Time
+}
+
+func (f *fakeClock) now() time.Time { return f.t }
+
+func (f *fakeClock) advance(d time.Duration) { f.t = f.t.Add(d) }
+
+func newTTLCache(ttl time.Duration, opts ...Option[string, int]) (*unsafeCache[string, int], *fakeClock) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	c := NewUnsafeLru[string, int](10, append(opts, WithTTL[string, int](ttl))...).(*unsafeCache[string, int])
+	c.now = clock.now
+	return c, clock
+}
+
+func Test_unsafeCache_WithTTL(t *testing.T) {
+	c, clock := newTTLCache(time.Minute)
+	c.Add("a", 1)
+
+	clock.advance(30 * time.Second)
+	c.Add("b", 2)
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, true, v, ok)
+	}
+
+	clock.advance(30 * time.Second)
+	if c.Contains("a") {
+		t.Fatal("should be expired")
+	}
+	if _, ok := c.Peek("a"); ok {
+		t.Fatal("should be expired")
+	}
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("should be expired")
+	}
+	if c.Len() != 1 {
+		t.Fatalf("Expected %v, got %v", 1, c.Len())
+	}
+
+	// Updating restarts the time to live
+	c.Add("b", 3)
+	clock.advance(45 * time.Second)
+	if v, ok := c.Get("b"); !ok || v != 3 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 3, true, v, ok)
+	}
+}
+
+func Test_unsafeCache_GetOk3(t *testing.T) {
+	c, clock := newTTLCache(time.Minute)
+	c.Add("a", 1)
+
+	if v, present, expired := c.GetOk3("a"); v != 1 || !present || expired {
+		t.Fatalf("Expected %v, %v, %v, got %v, %v, %v", 1, true, false, v, present, expired)
+	}
+
+	clock.advance(time.Minute)
+	if v, present, expired := c.GetOk3("a"); v != 1 || present || !expired {
+		t.Fatalf("Expected %v, %v, %v, got %v, %v, %v", 1, false, true, v, present, expired)
+	}
+
+	// The expired entry was removed, so it is now missing
+	if v, present, expired := c.GetOk3("a"); v != 0 || present || expired {
+		t.Fatalf("Expected %v, %v, %v, got %v, %v, %v", 0, false, false, v, present, expired)
+	}
+}
+
+func Test_unsafeCache_WithOnExpired(t *testing.T) {
+	var expired, evicted []string
+	c, clock := newTTLCache(time.Minute,
+		WithOnEvicted(func(k string, v int) { evicted = append(evicted, k) }),
+		WithOnExpired(func(k string, v int) { expired = append(expired, k) }),
+	)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	clock.advance(30 * time.Second)
+	c.Add("c", 3)
+	clock.advance(30 * time.Second)
+
+	// Lazy removal
+	c.Get("a")
+	c.Remove("c")
+	if !reflect.DeepEqual(expired, []string{"a"}) || !reflect.DeepEqual(evicted, []string{"c"}) {
+		t.Fatalf("bad callbacks: expired %v, evicted %v", expired, evicted)
+	}
+
+	c.Add("d", 4)
+	clock.advance(30 * time.Second)
+	if removed := c.RemoveExpired(); removed != 1 {
+		t.Fatalf("Expected %v, got %v", 1, removed)
+	}
+	if !reflect.DeepEqual(expired, []string{"a", "b"}) || c.Len() != 1 {
+		t.Fatalf("bad callbacks: expired %v, len %v", expired, c.Len())
+	}
+}
+
+func Test_unsafeCache_WithTTI(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	c := NewUnsafeLru[string, int](10,
+		WithTTL[string, int](time.Hour),
+		WithTTI[string, int](time.Minute),
+	).(*unsafeCache[string, int])
+	c.now = clock.now
+
+	c.Add("idle", 1)
+	c.Add("busy", 2)
+
+	// Reads keep the busy entry alive, until its time to live
+	for i := 0; i < 71; i++ {
+		clock.advance(50 * time.Second)
+		if _, ok := c.Get("busy"); !ok {
+			t.Fatalf("should not be expired after %v", clock.t.Sub(time.Unix(0, 0)))
+		}
+	}
+	if c.Contains("idle") {
+		t.Fatal("should be idle")
+	}
+
+	clock.advance(50 * time.Second)
+	if _, ok := c.Get("busy"); ok {
+		t.Fatal("should be expired")
+	}
+
+	if removed := c.RemoveExpired(); removed != 1 {
+		t.Fatalf("Expected %v, got %v", 1, removed)
+	}
+}
+
+func Test_unsafeCache_AddWithTTL(t *testing.T) {
+	c, clock := newTTLCache(time.Minute)
+	c.AddWithTTL("short", 1, time.Second)
+	c.AddWithTTL("forever", 2, NoExpiration)
+	c.Add("default", 3)
+	c.AddWithTTL("zero", 4, 0)
+
+	clock.advance(time.Second)
+	if c.Contains("short") || !c.Contains("default") {
+		t.Fatal("short should be expired")
+	}
+
+	clock.advance(time.Minute)
+	if c.Contains("default") || c.Contains("zero") || !c.Contains("forever") {
+		t.Fatal("default and zero should be expired")
+	}
+
+	// Updating with Add applies the default again
+	c.Add("forever", 2)
+	clock.advance(time.Minute)
+	if c.Contains("forever") {
+		t.Fatal("forever should be expired")
+	}
+
+	// Per-entry TTLs also work without a default
+	u := NewUnsafeLru[string, int](10).(*unsafeCache[string, int])
+	u.now = clock.now
+	u.AddWithTTL("a", 1, time.Second)
+	clock.advance(time.Second)
+	if removed := u.RemoveExpired(); removed != 1 {
+		t.Fatalf("Expected %v, got %v", 1, removed)
+	}
+}
+
+func Test_unsafeCache_WithOnExpiredBatch(t *testing.T) {
+	var (
+		batches [][]Entry[string, int]
+		evicted int
+	)
+	c, clock := newTTLCache(time.Minute,
+		WithOnExpiredBatch(func(entries []Entry[string, int]) { batches = append(batches, entries) }),
+		WithOnEvicted(func(k string, v int) { evicted++ }),
+	)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Add("c", 3)
+	c.AddWithTTL("d", 4, time.Hour)
+	clock.advance(time.Minute)
+
+	// Lazy expirations are delivered one by one
+	c.Get("a")
+	if c.RemoveExpired() != 2 || c.RemoveExpired() != 0 {
+		t.Fatal("b and c should be removed")
+	}
+	if len(batches) != 2 || len(batches[0]) != 1 || batches[0][0].Key != "a" {
+		t.Fatalf("bad batches: %v", batches)
+	}
+	if len(batches[1]) != 2 || batches[1][0].Value+batches[1][1].Value != 5 {
+		t.Fatalf("bad batch: %v", batches[1])
+	}
+	if evicted != 0 {
+		t.Fatalf("Expected %v, got %v", 0, evicted)
+	}
+}
+
+func Test_unsafeCache_AddWithTTLNoExpirationTTI(t *testing.T) {
+	c, clock := newTTLCache(0, WithTTI[string, int](time.Minute))
+	c.AddWithTTL("a", 1, NoExpiration)
+	c.AddWithTTL("b", 2, NoExpiration)
+
+	// The time to idle still applies
+	clock.advance(30 * time.Second)
+	c.Get("a")
+	clock.advance(30 * time.Second)
+	if !c.Contains("a") || c.Contains("b") {
+		t.Fatal("b should be expired after being idle")
+	}
+}
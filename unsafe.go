@@ -1,6 +1,16 @@
 package lru
 
-import "github.com/electricbubble/lru/list"
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/electricbubble/lru/list"
+)
+
+const defaultEvictionBatch = 1
 
 type Option[K comparable, V any] func(*unsafeCache[K, V])
 
@@ -18,14 +28,77 @@ func WithOnEvictedAsync[K comparable, V any](onEvicted func(key K, value V)) Opt
 	}
 }
 
+// WithOnEvictedContext is like WithOnEvictedAsync, and passes the callback
+// a context which is cancelled by Close, so that long-running cleanup
+// can be aborted at shutdown.
+func WithOnEvictedContext[K comparable, V any](onEvicted func(ctx context.Context, key K, value V)) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		c.onEvicted = func(key K, value V) {
+			onEvicted(c.ctx, key, value)
+		}
+		c.async = true
+	}
+}
+
+// WithHitCounting enables tracking of a per-entry hit counter, which is
+// exposed by Info and MostAccessed. It is off by default to avoid the
+// bookkeeping overhead on every Get.
+func WithHitCounting[K comparable, V any]() Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		c.countHits = true
+	}
+}
+
+// WithEvictionBatch sets how many excess entries a single Add evicts,
+// in addition to the one it makes room for, while the cache is above its
+// limit after SetCapacityLazy. Larger batches converge faster at the cost
+// of slower individual Adds. WithWeigher, the Adds exceeding the maximum
+// cost evict n-1 entries more than they make room for, so that the next
+// Adds do not each evict. The default is 1.
+func WithEvictionBatch[K comparable, V any](n int) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		if n > 0 {
+			c.evictBatch = n
+		}
+	}
+}
+
+// WithUnlimited removes the limit on the number of entries, which
+// NewUnsafeLru and New otherwise default to when given a size of zero.
+// The cache is then an LRU-ordered map which only evicts entries
+// for a weigher or a TTL, or after a Resize.
+func WithUnlimited[K comparable, V any]() Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		c.maxEntries = math.MaxInt
+	}
+}
+
+// WithMaxEntries sets the limit on the number of entries, overriding the
+// size given to the constructor, e.g. for NewScoped which takes none.
+// Sizes of zero or less are ignored.
+func WithMaxEntries[K comparable, V any](maxEntries int) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		if maxEntries > 0 {
+			c.maxEntries = maxEntries
+		}
+	}
+}
+
 func NewUnsafeLru[K comparable, V any](maxEntries int, opts ...Option[K, V]) Lru[K, V] {
 	if maxEntries <= 0 {
 		maxEntries = defaultSize
 	}
+	ctx, cancel := context.WithCancel(context.Background())
 	c := &unsafeCache[K, V]{
 		maxEntries: maxEntries,
+		ctx:        ctx,
+		cancel:     cancel,
+		drained:    make(chan struct{}),
+		evictBatch: defaultEvictionBatch,
+		events:     newEventHub[K, V](),
+		now:        time.Now,
 		entries:    list.New[*entry[K, V]](),
-		bucket:     make(map[K]*list.Element[*entry[K, V]]),
+		bucket:     make(builtinMap[K, *list.Element[*entry[K, V]]]),
 	}
 	for _, fn := range opts {
 		if fn == nil {
@@ -41,7 +114,7 @@ var _ Lru[int, any] = (*unsafeCache[int, any])(nil)
 // unsafeCache is an LRU cache. It is not safe for concurrent access.
 type unsafeCache[K comparable, V any] struct {
 	// maxEntries is the maximum number of cache entries before
-	// an item is evicted. Zero means no limit.
+	// an item is evicted. It is math.MaxInt for an unlimited cache.
 	maxEntries int
 
 	// onEvicted optionally specifies a callback function to be
@@ -49,99 +122,536 @@ type unsafeCache[K comparable, V any] struct {
 	onEvicted func(key K, value V)
 	async     bool
 
+	// ctx is cancelled by Close, for the callbacks of WithOnEvictedContext.
+	ctx    context.Context
+	cancel context.CancelFunc
+	// background counts the asynchronous work in flight, and has
+	// backgroundClosed set by Close, after which no work starts. drained
+	// is closed once both are true of it and the count drops to zero, so
+	// that tracking the work only costs atomic operations.
+	background atomic.Int64
+	drained    chan struct{}
+	// closeMu orders the start of the callback queue with Close.
+	closeMu sync.Mutex
+
+	// ordered delivers the asynchronous callbacks through queue.
+	ordered bool
+	queue   *callbackQueue[K, V]
+
+	// countHits enables the per-entry hit counter.
+	countHits bool
+
+	// evictBatch is the number of excess entries evicted per Add
+	// while shrinking lazily.
+	evictBatch int
+
+	// events publishes entry events to subscribers.
+	events *eventHub[K, V]
+
+	// weigher optionally computes the cost of an entry, the oldest
+	// entries are evicted while the total cost exceeds maxCost.
+	weigher func(key K, value V) int64
+	maxCost int64
+	cost    int64
+
+	// memoryFraction sizes maxCost relative to the runtime memory limit,
+	// which is re-read every memoryLimitCheckInterval cost checks, or to
+	// memoryFallback without a limit.
+	memoryFraction float64
+	memoryFallback int64
+	adds           uint64
+
+	// ttl optionally expires entries a fixed duration after they were
+	// written, and tti after they were last read or written.
+	// now is the clock used for them.
+	ttl time.Duration
+	tti time.Duration
+	now func() time.Time
+
+	// expiries orders the entries with an expiry.
+	expiries expiryHeap[K, V]
+
+	// onExpired optionally replaces onEvicted for the expired entries.
+	onExpired func(key K, value V)
+
+	// onExpiredBatch optionally replaces onExpired with a callback
+	// per RemoveExpired sweep.
+	onExpiredBatch func(entries []Entry[K, V])
+
+	// history optionally records the recently evicted entries.
+	history *evictionRing[K, V]
+
+	// slab optionally allocates the entries.
+	slab      *entrySlab[K, V]
+	slabLimit int
+
+	// onError is optionally invoked with the errors recovered from.
+	onError func(err error)
+
+	// overflow decides what happens to new entries of a full cache.
+	overflow OverflowPolicy
+
+	// softTTL flags the entries as stale before they expire,
+	// the refresh callback is invoked for the stale ones.
+	softTTL time.Duration
+	refresh func(key K, value V)
+
+	// sourceLimit optionally limits the insertions per source.
+	sourceLimit *sourceLimiter
+
+	// tombstones optionally records the removals.
+	tombstones *tombstones[K]
+
+	// thrash optionally detects the evicted keys added back.
+	thrash *thrashDetector[K]
+
+	// paused rejects the new entries instead of evicting,
+	// between PauseEvictions and ResumeEvictions.
+	paused bool
+
+	// source is the writer of the entry being added, set by
+	// AddWithSource for the duration of the Add.
+	source string
+
+	// storeTransform and loadTransform optionally transform the values
+	// written and read.
+	storeTransform, loadTransform func(value V) V
+
+	// dedup optionally shares the equal values.
+	dedup *dedupTable[V]
+
+	// promotionTTL is read by the ARC and 2Q caches.
+	promotionTTL PromotionTTL
+
+	// noStats disables the counters and the observers.
+	noStats bool
+
+	// strict makes Contains and Peek update the recency.
+	strict bool
+
+	// oversized optionally reports the entries too large to be added.
+	oversized func(key K, value V, cost int64) bool
+
+	// trimSignal defers evictions to Trim, it is notified when
+	// the cache goes over its limits.
+	trimSignal chan struct{}
+
+	// janitorInterval is the period of the RemoveExpired sweeps of the
+	// Cache returned by New, zero disables them.
+	janitorInterval time.Duration
+
+	// lockSampleEvery is read by the Cache returned by New.
+	lockSampleEvery uint64
+
+	// sizeGauge is optionally invoked with the last reported
+	// size and capacity when they change.
+	sizeGauge            func(len, cap int)
+	gaugedLen, gaugedCap int
+
+	// validator optionally checks the values on Get, the invalid
+	// entries are evicted and reported as misses.
+	validator func(key K, value V) bool
+
+	// stats counts the lookups and removals.
+	stats Stats
+	// deltaBase holds the counters at the previous StatsDelta,
+	// and the high-water marks since then.
+	deltaBase Stats
+
+	// policy optionally chooses the entries to evict instead of
+	// the order of entries.
+	policy Policy[K]
+
+	// evictionFilter optionally vetoes the capacity evictions.
+	evictionFilter func(key K, value V) bool
+
+	// decayHalfLife optionally tracks the decayed access rate of the
+	// entries, to evict the least used among decayCandidates oldest.
+	decayHalfLife   time.Duration
+	decayCandidates int
+
+	// onBatchRemoved optionally replaces the callbacks of RemoveAll,
+	// which collects the removed entries in batch meanwhile.
+	onBatchRemoved func(entries []Entry[K, V])
+	batch          *[]Entry[K, V]
+
+	// keyNormalizer optionally canonicalizes the keys of every operation.
+	keyNormalizer func(key K) K
+
+	// trace optionally records the operations.
+	trace *traceRecorder
+
 	entries *list.List[*entry[K, V]]
-	bucket  map[K]*list.Element[*entry[K, V]]
+	bucket  bucketMap[K, *list.Element[*entry[K, V]]]
 }
 
 // entry is used to hold a value in the entries
 type entry[K comparable, V any] struct {
 	key   K
 	value V
+
+	// hits is the number of cache hits served by this entry,
+	// only maintained when countHits is enabled.
+	hits uint64
+
+	// cost is the weight of the entry, only maintained with a weigher.
+	cost int64
+
+	// expiresAt is when the entry becomes stale, zero means never.
+	// writeExpiresAt is the part of it which is not extended by reads.
+	expiresAt      time.Time
+	writeExpiresAt time.Time
+	heapIndex      int
+
+	// rate is the decayed access rate at rateAt, only maintained
+	// with a decay half-life.
+	rate   float64
+	rateAt time.Time
+
+	// staleAt is when the entry goes past its soft TTL, zero means never.
+	// refreshing is set once its refresh was started.
+	staleAt    time.Time
+	refreshing bool
+
+	// source is the writer of the entry, set by AddWithSource.
+	source string
 }
 
 func (c *unsafeCache[K, V]) Add(key K, value V) (evicted bool) {
-	// Check for existing item
-	if elem, ok := c.bucket[key]; ok {
-		c.entries.MoveToFront(elem)
-		elem.Value.value = value
+	return c.add(key, value, c.ttl) == AddResultEvicted
+}
+
+func (c *unsafeCache[K, V]) AddWithTTL(key K, value V, ttl time.Duration) (evicted bool) {
+	if ttl == 0 {
+		ttl = c.ttl
+	}
+	return c.add(key, value, ttl) == AddResultEvicted
+}
+
+func (c *unsafeCache[K, V]) AddNew(key K, value V) (added bool) {
+	if !c.noStats {
+		defer c.peak()
+	}
+	key = c.normalize(key)
+	value = c.storeValue(value)
+	cost := c.weigh(key, value)
+	if c.oversized != nil && c.oversized(key, value, cost) {
 		return false
 	}
+	if c.rejecting() && c.full(cost) {
+		return false
+	}
+	// Only the insertions take a token of the source
+	if c.sourceLimit != nil {
+		if _, ok := c.bucket.get(key); !ok && c.overRate() {
+			return false
+		}
+	}
+
+	// The key is indexed without looking it up first
+	ent := c.newEntry(key, value)
+	elem := c.entries.PushFront(ent)
+	added = c.bucket.insert(key, elem)
+	if c.trace != nil {
+		c.record(TraceAdd, key, !added)
+	}
+	if !added {
+		c.entries.Remove(elem)
+		if c.dedup != nil {
+			c.dedup.release(ent.value)
+		}
+		if c.slab != nil {
+			c.slab.release(ent)
+		}
+		return false
+	}
+	c.inserted(elem, cost, c.ttl)
+	return true
+}
+
+// add adds or updates an entry which expires ttl after being written.
+func (c *unsafeCache[K, V]) add(key K, value V, ttl time.Duration) (result AddResult) {
+	return c.addStored(key, c.storeValue(value), ttl)
+}
+
+// addStored is add for a value already in its stored form.
+func (c *unsafeCache[K, V]) addStored(key K, value V, ttl time.Duration) (result AddResult) {
+	if !c.noStats {
+		defer c.peak()
+	}
+	key = c.normalize(key)
+	if c.trace != nil {
+		_, ok := c.bucket.get(key)
+		c.record(TraceAdd, key, ok)
+	}
+	cost := c.weigh(key, value)
+	if c.oversized != nil && c.oversized(key, value, cost) {
+		return AddResultTooLarge
+	}
+
+	// Check for existing item
+	if elem, ok := c.bucket.get(key); ok {
+		return c.update(elem, value, cost, ttl)
+	}
+
+	if c.rejecting() && c.full(cost) || c.overRate() {
+		return AddResultRejected
+	}
 
 	// Add new item
-	ent := &entry[K, V]{key, value}
+	ent := c.newEntry(key, value)
 	elem := c.entries.PushFront(ent)
-	c.bucket[key] = elem
+	c.bucket.set(key, elem)
+	return c.inserted(elem, cost, ttl)
+}
+
+// update replaces the value of an existing element, of the given cost.
+func (c *unsafeCache[K, V]) update(elem *list.Element[*entry[K, V]], value V, cost int64, ttl time.Duration) (result AddResult) {
+	c.entries.MoveToFront(elem)
+	if c.dedup != nil {
+		old := elem.Value.value
+		value = c.dedup.intern(value)
+		c.dedup.release(old)
+	}
+	elem.Value.value = value
+	elem.Value.source = c.source
+	if c.policy != nil {
+		c.policy.Access(elem.Value.key)
+	}
+	if c.decayHalfLife > 0 {
+		c.recordAccess(elem.Value)
+	}
+	c.touch(elem.Value, ttl)
+	if c.weigher != nil {
+		c.weighed(elem.Value, cost)
+	}
+	c.publish(EventUpdate, elem.Value)
+	if c.weigher != nil && c.trimSignal != nil {
+		c.requestTrim()
+	} else if c.weigher != nil && !c.paused && c.evictOverCost() > 0 {
+		return AddResultEvicted
+	}
+	return AddResultUpdated
+}
+
+// inserted completes the insertion of a new element of the given cost,
+// evicting the oldest entries if it made the cache exceed its limits.
+func (c *unsafeCache[K, V]) inserted(elem *list.Element[*entry[K, V]], cost int64, ttl time.Duration) (result AddResult) {
+	ent := elem.Value
+	if c.policy != nil {
+		c.policy.Insert(ent.key)
+	}
+	if c.decayHalfLife > 0 {
+		c.recordAccess(ent)
+	}
+	c.gauge()
+	if c.thrash != nil {
+		c.thrash.insert(ent.key, c.maxEntries)
+	}
+	if c.tombstones != nil {
+		c.tombstones.forget(ent.key)
+	}
+	c.touch(ent, ttl)
+	if c.weigher != nil {
+		c.weighed(ent, cost)
+	}
+	c.publish(EventAdd, ent)
 
-	evicted = c.entries.Len() > c.maxEntries
+	result = AddResultAdded
+	if c.trimSignal != nil {
+		c.requestTrim()
+		return result
+	}
 	// Verify size not exceeded
-	if evicted {
+	if c.entries.Len() > c.maxEntries {
+		result = AddResultEvicted
 		c.removeOldest()
+		// Still over the limit after a lazy shrink, so evict up to
+		// evictBatch more entries to converge without a burst of evictions
+		for i := 0; i < c.evictBatch && c.entries.Len() > c.maxEntries; i++ {
+			c.removeOldest()
+		}
 	}
-	return evicted
+	if c.weigher != nil && c.evictOverCost() > 0 {
+		result = AddResultEvicted
+	}
+	return result
 }
 
 func (c *unsafeCache[K, V]) Get(key K) (value V, ok bool) {
-	var elem *list.Element[*entry[K, V]]
-	if elem, ok = c.bucket[key]; !ok {
-		return
+	value, ok, _ = c.GetOk3(key)
+	return value, ok
+}
+
+func (c *unsafeCache[K, V]) GetOrAdd(key K, value V) (actual V, loaded bool) {
+	if actual, loaded = c.Get(key); loaded {
+		return actual, true
 	}
+	c.Add(key, value)
+	return value, false
+}
 
-	c.entries.MoveToFront(elem)
-	if elem.Value == nil {
-		return value, false
+func (c *unsafeCache[K, V]) ContainsOrAdd(key K, value V) (ok, evicted bool) {
+	if c.Contains(key) {
+		return true, false
 	}
+	return false, c.Add(key, value)
+}
 
-	value = elem.Value.value
-	return
+func (c *unsafeCache[K, V]) PeekOrAdd(key K, value V) (previous V, ok, evicted bool) {
+	if previous, ok = c.Peek(key); ok {
+		return previous, true, false
+	}
+	return previous, false, c.Add(key, value)
+}
+
+func (c *unsafeCache[K, V]) GetOk3(key K) (value V, present bool, expired bool) {
+	key = c.normalize(key)
+	elem, ok := c.bucket.get(key)
+	if c.trace != nil {
+		c.record(TraceGet, key, ok && !c.expired(elem.Value))
+	}
+	if ok && c.expired(elem.Value) {
+		value = c.loadValue(elem.Value.value)
+		c.removeElement(elem, EventExpire)
+		if !c.noStats {
+			c.stats.Misses++
+		}
+		c.events.publish(EventMiss, Entry[K, V]{Key: key})
+		return value, false, true
+	}
+	if ok && c.validator != nil && !c.validator(key, elem.Value.value) {
+		c.removeElement(elem, EventEvict)
+		ok = false
+	}
+	if !ok {
+		if !c.noStats {
+			c.stats.Misses++
+		}
+		c.events.publish(EventMiss, Entry[K, V]{Key: key})
+		return value, false, false
+	}
+
+	c.entries.MoveToFront(elem)
+	if c.policy != nil {
+		c.policy.Access(key)
+	}
+	if c.decayHalfLife > 0 {
+		c.recordAccess(elem.Value)
+	}
+	if c.tti > 0 {
+		c.access(elem.Value, c.now())
+	}
+	if c.countHits {
+		elem.Value.hits++
+	}
+	value = c.loadValue(elem.Value.value)
+	if !c.noStats {
+		c.stats.Hits++
+	}
+	c.publish(EventHit, elem.Value)
+	return value, true, false
 }
 
 func (c *unsafeCache[K, V]) Contains(key K) (ok bool) {
-	_, ok = c.bucket[key]
-	return ok
+	key = c.normalize(key)
+	elem, ok := c.bucket.get(key)
+	if !ok || c.expired(elem.Value) {
+		return false
+	}
+	if c.strict {
+		c.observe(elem)
+	}
+	return true
 }
 
 func (c *unsafeCache[K, V]) Peek(key K) (value V, ok bool) {
+	key = c.normalize(key)
 	var elem *list.Element[*entry[K, V]]
-	if elem, ok = c.bucket[key]; !ok {
-		return
+	if elem, ok = c.bucket.get(key); !ok || c.expired(elem.Value) {
+		return value, false
+	}
+	if c.strict {
+		c.observe(elem)
 	}
 
-	value = elem.Value.value
+	value = c.loadValue(elem.Value.value)
 	return
 }
 
 func (c *unsafeCache[K, V]) Remove(key K) (ok bool) {
+	key = c.normalize(key)
 	var elem *list.Element[*entry[K, V]]
-	if elem, ok = c.bucket[key]; !ok {
+	elem, ok = c.bucket.get(key)
+	if c.trace != nil {
+		c.record(TraceRemove, key, ok)
+	}
+	if !ok {
 		return
 	}
 
-	c.removeElement(elem)
+	c.removeElement(elem, EventRemove)
 	return
 }
 
+func (c *unsafeCache[K, V]) RemoveGet(key K) (value V, ok bool) {
+	key = c.normalize(key)
+	elem, ok := c.bucket.get(key)
+	if c.trace != nil {
+		c.record(TraceRemove, key, ok)
+	}
+	if !ok {
+		return value, false
+	}
+
+	if c.expired(elem.Value) {
+		c.removeElement(elem, EventExpire)
+		return value, false
+	}
+	value = c.loadValue(elem.Value.value)
+	c.removeElement(elem, EventRemove)
+	return value, true
+}
+
+func (c *unsafeCache[K, V]) Pop(key K) (value V, ok bool) {
+	return c.RemoveGet(key)
+}
+
+func (c *unsafeCache[K, V]) Compute(key K, fn func(old V, exists bool) (value V, keep bool)) (value V, ok bool) {
+	old, exists := c.Peek(key)
+	value, keep := fn(old, exists)
+	if !keep {
+		if exists {
+			c.Remove(key)
+		}
+		return value, false
+	}
+	c.Add(key, value)
+	return value, c.Contains(key)
+}
+
 func (c *unsafeCache[K, V]) RemoveOldest() (key K, value V, ok bool) {
-	elem := c.entries.Back()
+	elem := c.victim()
 	if elem == nil {
 		return key, value, false
 	}
 
-	c.removeElement(elem)
 	ent := elem.Value
 	key = ent.key
-	value = ent.value
+	value = c.loadValue(ent.value)
+	c.removeElement(elem, EventEvict)
 	return key, value, true
 }
 
 func (c *unsafeCache[K, V]) GetOldest() (key K, value V, ok bool) {
-	elem := c.entries.Back()
+	elem := c.victim()
 	if elem == nil {
 		return key, value, false
 	}
 
 	ent := elem.Value
 	key = ent.key
-	value = ent.value
+	value = c.loadValue(ent.value)
 	return key, value, true
 }
 
@@ -153,6 +663,16 @@ func (c *unsafeCache[K, V]) Keys() []K {
 	return keys
 }
 
+func (c *unsafeCache[K, V]) KeysWhere(fn func(key K) bool) []K {
+	var keys []K
+	for elem := c.entries.Back(); elem != nil; elem = elem.Prev() {
+		if fn(elem.Value.key) {
+			keys = append(keys, elem.Value.key)
+		}
+	}
+	return keys
+}
+
 func (c *unsafeCache[K, V]) Len() int {
 	return c.entries.Len()
 }
@@ -166,43 +686,235 @@ func (c *unsafeCache[K, V]) Resize(size int) (evicted int) {
 		c.removeOldest()
 	}
 	c.maxEntries = size
+	c.gauge()
 	return diff
 }
 
+func (c *unsafeCache[K, V]) SetCapacityLazy(size int) (excess int) {
+	c.maxEntries = size
+	c.gauge()
+	excess = c.Len() - size
+	if excess < 0 {
+		excess = 0
+	}
+	return excess
+}
+
 func (c *unsafeCache[K, V]) Clear() {
-	for key, elem := range c.bucket {
-		if c.onEvicted != nil {
-			c.evicting(key, elem.Value.value)
-		}
-		delete(c.bucket, key)
+	// Swap the generations, the retired one is left to the GC,
+	// after its callbacks are fired
+	retired := c.entries
+	c.entries = list.New[*entry[K, V]]()
+	c.bucket = c.bucket.empty()
+	if c.dedup != nil {
+		c.dedup.slots = make(map[uint64][]*dedupSlot[V])
+	}
+	if c.onEvicted != nil {
+		c.reclaim(retired)
 	}
-	c.entries.Init()
+	if c.policy != nil {
+		c.policy.Clear()
+	}
+	if c.thrash != nil {
+		c.thrash.clear()
+	}
+	c.cost = 0
+	c.expiries = nil
+	c.gauge()
+	if c.slab != nil {
+		c.slab.reset()
+	}
+	c.events.publish(EventClear, Entry[K, V]{})
 }
 
 // removeOldest removes the oldest item from the cache.
 func (c *unsafeCache[K, V]) removeOldest() {
-	ent := c.entries.Back()
+	ent := c.victim()
+	if c.evictionFilter != nil {
+		ent = c.filterVictim(ent)
+	}
 	if ent != nil {
-		c.removeElement(ent)
+		c.removeElement(ent, EventEvict)
 	}
 }
 
-// removeElement is used to remove a given list element from the cache
-func (c *unsafeCache[K, V]) removeElement(elem *list.Element[*entry[K, V]]) {
+// victim returns the next entry to evict, the one chosen by the policy,
+// or the least recently used one.
+func (c *unsafeCache[K, V]) victim() *list.Element[*entry[K, V]] {
+	if c.policy != nil {
+		if key, ok := c.policy.Victim(); ok {
+			if elem, ok := c.bucket.get(key); ok {
+				return elem
+			}
+		}
+	}
+	if c.decayHalfLife > 0 {
+		return c.leastDecayed()
+	}
+	return c.entries.Back()
+}
+
+// eventMove is the kind of the removals moving an entry to another list
+// of an ARC or 2Q cache. It is internal: they are not published, recorded
+// or counted, leave no tombstone and fire no callback.
+const eventMove EventKind = 0
+
+// removeElement is used to remove a given list element from the cache,
+// kind is the event published for it.
+func (c *unsafeCache[K, V]) removeElement(elem *list.Element[*entry[K, V]], kind EventKind) {
 	c.entries.Remove(elem)
 	ent := elem.Value
-	delete(c.bucket, ent.key)
+	c.bucket.delete(ent.key)
+	if c.policy != nil {
+		c.policy.Remove(ent.key)
+	}
+	c.cost -= ent.cost
+	c.expiries.remove(ent)
+	if c.dedup != nil {
+		c.dedup.release(ent.value)
+	}
+	c.gauge()
+	if kind == eventMove {
+		if c.slab != nil {
+			c.slab.release(ent)
+		}
+		return
+	}
+	switch {
+	case c.noStats:
+	case kind == EventEvict:
+		c.stats.Evictions++
+	case kind == EventExpire:
+		c.stats.Expirations++
+	}
+	if c.thrash != nil && kind == EventEvict {
+		c.thrash.evict(ent.key)
+	}
+	if c.tombstones != nil && kind == EventRemove {
+		c.tombstones.add(ent.key, c.now())
+	}
+	c.publish(kind, ent)
+	if c.history != nil {
+		c.history.record(ent, kind, c.now())
+	}
 
-	if c.onEvicted == nil {
+	switch {
+	case c.batch != nil, kind == EventExpire && c.onExpiredBatch != nil:
+		c.batched(ent)
+	case kind == EventExpire && c.onExpired != nil:
+		c.onExpired(ent.key, c.loadValue(ent.value))
+	case c.onEvicted != nil:
+		c.evicting(ent.key, c.loadValue(ent.value))
+	}
+	if c.slab != nil {
+		c.slab.release(ent)
+	}
+}
+
+// batched collects a removed entry for the batch in progress, or passes
+// it to the batch callback of the expired entries. It is kept out of
+// removeElement, whose frame it would enlarge, so that the goroutines
+// adding to the cache rarely need to grow their stack on an eviction.
+func (c *unsafeCache[K, V]) batched(ent *entry[K, V]) {
+	if c.batch != nil {
+		*c.batch = append(*c.batch, c.export(ent))
 		return
 	}
-	c.evicting(ent.key, ent.value)
+	c.onExpiredBatch([]Entry[K, V]{c.export(ent)})
+}
+
+// reclaim fires the eviction callbacks of the entries retired by Clear,
+// oldest first. Asynchronous callbacks are fired by a single goroutine,
+// so that Clear does not iterate the entries.
+func (c *unsafeCache[K, V]) reclaim(retired *list.List[*entry[K, V]]) {
+	if !c.async || c.ordered {
+		for elem := retired.Back(); elem != nil; elem = elem.Prev() {
+			c.evicting(elem.Value.key, c.loadValue(elem.Value.value))
+		}
+		return
+	}
+	c.goAsync(func() {
+		for elem := retired.Back(); elem != nil; elem = elem.Prev() {
+			if c.closed() {
+				return
+			}
+			c.onEvicted(elem.Value.key, c.loadValue(elem.Value.value))
+		}
+	})
 }
 
 func (c *unsafeCache[K, V]) evicting(key K, value V) {
-	if c.async {
-		go c.onEvicted(key, value)
-	} else {
+	if !c.async {
+		c.onEvicted(key, value)
+		return
+	}
+	if c.ordered {
+		c.enqueue(key, value)
+		return
+	}
+	c.goAsync(func() {
+		if c.closed() {
+			return
+		}
 		c.onEvicted(key, value)
+	})
+}
+
+func (c *unsafeCache[K, V]) Close() error {
+	c.shutdown()
+	<-c.drained
+	return nil
+}
+
+// backgroundClosed is the bit of background set by Close.
+const backgroundClosed = 1 << 62
+
+// shutdown marks the cache closed, cancels the context and stops the
+// callback queue without waiting for the background work.
+func (c *unsafeCache[K, V]) shutdown() {
+	for {
+		n := c.background.Load()
+		if n&backgroundClosed != 0 {
+			return
+		}
+		if c.background.CompareAndSwap(n, n|backgroundClosed) {
+			if n == 0 {
+				close(c.drained)
+			}
+			break
+		}
+	}
+	c.cancel()
+	c.closeMu.Lock()
+	q := c.queue
+	c.closeMu.Unlock()
+	if q != nil {
+		q.close()
+	}
+}
+
+// closed returns true once Close was called.
+func (c *unsafeCache[K, V]) closed() bool {
+	return c.background.Load()&backgroundClosed != 0
+}
+
+// track registers background work waited for by Close, it returns
+// false once the cache is closed. The work calls untrack when done.
+func (c *unsafeCache[K, V]) track() bool {
+	for {
+		n := c.background.Load()
+		if n&backgroundClosed != 0 {
+			return false
+		}
+		if c.background.CompareAndSwap(n, n+1) {
+			return true
+		}
+	}
+}
+
+// untrack ends background work registered by track.
+func (c *unsafeCache[K, V]) untrack() {
+	if c.background.Add(-1) == backgroundClosed {
+		close(c.drained)
 	}
 }
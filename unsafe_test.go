@@ -1,8 +1,11 @@
 package lru
 
 import (
+	"context"
+	"fmt"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -152,6 +155,32 @@ func Test_unsafeCache_Get(t *testing.T) {
 	}
 }
 
+func Test_unsafeCache_AddNew(t *testing.T) {
+	for _, backend := range []MapBackend{BuiltinMap, SwissMap} {
+		c := NewUnsafeLru[int, int](2,
+			WithMapBackend[int, int](backend, nil),
+			WithSlabAllocation[int, int](2),
+		)
+		if !c.AddNew(1, 1) || !c.AddNew(2, 2) {
+			t.Fatal("missing keys should be added")
+		}
+		if c.AddNew(1, 10) {
+			t.Fatal("existing key should not be added")
+		}
+		if v, _ := c.Peek(1); v != 1 || c.Len() != 2 {
+			t.Fatalf("Expected %v, got %v", 1, v)
+		}
+		if k, _, _ := c.GetOldest(); k != 1 {
+			t.Fatalf("Expected %v, got %v", 1, k)
+		}
+
+		// Evicts like Add
+		if !c.AddNew(3, 3) || c.Contains(1) || c.Len() != 2 {
+			t.Fatal("oldest entry should be evicted")
+		}
+	}
+}
+
 func Test_unsafeCache_Remove(t *testing.T) {
 	var (
 		maxEntries = 10
@@ -172,6 +201,25 @@ func Test_unsafeCache_Remove(t *testing.T) {
 	}
 }
 
+func Test_unsafeCache_RemoveGet(t *testing.T) {
+	c, clock := newTTLCache(time.Minute)
+	c.Add("a", 1)
+	c.AddWithTTL("b", 2, time.Second)
+
+	if v, ok := c.RemoveGet("a"); !ok || v != 1 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, true, v, ok)
+	}
+	if v, ok := c.RemoveGet("a"); ok || v != 0 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 0, false, v, ok)
+	}
+
+	// Expired entries are removed without their value
+	clock.advance(time.Second)
+	if v, ok := c.RemoveGet("b"); ok || v != 0 || c.Len() != 0 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 0, false, v, ok)
+	}
+}
+
 func Test_unsafeCache_RemoveOldest(t *testing.T) {
 	var (
 		maxEntries = 10
@@ -220,6 +268,37 @@ func Test_unsafeCache_Clear(t *testing.T) {
 	}
 }
 
+func Test_unsafeCache_ClearCallbacks(t *testing.T) {
+	var keys []int
+	c := NewUnsafeLru[int, int](10, WithOnEvicted(func(k, v int) { keys = append(keys, k) }))
+	for i := 0; i < 3; i++ {
+		c.Add(i, i)
+	}
+	c.Clear()
+	if fmt.Sprint(keys) != "[0 1 2]" {
+		t.Fatalf("Expected %v, got %v", "[0 1 2]", keys)
+	}
+
+	// Asynchronous callbacks are fired by the reclaiming goroutine
+	done := make(chan int, 10)
+	a := NewUnsafeLru[int, int](10, WithOnEvictedAsync(func(k, v int) { done <- k }))
+	for i := 0; i < 3; i++ {
+		a.Add(i, i)
+	}
+	a.Clear()
+	a.Add(3, 3)
+	if a.Len() != 1 || !a.Contains(3) {
+		t.Fatal("new generation should be usable")
+	}
+	keys = keys[:0]
+	for i := 0; i < 3; i++ {
+		keys = append(keys, <-done)
+	}
+	if fmt.Sprint(keys) != "[0 1 2]" {
+		t.Fatalf("Expected %v, got %v", "[0 1 2]", keys)
+	}
+}
+
 func Test_unsafeCache_Resize(t *testing.T) {
 	var (
 		maxEntries = 10
@@ -262,3 +341,106 @@ func Test_unsafeCache_Resize(t *testing.T) {
 		t.Fatalf("Expected %v, got %v", 0, c.Len())
 	}
 }
+
+func Test_unsafeCache_SetCapacityLazy(t *testing.T) {
+	var (
+		maxEntries = 10
+		evicted    int
+		c          = NewUnsafeLru[int, int](maxEntries, WithOnEvicted[int, int](func(k, v int) {
+			evicted++
+		}))
+	)
+	for i := 0; i < maxEntries; i++ {
+		c.Add(i, i)
+	}
+
+	excess := c.SetCapacityLazy(maxEntries / 2)
+	if excess != maxEntries/2 {
+		t.Fatalf("Expected %v, got %v", maxEntries/2, excess)
+	}
+	if c.Len() != maxEntries || evicted != 0 {
+		t.Fatalf("Expected %v, %v, got %v, %v", maxEntries, 0, c.Len(), evicted)
+	}
+
+	// Every Add evicts one entry beyond the one it makes room for
+	c.Add(100, 100)
+	if c.Len() != maxEntries-1 {
+		t.Fatalf("Expected %v, got %v", maxEntries-1, c.Len())
+	}
+	for i := 101; i < 110; i++ {
+		c.Add(i, i)
+	}
+	if c.Len() != maxEntries/2 {
+		t.Fatalf("Expected %v, got %v", maxEntries/2, c.Len())
+	}
+
+	if excess = c.SetCapacityLazy(maxEntries); excess != 0 {
+		t.Fatalf("Expected %v, got %v", 0, excess)
+	}
+}
+
+func Test_unsafeCache_WithEvictionBatch(t *testing.T) {
+	maxEntries := 20
+	c := NewUnsafeLru[int, int](maxEntries, WithEvictionBatch[int, int](4))
+	for i := 0; i < maxEntries; i++ {
+		c.Add(i, i)
+	}
+
+	c.SetCapacityLazy(maxEntries / 2)
+	c.Add(100, 100)
+	if c.Len() != maxEntries-4 {
+		t.Fatalf("Expected %v, got %v", maxEntries-4, c.Len())
+	}
+	c.Add(101, 101)
+	c.Add(102, 102)
+	if c.Len() != maxEntries/2 {
+		t.Fatalf("Expected %v, got %v", maxEntries/2, c.Len())
+	}
+}
+
+func Test_unsafeCache_Close(t *testing.T) {
+	var (
+		started = make(chan struct{})
+		aborted int32
+		c       = NewUnsafeLru[int, int](1, WithOnEvictedContext(func(ctx context.Context, k, v int) {
+			close(started)
+			<-ctx.Done()
+			atomic.StoreInt32(&aborted, 1)
+		}))
+	)
+	c.Add(1, 1)
+	c.Add(2, 2)
+	<-started
+
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&aborted) != 1 {
+		t.Fatal("callback should be aborted before Close returns")
+	}
+
+	// Callbacks are no longer executed after Close
+	c.Add(3, 3)
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_unsafeCache_WithUnlimited(t *testing.T) {
+	c := NewUnsafeLru[int, int](0, WithUnlimited[int, int]())
+	for i := 0; i < defaultSize*10; i++ {
+		if c.Add(i, i) {
+			t.Fatal("should not evict")
+		}
+	}
+	if c.Len() != defaultSize*10 {
+		t.Fatalf("Expected %v, got %v", defaultSize*10, c.Len())
+	}
+
+	if evicted := c.Resize(defaultSize); evicted != defaultSize*9 {
+		t.Fatalf("Expected %v, got %v", defaultSize*9, evicted)
+	}
+	if !c.Add(-1, -1) {
+		t.Fatal("should evict after Resize")
+	}
+}
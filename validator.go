@@ -0,0 +1,11 @@
+package lru
+
+// WithValidator checks the value of an entry on every Get, e.g. to protect
+// against values of a stale schema after a deploy. The entries failing the
+// validation are evicted, executing the eviction callback, and reported as
+// misses. Peek and Contains do not validate.
+func WithValidator[K comparable, V any](validator func(key K, value V) bool) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		c.validator = validator
+	}
+}
@@ -0,0 +1,32 @@
+package lru
+
+import "testing"
+
+func TestLru_WithValidator(t *testing.T) {
+	var evicted []int
+	l := New[int, int](128,
+		WithValidator(func(key, value int) bool { return value >= 0 }),
+		WithOnEvicted(func(key, value int) { evicted = append(evicted, key) }),
+	)
+	l.Add(1, 1)
+	l.Add(2, -1)
+
+	if v, ok := l.Get(1); !ok || v != 1 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, true, v, ok)
+	}
+	if !l.Contains(2) {
+		t.Fatal("Contains should not validate")
+	}
+	if _, ok := l.Get(2); ok {
+		t.Fatal("invalid value should miss")
+	}
+	if l.Contains(2) || l.Len() != 1 {
+		t.Fatal("invalid entry should be evicted")
+	}
+	if len(evicted) != 1 || evicted[0] != 2 {
+		t.Fatalf("Expected %v, got %v", []int{2}, evicted)
+	}
+	if _, ok := l.GetEntry(2); ok {
+		t.Fatal("should miss")
+	}
+}
@@ -0,0 +1,74 @@
+package lru
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// WarmFromTrace warms the cache at startup by replaying an access log: every
+// line is parsed into a key, skipping the lines for which parse returns false,
+// and the distinct keys are loaded by at most concurrency concurrent loader
+// calls. The values are added in the order of the last access of their key,
+// so that the most recently accessed keys are the most recently used.
+// The keys whose load fails are skipped and counted as failed, err is the
+// error of reading r.
+func WarmFromTrace[K comparable, V any](c Cacher[K, V], r io.Reader, parse func(line string) (K, bool), loader func(key K) (V, error), concurrency int) (warmed, failed int, err error) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	// Order the distinct keys by their last access
+	last := make(map[K]int)
+	var keys []K
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, ok := parse(scanner.Text())
+		if !ok {
+			continue
+		}
+		last[key] = len(keys)
+		keys = append(keys, key)
+	}
+	if err = scanner.Err(); err != nil {
+		return 0, 0, fmt.Errorf("lru: warm: %w", err)
+	}
+	distinct := keys[:0]
+	for i, key := range keys {
+		if last[key] == i {
+			distinct = append(distinct, key)
+		}
+	}
+
+	type result struct {
+		value V
+		ok    bool
+	}
+	results := make([]result, len(distinct))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, key := range distinct {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, key K) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			value, err := loader(key)
+			results[i] = result{value: value, ok: err == nil}
+		}(i, key)
+	}
+	wg.Wait()
+
+	for i, key := range distinct {
+		if !results[i].ok {
+			failed++
+			continue
+		}
+		c.Add(key, results[i].value)
+		warmed++
+	}
+	return warmed, failed, nil
+}
@@ -0,0 +1,69 @@
+package lru
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWarmFromTrace(t *testing.T) {
+	trace := strings.Join([]string{
+		"GET /a",
+		"GET /b",
+		"POST /c",
+		"GET /a",
+		"GET /missing",
+		"GET /d",
+	}, "\n")
+	parse := func(line string) (string, bool) {
+		if !strings.HasPrefix(line, "GET /") {
+			return "", false
+		}
+		return strings.TrimPrefix(line, "GET /"), true
+	}
+
+	var running, maxRunning int32
+	loader := func(key string) (int, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		if key == "missing" {
+			return 0, ErrNotFound
+		}
+		return len(key), nil
+	}
+
+	l := New[string, int](3)
+	warmed, failed, err := WarmFromTrace[string, int](l, strings.NewReader(trace), parse, loader, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if warmed != 3 || failed != 1 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 3, 1, warmed, failed)
+	}
+	// In the order of the last access, b the oldest
+	if !reflect.DeepEqual(l.Keys(), []string{"b", "a", "d"}) {
+		t.Fatalf("Expected %v, got %v", []string{"b", "a", "d"}, l.Keys())
+	}
+	if maxRunning > 2 {
+		t.Fatalf("concurrency not limited: %v", maxRunning)
+	}
+
+	errRead := errors.New("read failed")
+	if _, _, err = WarmFromTrace[string, int](l, failingReader{errRead}, parse, loader, 2); !errors.Is(err, errRead) {
+		t.Fatalf("Expected %v, got %v", errRead, err)
+	}
+}
+
+type failingReader struct{ err error }
+
+func (r failingReader) Read([]byte) (int, error) { return 0, r.err }
@@ -0,0 +1,53 @@
+package lru
+
+import "time"
+
+// weakSnapshotMaxAge is the age after which KeysApprox and ItemsApprox
+// try to refresh their snapshot.
+const weakSnapshotMaxAge = time.Second
+
+// weakSnapshot is an immutable copy of the entries of a Cache.
+type weakSnapshot[K comparable, V any] struct {
+	items []Entry[K, V]
+	at    time.Time
+}
+
+// KeysApprox is like Keys, but returns the keys of a snapshot up to about
+// a second old instead of blocking the writers. The snapshot is refreshed
+// only when the read lock is free, so the result can be older under heavy
+// write contention. It is meant for monitoring, not for correctness.
+func (c *Cache[K, V]) KeysApprox() []K {
+	items := c.weakItems()
+	keys := make([]K, len(items))
+	for i, e := range items {
+		keys[i] = e.Key
+	}
+	return keys
+}
+
+// ItemsApprox is like Items, with the staleness of KeysApprox.
+func (c *Cache[K, V]) ItemsApprox() []Entry[K, V] {
+	items := c.weakItems()
+	return append(make([]Entry[K, V], 0, len(items)), items...)
+}
+
+// weakItems returns the entries of the snapshot, refreshing it if it is
+// too old and the lock is free. Only the first call waits for the lock.
+func (c *Cache[K, V]) weakItems() []Entry[K, V] {
+	if c.bypassed() {
+		return nil
+	}
+	s := c.weak.Load()
+	if s != nil && time.Since(s.at) < weakSnapshotMaxAge {
+		return s.items
+	}
+	if s == nil {
+		c.RLock()
+	} else if !c.TryRLock() {
+		return s.items
+	}
+	s = &weakSnapshot[K, V]{items: c.lru.Items(), at: time.Now()}
+	c.RUnlock()
+	c.weak.Store(s)
+	return s.items
+}
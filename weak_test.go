@@ -0,0 +1,43 @@
+package lru
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestLru_KeysApprox(t *testing.T) {
+	l := New[int, int](10)
+	l.Add(1, 1)
+	l.Add(2, 2)
+	if keys := l.KeysApprox(); fmt.Sprint(keys) != "[1 2]" {
+		t.Fatalf("Expected %v, got %v", "[1 2]", keys)
+	}
+
+	// The snapshot is reused while recent
+	l.Add(3, 3)
+	if keys := l.KeysApprox(); fmt.Sprint(keys) != "[1 2]" {
+		t.Fatalf("Expected %v, got %v", "[1 2]", keys)
+	}
+
+	// An old snapshot is kept while the lock is held
+	l.weak.Load().at = time.Now().Add(-weakSnapshotMaxAge)
+	l.Lock()
+	items := l.ItemsApprox()
+	l.Unlock()
+	if len(items) != 2 {
+		t.Fatalf("Expected %v, got %v", 2, len(items))
+	}
+
+	// and refreshed when it is free
+	items = l.ItemsApprox()
+	if len(items) != 3 || items[2].Key != 3 || items[2].Value != 3 {
+		t.Fatalf("bad items: %+v", items)
+	}
+
+	// The results are copies
+	items[0].Key = 10
+	if keys := l.KeysApprox(); keys[0] != 1 {
+		t.Fatalf("Expected %v, got %v", 1, keys[0])
+	}
+}
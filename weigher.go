@@ -0,0 +1,142 @@
+package lru
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"runtime/debug"
+)
+
+// memoryLimitCheckInterval is the number of cost checks, i.e. of Adds
+// and UpdateCosts, between two reads of the runtime memory limit.
+const memoryLimitCheckInterval = 1024
+
+// WithWeigher bounds the total cost of the entries, as computed by weigher
+// (typically their size in bytes), in addition to their number. The oldest
+// entries are evicted while the total cost exceeds maxCost.
+func WithWeigher[K comparable, V any](weigher func(key K, value V) int64, maxCost int64) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		c.weigher = weigher
+		c.maxCost = maxCost
+	}
+}
+
+// WithMemoryLimitFraction sizes the maximum cost of a cache WithWeigher as
+// a fraction of the runtime memory limit (GOMEMLIMIT), or as fallback while
+// no limit is set. The limit is read again every 1024 Adds or UpdateCosts
+// checking the cost, so changes made with debug.SetMemoryLimit are picked
+// up within as many operations. A fraction out of (0, 1] is ignored.
+func WithMemoryLimitFraction[K comparable, V any](fraction float64, fallback int64) Option[K, V] {
+	return func(c *unsafeCache[K, V]) {
+		if fraction <= 0 || fraction > 1 {
+			return
+		}
+		c.memoryFraction = fraction
+		c.memoryFallback = fallback
+		c.maxCost = memoryLimitCost(fraction, fallback)
+	}
+}
+
+// NewWithMemoryLimit creates a Cache sized in bytes, as computed by weigher,
+// as a fraction of the runtime memory limit instead of a number of entries,
+// or of fallback bytes while no limit is set, see WithMemoryLimitFraction.
+// It returns an error if fraction is out of (0, 1], fallback is not
+// positive or weigher is nil, which would leave the cache without room.
+func NewWithMemoryLimit[K comparable, V any](fraction float64, fallback int64, weigher func(key K, value V) int64, opts ...Option[K, V]) (*Cache[K, V], error) {
+	if fraction <= 0 || fraction > 1 {
+		return nil, fmt.Errorf("lru: memory limit fraction %v out of (0, 1]", fraction)
+	}
+	if fallback <= 0 {
+		return nil, fmt.Errorf("lru: memory limit fallback %d is not positive", fallback)
+	}
+	if weigher == nil {
+		return nil, errors.New("lru: memory limit without a weigher")
+	}
+	opts = append([]Option[K, V]{
+		WithUnlimited[K, V](),
+		WithWeigher(weigher, 0),
+		WithMemoryLimitFraction[K, V](fraction, fallback),
+	}, opts...)
+	return New[K, V](0, opts...), nil
+}
+
+// memoryLimitCost returns the fraction of the runtime memory limit,
+// or fallback without a limit.
+func memoryLimitCost(fraction float64, fallback int64) int64 {
+	limit := debug.SetMemoryLimit(-1)
+	if limit == math.MaxInt64 {
+		return fallback
+	}
+	return int64(float64(limit) * fraction)
+}
+
+func (c *unsafeCache[K, V]) Cost() int64 {
+	return c.cost
+}
+
+func (c *unsafeCache[K, V]) SetMaxCost(maxCost int64) (evicted int) {
+	c.memoryFraction = 0
+	c.maxCost = maxCost
+	if c.weigher == nil {
+		return 0
+	}
+	return c.evictOverCost()
+}
+
+// weigh returns the cost of an entry, zero without a weigher. It is
+// computed once per write, before the entry is checked and stored.
+func (c *unsafeCache[K, V]) weigh(key K, value V) int64 {
+	if c.weigher == nil {
+		return 0
+	}
+	return c.weigher(key, value)
+}
+
+// weighed updates the cost of an entry after its value was set.
+func (c *unsafeCache[K, V]) weighed(ent *entry[K, V], cost int64) {
+	c.cost += cost - ent.cost
+	ent.cost = cost
+}
+
+// evictOverCost evicts the oldest entries while the total cost
+// exceeds the maximum cost, and evictBatch-1 more if it had to.
+func (c *unsafeCache[K, V]) evictOverCost() (evicted int) {
+	if c.memoryFraction > 0 {
+		if c.adds++; c.adds%memoryLimitCheckInterval == 0 {
+			c.maxCost = memoryLimitCost(c.memoryFraction, c.memoryFallback)
+		}
+	}
+	for c.cost > c.maxCost && c.entries.Len() > 0 {
+		c.removeOldest()
+		evicted++
+	}
+	// Leave room for the next Adds, so that they do not evict one by one
+	for i := 1; evicted > 0 && i < c.evictBatch && c.entries.Len() > 0; i++ {
+		c.removeOldest()
+		evicted++
+	}
+	return evicted
+}
+
+func (c *unsafeCache[K, V]) UpdateCost(key K, cost int64) (ok bool) {
+	if c.weigher == nil {
+		return false
+	}
+	key = c.normalize(key)
+	elem, ok := c.bucket.get(key)
+	if !ok {
+		return false
+	}
+	ent := elem.Value
+	c.cost += cost - ent.cost
+	ent.cost = cost
+	if c.trimSignal != nil {
+		c.requestTrim()
+	} else {
+		c.evictOverCost()
+	}
+	if !c.noStats {
+		c.peak()
+	}
+	return true
+}
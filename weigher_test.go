@@ -0,0 +1,168 @@
+package lru
+
+import (
+	"math"
+	"runtime/debug"
+	"testing"
+)
+
+func Test_unsafeCache_WithWeigher(t *testing.T) {
+	c := NewUnsafeLru[string, string](100, WithWeigher(func(k, v string) int64 {
+		return int64(len(v))
+	}, 10))
+
+	c.Add("a", "1234")
+	c.Add("b", "1234")
+	if c.Cost() != 8 || c.Len() != 2 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 8, 2, c.Cost(), c.Len())
+	}
+
+	if evicted := c.Add("c", "1234"); !evicted {
+		t.Fatal("should evict")
+	}
+	if c.Contains("a") || c.Cost() != 8 {
+		t.Fatalf("Expected %v, got %v", 8, c.Cost())
+	}
+
+	// Growing an existing value also evicts
+	if evicted := c.Add("c", "12345678"); !evicted {
+		t.Fatal("should evict")
+	}
+	if c.Len() != 1 || c.Cost() != 8 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, 8, c.Len(), c.Cost())
+	}
+	if e, _ := c.PeekEntry("c"); e.Cost != 8 {
+		t.Fatalf("Expected %v, got %v", 8, e.Cost)
+	}
+
+	if evicted := c.SetMaxCost(4); evicted != 1 || c.Cost() != 0 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 1, 0, evicted, c.Cost())
+	}
+
+	c.Add("d", "12")
+	c.Remove("d")
+	if c.Cost() != 0 {
+		t.Fatalf("Expected %v, got %v", 0, c.Cost())
+	}
+}
+
+func TestNewWithMemoryLimit(t *testing.T) {
+	old := debug.SetMemoryLimit(1000)
+	defer debug.SetMemoryLimit(old)
+
+	c, err := NewWithMemoryLimit[int, []byte](0.1, 1<<20, func(k int, v []byte) int64 {
+		return int64(len(v))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		c.Add(i, make([]byte, 10))
+	}
+	if c.Len() != 10 || c.Cost() != 100 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 10, 100, c.Len(), c.Cost())
+	}
+
+	// The new limit is picked up by subsequent Adds
+	debug.SetMemoryLimit(math.MaxInt64)
+	for i := 0; i < memoryLimitCheckInterval; i++ {
+		c.Add(i, make([]byte, 10))
+	}
+	if c.Len() <= 10 {
+		t.Fatalf("Expected more than %v, got %v", 10, c.Len())
+	}
+}
+
+func TestNewWithMemoryLimit_Fallback(t *testing.T) {
+	old := debug.SetMemoryLimit(math.MaxInt64)
+	defer debug.SetMemoryLimit(old)
+
+	c, err := NewWithMemoryLimit[int, []byte](0.1, 50, func(k int, v []byte) int64 {
+		return int64(len(v))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		c.Add(i, make([]byte, 10))
+	}
+	if c.Len() != 5 || c.Cost() != 50 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 5, 50, c.Len(), c.Cost())
+	}
+}
+
+func TestNewWithMemoryLimit_Invalid(t *testing.T) {
+	weigher := func(k int, v []byte) int64 { return int64(len(v)) }
+	for _, tt := range []struct {
+		fraction float64
+		fallback int64
+		weigher  func(k int, v []byte) int64
+	}{
+		{0, 50, weigher},
+		{1.5, 50, weigher},
+		{0.1, 0, weigher},
+		{0.1, 50, nil},
+	} {
+		if _, err := NewWithMemoryLimit[int, []byte](tt.fraction, tt.fallback, tt.weigher); err == nil {
+			t.Fatalf("%v, %v: should fail", tt.fraction, tt.fallback)
+		}
+	}
+}
+
+func TestLru_UpdateCost(t *testing.T) {
+	l := New[int, []byte](10, WithWeigher(func(k int, v []byte) int64 { return int64(len(v)) }, 10))
+	l.Add(1, make([]byte, 3))
+	l.Add(2, make([]byte, 3))
+	if !l.UpdateCost(2, 5) || l.Cost() != 8 {
+		t.Fatalf("Expected %v, got %v", 8, l.Cost())
+	}
+
+	// Growing over the budget evicts the oldest entries
+	if !l.UpdateCost(2, 9) || l.Contains(1) || l.Cost() != 9 {
+		t.Fatalf("Expected %v, got %v", 9, l.Cost())
+	}
+	if l.UpdateCost(1, 1) {
+		t.Fatal("missing key should not be updated")
+	}
+
+	// The weigher applies again on update
+	l.Add(2, make([]byte, 2))
+	if l.Cost() != 2 {
+		t.Fatalf("Expected %v, got %v", 2, l.Cost())
+	}
+
+	u := New[int, int](10)
+	u.Add(1, 1)
+	if u.UpdateCost(1, 5) || u.Cost() != 0 {
+		t.Fatal("cost should not be updated without a weigher")
+	}
+}
+
+func Test_unsafeCache_WithWeigherEvictionBatch(t *testing.T) {
+	c := NewUnsafeLru[int, string](100,
+		WithWeigher(func(k int, v string) int64 {
+			return int64(len(v))
+		}, 10),
+		WithEvictionBatch[int, string](3),
+	)
+	for i := 0; i < 5; i++ {
+		c.Add(i, "12")
+	}
+
+	// Making room for 1 entry evicts 2 more
+	if evicted := c.Add(5, "12"); !evicted {
+		t.Fatal("should evict")
+	}
+	if c.Len() != 3 || c.Cost() != 6 {
+		t.Fatalf("Expected %v, %v, got %v, %v", 3, 6, c.Len(), c.Cost())
+	}
+	if c.Contains(2) || !c.Contains(3) {
+		t.Fatal("the 3 oldest entries should be evicted")
+	}
+
+	// The next Adds fit
+	c.Add(6, "12")
+	if evicted := c.Add(7, "12"); evicted || c.Len() != 5 {
+		t.Fatalf("Expected %v, %v, got %v, %v", false, 5, evicted, c.Len())
+	}
+}
@@ -0,0 +1,54 @@
+package lru
+
+import (
+	"context"
+	"fmt"
+)
+
+// WriteThroughMode decides when a WriteThroughCache refreshes an entry.
+type WriteThroughMode int
+
+const (
+	// RefreshOnSuccess writes to the store first, and refreshes the value,
+	// recency and TTL of the entry only once the store accepted it, so that
+	// a failed write never serves a value the store does not have.
+	RefreshOnSuccess WriteThroughMode = iota
+
+	// RefreshFirst refreshes the entry before writing to the store, making
+	// the value visible sooner, and removes it if the write fails.
+	RefreshFirst
+)
+
+// NewWriteThrough creates a WriteThroughCache writing to the store with write.
+func NewWriteThrough[K comparable, V any](cache Cacher[K, V], write func(ctx context.Context, key K, value V) error, mode WriteThroughMode) *WriteThroughCache[K, V] {
+	return &WriteThroughCache[K, V]{Cacher: cache, write: write, mode: mode}
+}
+
+// WriteThroughCache keeps a cache consistent with a backing store by writing
+// the values to both. It is as safe for concurrent access as its cache,
+// concurrent Sets of the same key are not ordered.
+type WriteThroughCache[K comparable, V any] struct {
+	Cacher[K, V]
+
+	write func(ctx context.Context, key K, value V) error
+	mode  WriteThroughMode
+}
+
+// Set writes the value to the store and the cache, according to the mode.
+// The error of the store is wrapped.
+func (c *WriteThroughCache[K, V]) Set(ctx context.Context, key K, value V) error {
+	if c.mode == RefreshFirst {
+		c.Add(key, value)
+		if err := c.write(ctx, key, value); err != nil {
+			c.Remove(key)
+			return fmt.Errorf("lru: write through: %w", err)
+		}
+		return nil
+	}
+
+	if err := c.write(ctx, key, value); err != nil {
+		return fmt.Errorf("lru: write through: %w", err)
+	}
+	c.Add(key, value)
+	return nil
+}
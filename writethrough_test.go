@@ -0,0 +1,52 @@
+package lru
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWriteThroughCache(t *testing.T) {
+	errStore := errors.New("store down")
+	store := make(map[string]int)
+	var failing bool
+	write := func(ctx context.Context, key string, value int) error {
+		if failing {
+			return errStore
+		}
+		store[key] = value
+		return nil
+	}
+	ctx := context.Background()
+
+	for _, mode := range []WriteThroughMode{RefreshOnSuccess, RefreshFirst} {
+		failing = false
+		l := New[string, int](2)
+		c := NewWriteThrough[string, int](l, write, mode)
+
+		if err := c.Set(ctx, "a", 1); err != nil {
+			t.Fatal(err)
+		}
+		c.Set(ctx, "b", 2)
+		if v, _ := c.Get("a"); v != 1 || store["a"] != 1 {
+			t.Fatalf("mode %v: bad value %v", mode, v)
+		}
+
+		failing = true
+		if err := c.Set(ctx, "b", 3); !errors.Is(err, errStore) {
+			t.Fatalf("mode %v: Expected %v, got %v", mode, errStore, err)
+		}
+		switch mode {
+		case RefreshOnSuccess:
+			// Neither the value nor the recency of b were refreshed
+			if v, _ := c.Peek("b"); v != 2 || !reflect.DeepEqual(l.Keys(), []string{"b", "a"}) {
+				t.Fatalf("b should be unchanged: %v %v", v, l.Keys())
+			}
+		case RefreshFirst:
+			if c.Contains("b") {
+				t.Fatal("b should be removed")
+			}
+		}
+	}
+}